@@ -7,19 +7,24 @@ SPDX-License-Identifier: Apache-2.0
 package channel
 
 import (
+	reqContext "context"
 	"time"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/errors/retry"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 )
 
-// CCEvent contains the data for a chaincocde event
+// CCEvent contains the data for a chaincocde event.
+// The payload is only available if the event service receives full blocks.
 type CCEvent struct {
 	TxID        string
 	ChaincodeID string
 	EventName   string
 	Payload     []byte
+	BlockNumber uint64
 }
 
 // Registration is a handle that is returned from a successful Register Chaincode Event.
@@ -32,6 +37,8 @@ type opts struct {
 	ProposalProcessors []fab.ProposalProcessor // targets
 	Timeout            time.Duration
 	Retry              retry.Opts
+	ParentContext      reqContext.Context
+	Identity           context.Identity // signing identity, if other than the client's
 }
 
 //Option func for each Opts argument
@@ -43,18 +50,26 @@ type Request struct {
 	Fcn          string
 	Args         [][]byte
 	TransientMap map[string][]byte
+	// Collections names the private data collections used by the request. If set, the request
+	// is only endorsed by peers of organizations that are members of all the collections
+	Collections []string
 }
 
-//Response contains response parameters for query and execute an invocation transaction
+//Response contains response parameters for query and execute an invocation transaction.
+//RWSet is the decoded read/write set and chaincode event of the first endorsement.
+//Attempts is the number of times the request was executed, including retries
 type Response struct {
 	Payload          []byte
 	TransactionID    fab.TransactionID
 	TxValidationCode pb.TxValidationCode
 	Proposal         *fab.TransactionProposal
 	Responses        []*fab.TransactionProposalResponse
+	RWSet            *txn.TxRWSet
+	Attempts         int
 }
 
-//WithTimeout encapsulates time.Duration to Option
+//WithTimeout encapsulates time.Duration to Option.
+//The timeout is applied as a deadline on the request context
 func WithTimeout(timeout time.Duration) Option {
 	return func(o *opts) error {
 		o.Timeout = timeout
@@ -70,7 +85,29 @@ func WithProposalProcessor(proposalProcessors ...fab.ProposalProcessor) Option {
 	}
 }
 
-// WithRetry option to configure retries
+//WithParentContext encapsulates a parent context to Option.
+//Cancelling the parent context aborts the request, including in-flight calls to peers and orderers
+func WithParentContext(parentContext reqContext.Context) Option {
+	return func(o *opts) error {
+		o.ParentContext = parentContext
+		return nil
+	}
+}
+
+//WithIdentity encapsulates the identity that creates and signs the transaction to Option.
+//The request reuses the discovery, selection, membership and event connections of the client,
+//which are bound to the identity the client was created with
+func WithIdentity(identity context.Identity) Option {
+	return func(o *opts) error {
+		o.Identity = identity
+		return nil
+	}
+}
+
+// WithRetry option to configure retries.
+// Transactions invalidated on commit with one of the event server codes of the retryable codes,
+// which by default include MVCC and phantom read conflicts, are re-endorsed and re-submitted.
+// Set RetryableTxValidationCodes (e.g. retry.ConflictTxValidationCodes) to select these codes
 func WithRetry(retryOpt retry.Opts) Option {
 	return func(o *opts) error {
 		o.Retry = retryOpt
@@ -8,7 +8,7 @@ SPDX-License-Identifier: Apache-2.0
 package channel
 
 import (
-	"reflect"
+	reqContext "context"
 	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/invoke"
@@ -35,13 +35,15 @@ const (
 // An application that requires interaction with multiple channels should create a separate
 // instance of the channel client for each channel. Channel client supports non-admin functions only.
 type Client struct {
-	context    core.Providers
-	discovery  fab.DiscoveryService
-	selection  fab.SelectionService
-	membership fab.ChannelMembership
-	transactor fab.Transactor
-	eventHub   fab.EventHub
-	greylist   *greylist.Filter
+	context      core.Providers
+	discovery    fab.DiscoveryService
+	selection    fab.SelectionService
+	membership   fab.ChannelMembership
+	transactor   fab.Transactor
+	eventService fab.EventService
+	greylist     *greylist.Filter
+	interceptors []invoke.Interceptor
+	collConfig   invoke.CCCollectionConfigProvider
 }
 
 // Context holds the providers and services needed to create a Client.
@@ -52,14 +54,48 @@ type Context struct {
 	ChannelService   fab.ChannelService
 }
 
-// New returns a Client instance.
-func New(c Context) (*Client, error) {
-	greylistProvider := greylist.New(c.Config().TimeoutOrDefault(core.DiscoveryGreylistExpiry))
+// ClientOption describes a functional parameter for the New constructor
+type ClientOption func(*Client) error
 
-	eventHub, err := c.ChannelService.EventHub()
-	if err != nil {
-		return nil, errors.WithMessage(err, "event hub creation failed")
+// WithInterceptors registers interceptors that are notified around every stage of the
+// invoke chain for all Query, Execute and InvokeHandler calls of the client.
+// Interceptors are notified in the order they are registered (see invoke.Interceptor).
+func WithInterceptors(interceptors ...invoke.Interceptor) ClientOption {
+	return func(cc *Client) error {
+		cc.interceptors = append(cc.interceptors, interceptors...)
+		return nil
+	}
+}
+
+// WithCollectionConfigProvider sets the provider of the chaincodes' private data collection configuration.
+// It is required for requests that name private data collections, which are only endorsed by peers
+// of organizations that are members of the collections.
+func WithCollectionConfigProvider(provider invoke.CCCollectionConfigProvider) ClientOption {
+	return func(cc *Client) error {
+		cc.collConfig = provider
+		return nil
 	}
+}
+
+// WithLSCCCollectionConfig retrieves the chaincodes' private data collection configuration from the
+// lifecycle system chaincode (LSCC) of the channel, with queries of the client.
+func WithLSCCCollectionConfig() ClientOption {
+	return func(cc *Client) error {
+		cc.collConfig = invoke.NewLSCCCollectionConfigProvider(func(request invoke.Request) ([]byte, error) {
+			response, err := cc.Query(Request(request))
+			if err != nil {
+				return nil, err
+			}
+			return response.Payload, nil
+		})
+		return nil
+	}
+}
+
+// New returns a Client instance.
+// The client connects to the event service of the channel on the first Execute or event registration.
+func New(c Context, opts ...ClientOption) (*Client, error) {
+	greylistProvider := greylist.New(c.Config().TimeoutOrDefault(core.DiscoveryGreylistExpiry))
 
 	transactor, err := c.ChannelService.Transactor()
 	if err != nil {
@@ -72,13 +108,20 @@ func New(c Context) (*Client, error) {
 	}
 
 	channelClient := Client{
-		greylist:   greylistProvider,
-		context:    c,
-		discovery:  discovery.NewDiscoveryFilterService(c.DiscoveryService, greylistProvider),
-		selection:  c.SelectionService,
-		membership: membership,
-		transactor: transactor,
-		eventHub:   eventHub,
+		greylist:     greylistProvider,
+		context:      c,
+		discovery:    discovery.NewDiscoveryFilterService(c.DiscoveryService, greylistProvider),
+		selection:    c.SelectionService,
+		membership:   membership,
+		transactor:   transactor,
+		eventService: newLazyEventService(c.ChannelService),
+	}
+
+	for _, opt := range opts {
+		err := opt(&channelClient)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to apply client option")
+		}
 	}
 
 	return &channelClient, nil
@@ -94,37 +137,73 @@ func (cc *Client) Execute(request Request, options ...Option) (Response, error)
 	return cc.InvokeHandler(invoke.NewExecuteHandler(), request, cc.addDefaultTimeout(core.Execute, options...)...)
 }
 
+// ExecuteAsync prepares and executes transaction using request and optional options provided.
+// It returns as soon as the transaction has been accepted by the orderer; the returned Commit
+// delivers the validation code and block number once the transaction is committed.
+func (cc *Client) ExecuteAsync(request Request, options ...Option) (Response, *invoke.Commit, error) {
+	requestContext, err := cc.invokeHandler(invoke.NewExecuteAsyncHandler(), request, cc.addDefaultTimeout(core.Execute, options...)...)
+	if err != nil {
+		return Response{}, nil, err
+	}
+	if requestContext.Error != nil {
+		return Response(requestContext.Response), nil, requestContext.Error
+	}
+	if requestContext.Commit == nil {
+		return Response(requestContext.Response), nil, errors.New("transaction was not sent to the orderer")
+	}
+	return Response(requestContext.Response), requestContext.Commit, nil
+}
+
 //InvokeHandler invokes handler using request and options provided
 func (cc *Client) InvokeHandler(handler invoke.Handler, request Request, options ...Option) (Response, error) {
+	requestContext, err := cc.invokeHandler(handler, request, options...)
+	if err != nil {
+		return Response{}, err
+	}
+	return Response(requestContext.Response), requestContext.Error
+}
+
+//invokeHandler invokes handler using request and options provided and returns the resulting request context
+func (cc *Client) invokeHandler(handler invoke.Handler, request Request, options ...Option) (*invoke.RequestContext, error) {
 	//Read execute tx options
 	txnOpts, err := cc.prepareOptsFromOptions(options...)
 	if err != nil {
-		return Response{}, err
+		return nil, err
 	}
 
 	//Prepare context objects for handler
 	requestContext, clientContext, err := cc.prepareHandlerContexts(request, txnOpts)
 	if err != nil {
-		return Response{}, err
+		return nil, err
 	}
 
-	complete := make(chan bool)
+	reqCtx, cancel := reqContext.WithTimeout(requestContext.Opts.ParentContext, requestContext.Opts.Timeout)
+	defer cancel()
+	requestContext.Ctx = reqCtx
+
+	complete := make(chan bool, 1)
 
 	go func() {
+		attempts := 0
 	handleInvoke:
 		//Perform action through handler
-		handler.Handle(requestContext, clientContext)
-		if cc.resolveRetry(requestContext, txnOpts) {
+		attempts++
+		invoke.Delegate(handler, requestContext, clientContext)
+		if reqCtx.Err() == nil && cc.resolveRetry(requestContext, txnOpts) {
 			goto handleInvoke
 		}
+		requestContext.Response.Attempts = attempts
 		complete <- true
 	}()
 	select {
 	case <-complete:
-		return Response(requestContext.Response), requestContext.Error
-	case <-time.After(requestContext.Opts.Timeout):
-		return Response{}, status.New(status.ClientStatus, status.Timeout.ToInt32(),
-			"request timed out", nil)
+		return requestContext, nil
+	case <-reqCtx.Done():
+		if reqCtx.Err() == reqContext.DeadlineExceeded {
+			return nil, status.New(status.ClientStatus, status.Timeout.ToInt32(),
+				"request timed out", nil)
+		}
+		return nil, errors.Wrap(reqCtx.Err(), "request cancelled")
 	}
 }
 
@@ -134,7 +213,7 @@ func (cc *Client) resolveRetry(ctx *invoke.RequestContext, o opts) bool {
 		errs = append(errs, ctx.Error)
 	}
 	for _, e := range errs {
-		if ctx.RetryHandler.Required(e) {
+		if retryRequired(ctx, e) {
 			logger.Infof("Retrying on error %s", e)
 			cc.greylist.Greylist(e)
 
@@ -149,6 +228,15 @@ func (cc *Client) resolveRetry(ctx *invoke.RequestContext, o opts) bool {
 	return false
 }
 
+//retryRequired cuts the backoff of the retry handler short once the request context is done,
+//if the handler supports it
+func retryRequired(ctx *invoke.RequestContext, err error) bool {
+	if handler, ok := ctx.RetryHandler.(retry.ContextHandler); ok {
+		return handler.RequiredWithContext(ctx.Ctx, err)
+	}
+	return ctx.RetryHandler.Required(err)
+}
+
 //prepareHandlerContexts prepares context objects for handlers
 func (cc *Client) prepareHandlerContexts(request Request, o opts) (*invoke.RequestContext, *invoke.ClientContext, error) {
 
@@ -157,11 +245,13 @@ func (cc *Client) prepareHandlerContexts(request Request, o opts) (*invoke.Reque
 	}
 
 	clientContext := &invoke.ClientContext{
-		Selection:  cc.selection,
-		Discovery:  cc.discovery,
-		Membership: cc.membership,
-		Transactor: cc.transactor,
-		EventHub:   cc.eventHub,
+		Selection:        cc.selection,
+		Discovery:        cc.discovery,
+		Membership:       cc.membership,
+		Transactor:       cc.transactor,
+		EventService:     cc.eventService,
+		Interceptors:     cc.interceptors,
+		CollectionConfig: cc.collConfig,
 	}
 
 	requestContext := &invoke.RequestContext{
@@ -175,6 +265,10 @@ func (cc *Client) prepareHandlerContexts(request Request, o opts) (*invoke.Reque
 		requestContext.Opts.Timeout = defaultHandlerTimeout
 	}
 
+	if requestContext.Opts.ParentContext == nil {
+		requestContext.Opts.ParentContext = reqContext.Background()
+	}
+
 	return requestContext, clientContext, nil
 }
 
@@ -203,12 +297,13 @@ func (cc *Client) addDefaultTimeout(timeOutType core.TimeoutType, options ...Opt
 	return options
 }
 
-// Close releases channel client resources (disconnects event hub etc.)
+// Close releases channel client resources.
+// The event service is shared by the clients of the channel and is closed once no client uses it.
+// Registrations of the client should be unregistered before closing it.
 func (cc *Client) Close() error {
-	if cc.eventHub.IsConnected() == true {
-		return cc.eventHub.Disconnect()
+	if eventService, ok := cc.eventService.(closable); ok {
+		eventService.Close()
 	}
-
 	return nil
 }
 
@@ -217,31 +312,28 @@ func (cc *Client) Close() error {
 // @returns {object} object handle that should be used to unregister
 func (cc *Client) RegisterChaincodeEvent(notify chan<- *CCEvent, chainCodeID string, eventID string) (Registration, error) {
 
-	if cc.eventHub.IsConnected() == false {
-		if err := cc.eventHub.Connect(); err != nil {
-			return nil, errors.WithMessage(err, "Event hub failed to connect")
-		}
+	reg, eventch, err := cc.eventService.RegisterChaincodeEvent(chainCodeID, eventID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "event service registration failed")
 	}
 
-	// Register callback for CE
-	rce := cc.eventHub.RegisterChaincodeEvent(chainCodeID, eventID, func(ce *fab.ChaincodeEvent) {
-		notify <- &CCEvent{ChaincodeID: ce.ChaincodeID, EventName: ce.EventName, TxID: ce.TxID, Payload: ce.Payload}
-	})
+	// Forward the chaincode events until the registration is removed
+	go func() {
+		for ce := range eventch {
+			notify <- &CCEvent{ChaincodeID: ce.ChaincodeID, EventName: ce.EventName, TxID: ce.TxID, Payload: ce.Payload, BlockNumber: ce.BlockNumber}
+		}
+	}()
 
-	return rce, nil
+	return reg, nil
 }
 
 // UnregisterChaincodeEvent removes chain code event registration
 func (cc *Client) UnregisterChaincodeEvent(registration Registration) error {
 
-	switch regType := registration.(type) {
-
-	case *fab.ChainCodeCBE:
-		cc.eventHub.UnregisterChaincodeEvent(regType)
-	default:
-		return errors.Errorf("Unsupported registration type: %v", reflect.TypeOf(registration))
+	if registration == nil {
+		return errors.New("registration is required")
 	}
+	cc.eventService.Unregister(registration)
 
 	return nil
-
 }
@@ -7,10 +7,12 @@ SPDX-License-Identifier: Apache-2.0
 package channel
 
 import (
+	reqContext "context"
 	"fmt"
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 
@@ -251,7 +253,7 @@ type customEndorsementHandler struct {
 }
 
 func (h *customEndorsementHandler) Handle(requestContext *invoke.RequestContext, clientContext *invoke.ClientContext) {
-	transactionProposalResponses, txnID, err := createAndSendTestTransactionProposal(h.transactor, &requestContext.Request, requestContext.Opts.ProposalProcessors)
+	transactionProposalResponses, txnID, err := createAndSendTestTransactionProposal(requestContext.Ctx, h.transactor, &requestContext.Request, requestContext.Opts.ProposalProcessors)
 
 	requestContext.Response.TransactionID = txnID
 
@@ -353,7 +355,7 @@ func TestOrdererStatusError(t *testing.T) {
 	testOrderer1 := fcmocks.NewMockOrderer("", make(chan *fab.SignedEnvelope))
 	orderers := []fab.Orderer{testOrderer1}
 	chClient := setupChannelClientWithNodes(peers, orderers, t)
-	chClient.eventHub = fcmocks.NewMockEventHub()
+	chClient.eventService = fcmocks.NewMockEventService()
 
 	mockOrderer, ok := testOrderer1.(fcmocks.MockOrderer)
 	assert.True(t, ok, "Expected object to be mock orderer")
@@ -373,22 +375,21 @@ func TestOrdererStatusError(t *testing.T) {
 
 func TestTransactionValidationError(t *testing.T) {
 	validationCode := pb.TxValidationCode_BAD_RWSET
-	mockEventHub := fcmocks.NewMockEventHub()
+	mockEventService := fcmocks.NewMockEventService()
 	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
 	peers := []fab.Peer{testPeer1}
 
 	go func() {
 		select {
-		case callback := <-mockEventHub.RegisteredTxCallbacks:
-			callback("txid", validationCode,
-				status.New(status.EventServerStatus, int32(validationCode), "test", nil))
+		case txStatusReg := <-mockEventService.TxStatusRegCh:
+			txStatusReg.Eventch <- &fab.TxStatusEvent{TxID: txStatusReg.TxID, TxValidationCode: validationCode}
 		case <-time.After(time.Second * 5):
-			t.Fatal("Timed out waiting for execute Tx to register event callback")
+			t.Fatal("Timed out waiting for execute Tx to register for tx status event")
 		}
 	}()
 
 	chClient := setupChannelClient(peers, t)
-	chClient.eventHub = mockEventHub
+	chClient.eventService = mockEventService
 	response, err := chClient.Execute(Request{ChaincodeID: "test", Fcn: "invoke",
 		Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}})
 	assert.Nil(t, response.Payload, "Expected nil result on failed execute operation")
@@ -398,6 +399,165 @@ func TestTransactionValidationError(t *testing.T) {
 	assert.EqualValues(t, validationCode, status.ToTransactionValidationCode(statusError.Code))
 }
 
+func TestExecuteAsync(t *testing.T) {
+	mockEventService := fcmocks.NewMockEventService()
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	chClient := setupChannelClient([]fab.Peer{testPeer1}, t)
+	chClient.eventService = mockEventService
+
+	response, commit, err := chClient.ExecuteAsync(Request{ChaincodeID: "test", Fcn: "invoke",
+		Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}})
+	assert.Nil(t, err, "expected execute async to succeed")
+	assert.NotNil(t, commit, "expected commit handle")
+	assert.Equal(t, response.TransactionID, commit.TxnID, "expected commit for the executed transaction")
+
+	select {
+	case txStatusReg := <-mockEventService.TxStatusRegCh:
+		txStatusReg.Eventch <- &fab.TxStatusEvent{TxID: txStatusReg.TxID, TxValidationCode: pb.TxValidationCode_VALID}
+	case <-time.After(time.Second * 5):
+		t.Fatal("Timed out waiting for execute async to register for tx status event")
+	}
+
+	select {
+	case s := <-commit.Done():
+		assert.Nil(t, s.Error, "expected successful commit")
+		assert.Equal(t, pb.TxValidationCode_VALID, s.TxValidationCode)
+	case <-time.After(time.Second * 5):
+		t.Fatal("Timed out waiting for commit status")
+	}
+}
+
+func TestExecuteAsyncCancel(t *testing.T) {
+	mockEventService := fcmocks.NewMockEventService()
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	chClient := setupChannelClient([]fab.Peer{testPeer1}, t)
+	chClient.eventService = mockEventService
+
+	_, commit, err := chClient.ExecuteAsync(Request{ChaincodeID: "test", Fcn: "invoke",
+		Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}})
+	assert.Nil(t, err, "expected execute async to succeed")
+
+	commit.Cancel()
+	s := commit.Wait()
+	assert.NotNil(t, s.Error, "expected error for cancelled commit")
+
+	// Late status must not block the event service
+	txStatusReg := <-mockEventService.TxStatusRegCh
+	txStatusReg.Eventch <- &fab.TxStatusEvent{TxID: txStatusReg.TxID, TxValidationCode: pb.TxValidationCode_VALID}
+}
+
+func TestExecuteTxTimeout(t *testing.T) {
+	mockEventService := fcmocks.NewMockEventService()
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	chClient := setupChannelClient([]fab.Peer{testPeer1}, t)
+	chClient.eventService = mockEventService
+
+	_, err := chClient.Execute(Request{ChaincodeID: "test", Fcn: "invoke",
+		Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}}, WithTimeout(100*time.Millisecond))
+	assert.NotNil(t, err, "expected timeout error")
+	s, ok := status.FromError(err)
+	assert.True(t, ok, "expected status error")
+	assert.EqualValues(t, status.Timeout.ToInt32(), s.Code, "expected timeout error")
+
+	// The handler must have given up waiting for the tx status event
+	select {
+	case <-mockEventService.TxStatusRegCh:
+	case <-time.After(time.Second * 5):
+		t.Fatal("Timed out waiting for execute to register for tx status event")
+	}
+}
+
+func TestExecuteTxParentContextCancel(t *testing.T) {
+	mockEventService := fcmocks.NewMockEventService()
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	chClient := setupChannelClient([]fab.Peer{testPeer1}, t)
+	chClient.eventService = mockEventService
+
+	parentCtx, cancel := reqContext.WithCancel(reqContext.Background())
+	go func() {
+		<-mockEventService.TxStatusRegCh
+		cancel()
+	}()
+
+	_, err := chClient.Execute(Request{ChaincodeID: "test", Fcn: "invoke",
+		Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}}, WithParentContext(parentCtx))
+	assert.NotNil(t, err, "expected cancellation error")
+	assert.Equal(t, reqContext.Canceled, errors.Cause(err), "expected context cancelled error")
+}
+
+type recordingInterceptor struct {
+	name    string
+	events  *[]string
+	reject  string
+	elapsed map[string]time.Duration
+}
+
+func (i *recordingInterceptor) Before(stage string, requestContext *invoke.RequestContext, clientContext *invoke.ClientContext) error {
+	*i.events = append(*i.events, i.name+":before:"+stage)
+	if stage == i.reject {
+		return errors.New("rejected")
+	}
+	return nil
+}
+
+func (i *recordingInterceptor) After(stage string, requestContext *invoke.RequestContext, clientContext *invoke.ClientContext, elapsed time.Duration) {
+	*i.events = append(*i.events, i.name+":after:"+stage)
+	i.elapsed[stage] = elapsed
+}
+
+func TestQueryWithInterceptors(t *testing.T) {
+	chClient := setupChannelClient(nil, t)
+
+	var events []string
+	first := &recordingInterceptor{name: "first", events: &events, elapsed: make(map[string]time.Duration)}
+	second := &recordingInterceptor{name: "second", events: &events, elapsed: make(map[string]time.Duration)}
+	err := WithInterceptors(first, second)(chClient)
+	assert.Nil(t, err, "expected interceptors to be registered")
+
+	_, err = chClient.Query(Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}})
+	if err != nil {
+		t.Fatalf("Failed to invoke test cc: %s", err)
+	}
+
+	expected := []string{
+		"first:before:ProposalProcessorHandler", "second:before:ProposalProcessorHandler",
+		"first:before:EndorsementHandler", "second:before:EndorsementHandler",
+		"first:before:EndorsementValidationHandler", "second:before:EndorsementValidationHandler",
+		"first:before:SignatureValidationHandler", "second:before:SignatureValidationHandler",
+		"second:after:SignatureValidationHandler", "first:after:SignatureValidationHandler",
+		"second:after:EndorsementValidationHandler", "first:after:EndorsementValidationHandler",
+		"second:after:EndorsementHandler", "first:after:EndorsementHandler",
+		"second:after:ProposalProcessorHandler", "first:after:ProposalProcessorHandler",
+	}
+	assert.Equal(t, expected, events, "unexpected interceptor notifications")
+	assert.True(t, first.elapsed["ProposalProcessorHandler"] >= first.elapsed["EndorsementHandler"], "expected elapsed time to include nested stages")
+
+	// Rejecting a stage fails the request and skips the rest of the chain
+	events = nil
+	first.reject = "EndorsementValidationHandler"
+	_, err = chClient.Query(Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}})
+	assert.NotNil(t, err, "expected interceptor to reject the request")
+	assert.Equal(t, "first:before:EndorsementValidationHandler", events[4])
+	assert.Equal(t, "first:after:EndorsementValidationHandler", events[5], "expected second interceptor and stage to be skipped")
+}
+
+func TestQueryWithIdentity(t *testing.T) {
+	chClient := setupChannelClient(nil, t)
+
+	response, err := chClient.Query(Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}})
+	if err != nil {
+		t.Fatalf("Failed to invoke test cc: %s", err)
+	}
+	assert.Nil(t, response.Proposal.Signer, "expected proposal to be signed by the identity of the client")
+
+	identity := fcmocks.NewMockUserWithMSPID("other", "Org2MSP")
+	response, err = chClient.Query(Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}}, WithIdentity(identity))
+	if err != nil {
+		t.Fatalf("Failed to invoke test cc: %s", err)
+	}
+	assert.Equal(t, identity, response.Proposal.Signer, "expected proposal to be signed by the given identity")
+}
+
 func TestExecuteTxWithRetries(t *testing.T) {
 	testStatus := status.New(status.EndorserClientStatus, status.ConnectionFailed.ToInt32(), "test", nil)
 	testResp := []byte("test")
@@ -428,6 +588,160 @@ func TestExecuteTxWithRetries(t *testing.T) {
 	assert.Equal(t, testResp, resp.Payload, "expected correct response")
 }
 
+//requiredOnlyHandler is a retry handler that doesn't implement retry.ContextHandler
+type requiredOnlyHandler struct {
+	calls int
+}
+
+func (h *requiredOnlyHandler) Required(err error) bool {
+	h.calls++
+	return true
+}
+
+func TestRetryRequiredWithoutContextHandler(t *testing.T) {
+	handler := &requiredOnlyHandler{}
+	ctx, cancel := reqContext.WithCancel(reqContext.Background())
+	cancel()
+
+	requestContext := &invoke.RequestContext{RetryHandler: handler, Ctx: ctx}
+	assert.True(t, retryRequired(requestContext, errors.New("test")), "expected handler without context support to decide")
+	assert.Equal(t, 1, handler.calls)
+
+	// Handlers of the retry package end with the context
+	requestContext.RetryHandler = retry.New(retry.Opts{Attempts: 1, InitialBackoff: time.Minute, MaxBackoff: time.Minute, BackoffFactor: 1, RetryableCodes: retry.ChannelClientRetryableCodes})
+	err := status.New(status.EndorserClientStatus, status.ConnectionFailed.ToInt32(), "test", nil)
+	assert.False(t, retryRequired(requestContext, err), "expected no retry once the context is done")
+}
+
+func TestExecuteTxWithTxValidationRetry(t *testing.T) {
+	mockEventService := fcmocks.NewMockEventService()
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	chClient := setupChannelClient([]fab.Peer{testPeer1}, t)
+	chClient.eventService = mockEventService
+
+	retryOpts := retry.Opts{
+		Attempts:                   3,
+		BackoffFactor:              1,
+		InitialBackoff:             time.Millisecond * 1,
+		MaxBackoff:                 time.Second * 1,
+		RetryableTxValidationCodes: retry.ConflictTxValidationCodes,
+	}
+
+	go func() {
+		// First attempt is invalidated by an MVCC read conflict
+		txStatusReg := <-mockEventService.TxStatusRegCh
+		txStatusReg.Eventch <- &fab.TxStatusEvent{TxID: txStatusReg.TxID, TxValidationCode: pb.TxValidationCode_MVCC_READ_CONFLICT}
+		txStatusReg = <-mockEventService.TxStatusRegCh
+		txStatusReg.Eventch <- &fab.TxStatusEvent{TxID: txStatusReg.TxID, TxValidationCode: pb.TxValidationCode_VALID}
+	}()
+
+	resp, err := chClient.Execute(Request{ChaincodeID: "test", Fcn: "invoke",
+		Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}}, WithRetry(retryOpts))
+	assert.Nil(t, err, "expected execute to succeed after retry")
+	assert.Equal(t, 2, resp.Attempts, "expected two attempts")
+	assert.Equal(t, 2, testPeer1.ProcessProposalCalls, "expected transaction to be endorsed twice")
+	assert.Equal(t, pb.TxValidationCode_VALID, resp.TxValidationCode)
+}
+
+func TestRegisterChaincodeEvent(t *testing.T) {
+	mockEventService := fcmocks.NewMockEventService()
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	chClient := setupChannelClient([]fab.Peer{testPeer1}, t)
+	chClient.eventService = mockEventService
+
+	notifier := make(chan *CCEvent, 1)
+	reg, err := chClient.RegisterChaincodeEvent(notifier, "testCC", "test([a-zA-Z]+)")
+	assert.Nil(t, err, "expected chaincode event registration to succeed")
+
+	var ccReg *fcmocks.CCReg
+	select {
+	case ccReg = <-mockEventService.CCRegCh:
+	case <-time.After(time.Second * 5):
+		t.Fatal("Timed out waiting for chaincode event registration")
+	}
+	assert.Equal(t, "testCC", ccReg.ChaincodeID)
+	assert.Equal(t, "test([a-zA-Z]+)", ccReg.EventFilter)
+
+	ccReg.Eventch <- &fab.CCEvent{TxID: "txid", ChaincodeID: "testCC", EventName: "testEvent", BlockNumber: 3}
+
+	select {
+	case event := <-notifier:
+		assert.Equal(t, "txid", event.TxID)
+		assert.Equal(t, "testEvent", event.EventName)
+		assert.EqualValues(t, 3, event.BlockNumber)
+	case <-time.After(time.Second * 5):
+		t.Fatal("Timed out waiting for chaincode event")
+	}
+
+	err = chClient.UnregisterChaincodeEvent(reg)
+	assert.Nil(t, err, "expected unregister to succeed")
+	assert.Equal(t, reg, <-mockEventService.Unregistered)
+
+	_, err = chClient.RegisterChaincodeEvent(notifier, "", "test")
+	assert.NotNil(t, err, "expected error for missing chaincode ID")
+}
+
+// countingChannelService counts the acquisitions of the event service
+type countingChannelService struct {
+	fab.ChannelService
+	eventServices int
+}
+
+func (cs *countingChannelService) EventService() (fab.EventService, error) {
+	cs.eventServices++
+	return cs.ChannelService.EventService()
+}
+
+func TestEventServiceConnectedOnFirstUse(t *testing.T) {
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	chClient := setupChannelClient([]fab.Peer{testPeer1}, t)
+	channelService := &countingChannelService{ChannelService: chClient.context.(Context).ChannelService}
+	mockEventService := fcmocks.NewMockEventService()
+	channelService.ChannelService.(*fcmocks.MockChannelService).SetEventService(mockEventService)
+	chClient.eventService = newLazyEventService(channelService)
+
+	_, err := chClient.Query(Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}})
+	assert.Nil(t, err, "expected query to succeed")
+	assert.Equal(t, 0, channelService.eventServices, "expected query not to acquire the event service")
+
+	notifier := make(chan *CCEvent, 1)
+	reg, err := chClient.RegisterChaincodeEvent(notifier, "testCC", "test")
+	assert.Nil(t, err, "expected chaincode event registration to succeed")
+	_, err = chClient.RegisterChaincodeEvent(notifier, "testCC", "test")
+	assert.Nil(t, err, "expected chaincode event registration to succeed")
+	assert.Equal(t, 1, channelService.eventServices, "expected event service to be acquired once")
+	assert.Nil(t, chClient.UnregisterChaincodeEvent(reg))
+
+	assert.Nil(t, chClient.Close())
+	assert.True(t, mockEventService.Closed, "expected event service to be released")
+
+	_, err = chClient.RegisterChaincodeEvent(notifier, "testCC", "test")
+	assert.NotNil(t, err, "expected registration to fail once the client is closed")
+}
+
+func TestLSCCCollectionConfig(t *testing.T) {
+	collConfig := &common.CollectionConfigPackage{Config: []*common.CollectionConfig{
+		{Payload: &common.CollectionConfig_StaticCollectionConfig{StaticCollectionConfig: &common.StaticCollectionConfig{Name: "coll1"}}},
+	}}
+	payload, err := proto.Marshal(collConfig)
+	if err != nil {
+		t.Fatalf("marshal of collection config failed: %s", err)
+	}
+
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	testPeer1.Payload = payload
+	chClient := setupChannelClient([]fab.Peer{testPeer1}, t)
+	if err := WithLSCCCollectionConfig()(chClient); err != nil {
+		t.Fatalf("Failed to apply client option: %s", err)
+	}
+
+	config, err := chClient.collConfig.GetCollectionConfig("testCC")
+	assert.Nil(t, err, "expected collection config query to succeed")
+	if assert.Len(t, config, 1) {
+		assert.Equal(t, "coll1", config[0].GetStaticCollectionConfig().Name)
+	}
+}
+
 func TestMultiErrorPropogation(t *testing.T) {
 	testErr := fmt.Errorf("Test Error")
 
@@ -614,7 +928,7 @@ func setupChannelClientWithNodes(peers []fab.Peer,
 	return ch
 }
 
-func createAndSendTestTransactionProposal(sender fab.ProposalSender, chrequest *invoke.Request, targets []fab.ProposalProcessor) ([]*fab.TransactionProposalResponse, fab.TransactionID, error) {
+func createAndSendTestTransactionProposal(reqCtx reqContext.Context, sender fab.ProposalSender, chrequest *invoke.Request, targets []fab.ProposalProcessor) ([]*fab.TransactionProposalResponse, fab.TransactionID, error) {
 	request := fab.ChaincodeInvokeRequest{
 		ChaincodeID:  chrequest.ChaincodeID,
 		Fcn:          chrequest.Fcn,
@@ -632,6 +946,6 @@ func createAndSendTestTransactionProposal(sender fab.ProposalSender, chrequest *
 		return nil, fab.EmptyTransactionID, errors.WithMessage(err, "creation of transaction proposal failed")
 	}
 
-	tpr, err := sender.SendTransactionProposal(tpreq, targets)
+	tpr, err := sender.SendTransactionProposal(reqCtx, tpreq, targets)
 	return tpr, tpreq.TxnID, err
 }
@@ -0,0 +1,110 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/pkg/errors"
+)
+
+// closable is implemented by event services that hold a connection to release
+type closable interface {
+	Close()
+}
+
+// lazyEventService acquires the event service of the channel on the first registration, so that
+// clients that only query don't connect to the event source, and releases it when closed.
+type lazyEventService struct {
+	channelService fab.ChannelService
+	mutex          sync.Mutex
+	eventService   fab.EventService
+	closed         bool
+}
+
+func newLazyEventService(channelService fab.ChannelService) *lazyEventService {
+	return &lazyEventService{channelService: channelService}
+}
+
+// get returns the event service of the channel, which is acquired on first use
+func (s *lazyEventService) get() (fab.EventService, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return nil, errors.New("event service is closed")
+	}
+	if s.eventService == nil {
+		eventService, err := s.channelService.EventService()
+		if err != nil {
+			return nil, errors.WithMessage(err, "event service creation failed")
+		}
+		s.eventService = eventService
+	}
+	return s.eventService, nil
+}
+
+// RegisterBlockEvent registers for block events on the event service of the channel
+func (s *lazyEventService) RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockEvent, error) {
+	eventService, err := s.get()
+	if err != nil {
+		return nil, nil, err
+	}
+	return eventService.RegisterBlockEvent(filter...)
+}
+
+// RegisterFilteredBlockEvent registers for filtered block events on the event service of the channel
+func (s *lazyEventService) RegisterFilteredBlockEvent() (fab.Registration, <-chan *fab.FilteredBlockEvent, error) {
+	eventService, err := s.get()
+	if err != nil {
+		return nil, nil, err
+	}
+	return eventService.RegisterFilteredBlockEvent()
+}
+
+// RegisterChaincodeEvent registers for chaincode events on the event service of the channel
+func (s *lazyEventService) RegisterChaincodeEvent(ccID, eventFilter string) (fab.Registration, <-chan *fab.CCEvent, error) {
+	eventService, err := s.get()
+	if err != nil {
+		return nil, nil, err
+	}
+	return eventService.RegisterChaincodeEvent(ccID, eventFilter)
+}
+
+// RegisterTxStatusEvent registers for transaction status events on the event service of the channel
+func (s *lazyEventService) RegisterTxStatusEvent(txID string) (fab.Registration, <-chan *fab.TxStatusEvent, error) {
+	eventService, err := s.get()
+	if err != nil {
+		return nil, nil, err
+	}
+	return eventService.RegisterTxStatusEvent(txID)
+}
+
+// Unregister removes the registration, which was made on the acquired event service
+func (s *lazyEventService) Unregister(reg fab.Registration) {
+	s.mutex.Lock()
+	eventService := s.eventService
+	s.mutex.Unlock()
+
+	if eventService != nil {
+		eventService.Unregister(reg)
+	}
+}
+
+// Close releases the event service of the channel, if it was acquired.
+// Subsequent registrations fail.
+func (s *lazyEventService) Close() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.closed = true
+	if eventService, ok := s.eventService.(closable); ok {
+		eventService.Close()
+	}
+	s.eventService = nil
+}
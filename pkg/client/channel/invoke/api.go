@@ -8,11 +8,14 @@ SPDX-License-Identifier: Apache-2.0
 package invoke
 
 import (
+	reqContext "context"
 	"time"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/errors/retry"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 )
 
@@ -21,6 +24,8 @@ type Opts struct {
 	ProposalProcessors []fab.ProposalProcessor // targets
 	Timeout            time.Duration
 	Retry              retry.Opts
+	ParentContext      reqContext.Context
+	Identity           context.Identity // signing identity, if other than the client's
 }
 
 // Request contains the parameters to execute transaction
@@ -29,6 +34,9 @@ type Request struct {
 	Fcn          string
 	Args         [][]byte
 	TransientMap map[string][]byte
+	//Collections names the private data collections used by the request. If set, the request
+	//is only endorsed by peers of organizations that are members of all the collections
+	Collections []string
 }
 
 //Response contains response parameters for query and execute transaction
@@ -38,6 +46,8 @@ type Response struct {
 	TxValidationCode pb.TxValidationCode
 	Proposal         *fab.TransactionProposal
 	Responses        []*fab.TransactionProposalResponse
+	RWSet            *txn.TxRWSet
+	Attempts         int
 }
 
 //Handler for chaining transaction executions
@@ -47,19 +57,27 @@ type Handler interface {
 
 //ClientContext contains context parameters for handler execution
 type ClientContext struct {
-	CryptoSuite core.CryptoSuite
-	Discovery   fab.DiscoveryService
-	Selection   fab.SelectionService
-	Membership  fab.ChannelMembership
-	Transactor  fab.Transactor
-	EventHub    fab.EventHub
+	CryptoSuite  core.CryptoSuite
+	Discovery    fab.DiscoveryService
+	Selection    fab.SelectionService
+	Membership   fab.ChannelMembership
+	Transactor   fab.Transactor
+	EventService fab.EventService
+	// CollectionConfig provides the collection configuration for requests that name private data collections
+	CollectionConfig CCCollectionConfigProvider
+	// Interceptors are notified around every stage of the invoke chain (see Delegate)
+	Interceptors []Interceptor
 }
 
-//RequestContext contains request, opts, response parameters for handler execution
+//RequestContext contains request, opts, response parameters for handler execution.
+//Ctx carries the deadline and cancellation of the request; handlers must abort
+//in-flight work once it is done
 type RequestContext struct {
+	Ctx          reqContext.Context
 	Request      Request
 	Opts         Opts
 	Response     Response
 	Error        error
 	RetryHandler retry.Handler
+	Commit       *Commit
 }
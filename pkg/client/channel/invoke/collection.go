@@ -0,0 +1,217 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invoke
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+)
+
+//CCCollectionConfigProvider retrieves the private data collection configuration of the given chaincode ID,
+//i.e. the collection configuration the chaincode was instantiated with
+type CCCollectionConfigProvider interface {
+	GetCollectionConfig(chaincodeID string) ([]*common.CollectionConfig, error)
+}
+
+//StaticCollectionConfigProvider provides the collection configuration of chaincodes from a map
+//of chaincode ID to the collection configuration the chaincode was instantiated with
+type StaticCollectionConfigProvider map[string][]*common.CollectionConfig
+
+//GetCollectionConfig returns the collection configuration of the given chaincode ID
+func (p StaticCollectionConfigProvider) GetCollectionConfig(chaincodeID string) ([]*common.CollectionConfig, error) {
+	collConfig, ok := p[chaincodeID]
+	if !ok {
+		return nil, errors.Errorf("no collection config for chaincode [%s]", chaincodeID)
+	}
+	return collConfig, nil
+}
+
+const (
+	lscc                     = "lscc"
+	lsccGetCollectionsConfig = "GetCollectionsConfig"
+)
+
+//CCQuery queries a chaincode and returns the payload of the response
+type CCQuery func(request Request) ([]byte, error)
+
+//LSCCCollectionConfigProvider retrieves the collection configuration of chaincodes from the lifecycle system
+//chaincode (LSCC) of the channel, which requires peers that support its GetCollectionsConfig function.
+//The collection configuration of each chaincode is cached, since it doesn't change until the chaincode is upgraded
+type LSCCCollectionConfigProvider struct {
+	query   CCQuery
+	mutex   sync.RWMutex
+	configs map[string][]*common.CollectionConfig
+}
+
+//NewLSCCCollectionConfigProvider returns a provider that queries LSCC with the given query function
+func NewLSCCCollectionConfigProvider(query CCQuery) *LSCCCollectionConfigProvider {
+	return &LSCCCollectionConfigProvider{
+		query:   query,
+		configs: make(map[string][]*common.CollectionConfig),
+	}
+}
+
+//GetCollectionConfig returns the collection configuration of the given chaincode ID
+func (p *LSCCCollectionConfigProvider) GetCollectionConfig(chaincodeID string) ([]*common.CollectionConfig, error) {
+	p.mutex.RLock()
+	collConfig, ok := p.configs[chaincodeID]
+	p.mutex.RUnlock()
+	if ok {
+		return collConfig, nil
+	}
+
+	payload, err := p.query(Request{ChaincodeID: lscc, Fcn: lsccGetCollectionsConfig, Args: [][]byte{[]byte(chaincodeID)}})
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("querying collection config of chaincode [%s] failed", chaincodeID))
+	}
+
+	collConfigPkg := &common.CollectionConfigPackage{}
+	if err := proto.Unmarshal(payload, collConfigPkg); err != nil {
+		return nil, errors.Wrap(err, "unmarshal of collection config failed")
+	}
+
+	p.mutex.Lock()
+	p.configs[chaincodeID] = collConfigPkg.Config
+	p.mutex.Unlock()
+
+	return collConfigPkg.Config, nil
+}
+
+//collectionMembers returns the MSP IDs of the organizations that are members of all the named collections
+func collectionMembers(provider CCCollectionConfigProvider, chaincodeID string, collections []string) (map[string]bool, error) {
+	if provider == nil {
+		return nil, errors.New("collection config provider is required for private data requests")
+	}
+
+	collConfig, err := provider.GetCollectionConfig(chaincodeID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get collection config")
+	}
+
+	var members map[string]bool
+	for _, name := range collections {
+		staticConfig := findCollection(collConfig, name)
+		if staticConfig == nil {
+			return nil, errors.Errorf("collection [%s] is not defined for chaincode [%s]", name, chaincodeID)
+		}
+
+		orgs := memberOrgs(staticConfig)
+		if members == nil {
+			members = orgs
+			continue
+		}
+		for mspID := range members {
+			if !orgs[mspID] {
+				delete(members, mspID)
+			}
+		}
+	}
+
+	if len(members) == 0 {
+		return nil, errors.Errorf("no organization is a member of all collections [%s]", strings.Join(collections, ", "))
+	}
+	return members, nil
+}
+
+func findCollection(collConfig []*common.CollectionConfig, name string) *common.StaticCollectionConfig {
+	for _, config := range collConfig {
+		staticConfig := config.GetStaticCollectionConfig()
+		if staticConfig != nil && staticConfig.Name == name {
+			return staticConfig
+		}
+	}
+	return nil
+}
+
+//memberOrgs returns the MSP IDs of the principals of the collection's member orgs policy
+func memberOrgs(staticConfig *common.StaticCollectionConfig) map[string]bool {
+	orgs := make(map[string]bool)
+	policy := staticConfig.GetMemberOrgsPolicy().GetSignaturePolicy()
+	if policy == nil {
+		return orgs
+	}
+	for _, principal := range policy.Identities {
+		if mspID, ok := principalMSPID(principal); ok {
+			orgs[mspID] = true
+		}
+	}
+	return orgs
+}
+
+//principalMSPID returns the MSP ID of the given principal
+func principalMSPID(principal *msp.MSPPrincipal) (string, bool) {
+	switch principal.PrincipalClassification {
+	case msp.MSPPrincipal_ROLE:
+		role := &msp.MSPRole{}
+		if err := proto.Unmarshal(principal.Principal, role); err != nil {
+			return "", false
+		}
+		return role.MspIdentifier, true
+	case msp.MSPPrincipal_ORGANIZATION_UNIT:
+		ou := &msp.OrganizationUnit{}
+		if err := proto.Unmarshal(principal.Principal, ou); err != nil {
+			return "", false
+		}
+		return ou.MspIdentifier, true
+	case msp.MSPPrincipal_IDENTITY:
+		identity := &msp.SerializedIdentity{}
+		if err := proto.Unmarshal(principal.Principal, identity); err != nil {
+			return "", false
+		}
+		return identity.Mspid, true
+	default:
+		return "", false
+	}
+}
+
+//filterCollectionMembers returns the peers that belong to one of the member organizations
+func filterCollectionMembers(peers []fab.Peer, members map[string]bool) []fab.Peer {
+	var filtered []fab.Peer
+	for _, p := range peers {
+		if members[p.MSPID()] {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered
+}
+
+//validateCollectionTargets verifies that all the given targets belong to one of the member organizations.
+//Targets that are not peers have no organization and are rejected
+func validateCollectionTargets(targets []fab.ProposalProcessor, members map[string]bool) error {
+	var nonMembers []string
+	for _, target := range targets {
+		p, ok := target.(fab.Peer)
+		if !ok {
+			return errors.Errorf("target %T is not a peer, so its membership of the collections can't be verified", target)
+		}
+		if !members[p.MSPID()] {
+			nonMembers = append(nonMembers, fmt.Sprintf("%s (%s)", p.URL(), p.MSPID()))
+		}
+	}
+	if len(nonMembers) > 0 {
+		return errors.Errorf("targets are not members of the collections: [%s]; member organizations: [%s]", strings.Join(nonMembers, ", "), strings.Join(sortedKeys(members), ", "))
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]bool) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
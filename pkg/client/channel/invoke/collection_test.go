@@ -0,0 +1,175 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invoke
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+func newCollectionConfig(name string, mspIDs ...string) *common.CollectionConfig {
+	return &common.CollectionConfig{
+		Payload: &common.CollectionConfig_StaticCollectionConfig{
+			StaticCollectionConfig: &common.StaticCollectionConfig{
+				Name: name,
+				MemberOrgsPolicy: &common.CollectionPolicyConfig{
+					Payload: &common.CollectionPolicyConfig_SignaturePolicy{
+						SignaturePolicy: cauthdsl.SignedByAnyMember(mspIDs),
+					},
+				},
+				RequiredPeerCount: 1,
+				MaximumPeerCount:  2,
+			},
+		},
+	}
+}
+
+func setupCollectionClientContext(peers []fab.Peer, t *testing.T) *ClientContext {
+	discoveryService, err := setupTestDiscovery(nil, peers)
+	if err != nil {
+		t.Fatalf("Failed to setup discovery service: %s", err)
+	}
+
+	clientContext := setupChannelClientContext(nil, nil, nil, t)
+	clientContext.Discovery = discoveryService
+	clientContext.Selection = nil
+	clientContext.CollectionConfig = StaticCollectionConfigProvider{
+		"testCC": {
+			newCollectionConfig("coll1", "Org1MSP", "Org2MSP"),
+			newCollectionConfig("coll2", "Org2MSP", "Org3MSP"),
+		},
+	}
+	return clientContext
+}
+
+func TestProposalProcessorHandlerCollections(t *testing.T) {
+	peer1 := fcmocks.NewMockPeer("peer1", "http://peer1.com")
+	peer1.MockMSP = "Org1MSP"
+	peer2 := fcmocks.NewMockPeer("peer2", "http://peer2.com")
+	peer2.MockMSP = "Org2MSP"
+	peer3 := fcmocks.NewMockPeer("peer3", "http://peer3.com")
+	peer3.MockMSP = "Org3MSP"
+	clientContext := setupCollectionClientContext([]fab.Peer{peer1, peer2, peer3}, t)
+
+	// Only members of the collection endorse
+	request := Request{ChaincodeID: "testCC", Fcn: "invoke", Collections: []string{"coll1"}}
+	requestContext := prepareRequestContext(request, Opts{}, t)
+	NewProposalProcessorHandler().Handle(requestContext, clientContext)
+	assert.Nil(t, requestContext.Error)
+	assert.Equal(t, []fab.ProposalProcessor{peer1, peer2}, requestContext.Opts.ProposalProcessors)
+
+	// Endorsers must be members of all collections
+	request = Request{ChaincodeID: "testCC", Fcn: "invoke", Collections: []string{"coll1", "coll2"}}
+	requestContext = prepareRequestContext(request, Opts{}, t)
+	NewProposalProcessorHandler().Handle(requestContext, clientContext)
+	assert.Nil(t, requestContext.Error)
+	assert.Equal(t, []fab.ProposalProcessor{peer2}, requestContext.Opts.ProposalProcessors)
+
+	// Requests without collections are endorsed by all peers
+	request = Request{ChaincodeID: "testCC", Fcn: "invoke"}
+	requestContext = prepareRequestContext(request, Opts{}, t)
+	NewProposalProcessorHandler().Handle(requestContext, clientContext)
+	assert.Nil(t, requestContext.Error)
+	assert.Len(t, requestContext.Opts.ProposalProcessors, 3)
+
+	// Explicit targets must be members of the collections
+	request = Request{ChaincodeID: "testCC", Fcn: "invoke", Collections: []string{"coll2"}}
+	requestContext = prepareRequestContext(request, Opts{ProposalProcessors: []fab.ProposalProcessor{peer1, peer3}}, t)
+	NewProposalProcessorHandler().Handle(requestContext, clientContext)
+	if requestContext.Error == nil {
+		t.Fatal("Expected error for targets that are not collection members")
+	}
+	assert.True(t, strings.Contains(requestContext.Error.Error(), "http://peer1.com"), "expected non-member target in error: %s", requestContext.Error)
+	assert.False(t, strings.Contains(requestContext.Error.Error(), "http://peer3.com"), "unexpected member target in error: %s", requestContext.Error)
+
+	// Targets that are not peers can't be verified
+	requestContext = prepareRequestContext(request, Opts{ProposalProcessors: []fab.ProposalProcessor{peer3, &proposalProcessor{}}}, t)
+	NewProposalProcessorHandler().Handle(requestContext, clientContext)
+	assert.NotNil(t, requestContext.Error, "expected error for target that is not a peer")
+}
+
+// proposalProcessor is a target that is not a peer
+type proposalProcessor struct {
+	fab.ProposalProcessor
+}
+
+func TestProposalProcessorHandlerCollectionErrors(t *testing.T) {
+	peer1 := fcmocks.NewMockPeer("peer1", "http://peer1.com")
+	peer1.MockMSP = "Org1MSP"
+	clientContext := setupCollectionClientContext([]fab.Peer{peer1}, t)
+
+	// Unknown collection
+	request := Request{ChaincodeID: "testCC", Fcn: "invoke", Collections: []string{"unknown"}}
+	requestContext := prepareRequestContext(request, Opts{}, t)
+	NewProposalProcessorHandler().Handle(requestContext, clientContext)
+	assert.NotNil(t, requestContext.Error, "expected error for unknown collection")
+
+	// Unknown chaincode
+	request = Request{ChaincodeID: "otherCC", Fcn: "invoke", Collections: []string{"coll1"}}
+	requestContext = prepareRequestContext(request, Opts{}, t)
+	NewProposalProcessorHandler().Handle(requestContext, clientContext)
+	assert.NotNil(t, requestContext.Error, "expected error for chaincode without collection config")
+
+	// No peers of the member orgs
+	request = Request{ChaincodeID: "testCC", Fcn: "invoke", Collections: []string{"coll2"}}
+	requestContext = prepareRequestContext(request, Opts{}, t)
+	NewProposalProcessorHandler().Handle(requestContext, clientContext)
+	assert.NotNil(t, requestContext.Error, "expected error when no peers are collection members")
+
+	// Collection config provider is required
+	clientContext.CollectionConfig = nil
+	request = Request{ChaincodeID: "testCC", Fcn: "invoke", Collections: []string{"coll1"}}
+	requestContext = prepareRequestContext(request, Opts{}, t)
+	NewProposalProcessorHandler().Handle(requestContext, clientContext)
+	assert.NotNil(t, requestContext.Error, "expected error without collection config provider")
+}
+
+func TestLSCCCollectionConfigProvider(t *testing.T) {
+	collConfig := []*common.CollectionConfig{newCollectionConfig("coll1", "Org1MSP")}
+	payload, err := proto.Marshal(&common.CollectionConfigPackage{Config: collConfig})
+	if err != nil {
+		t.Fatalf("marshal of collection config failed: %s", err)
+	}
+
+	var queries []Request
+	provider := NewLSCCCollectionConfigProvider(func(request Request) ([]byte, error) {
+		queries = append(queries, request)
+		if string(request.Args[0]) != "testCC" {
+			return nil, errors.New("chaincode not found")
+		}
+		return payload, nil
+	})
+
+	config, err := provider.GetCollectionConfig("testCC")
+	assert.Nil(t, err)
+	assert.True(t, proto.Equal(collConfig[0], config[0]), "unexpected collection config")
+	if assert.Len(t, queries, 1) {
+		assert.Equal(t, "lscc", queries[0].ChaincodeID)
+		assert.Equal(t, "GetCollectionsConfig", queries[0].Fcn)
+	}
+
+	// The collection config is cached
+	_, err = provider.GetCollectionConfig("testCC")
+	assert.Nil(t, err)
+	assert.Len(t, queries, 1)
+
+	// Failed queries aren't cached
+	_, err = provider.GetCollectionConfig("otherCC")
+	assert.NotNil(t, err, "expected error for unknown chaincode")
+	_, err = provider.GetCollectionConfig("otherCC")
+	assert.NotNil(t, err, "expected error for unknown chaincode")
+	assert.Len(t, queries, 3)
+}
@@ -0,0 +1,95 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invoke
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+//CommitStatus contains the outcome of committing a transaction
+type CommitStatus struct {
+	TxValidationCode pb.TxValidationCode
+	BlockNumber      uint64
+	Error            error
+}
+
+//Commit is a handle to a transaction that was accepted by the orderer and is waiting to be committed.
+//The commit status is delivered exactly once, either when the event service reports the transaction
+//or when the commit is cancelled.
+type Commit struct {
+	TxnID        fab.TransactionID
+	eventService fab.EventService
+	reg          fab.Registration
+	notifier     chan CommitStatus
+	once         sync.Once
+	unregOnce    sync.Once
+}
+
+//registerCommit registers on the given event service for the status of the given transaction
+func registerCommit(txnID fab.TransactionID, eventService fab.EventService) (*Commit, error) {
+	reg, eventch, err := eventService.RegisterTxStatusEvent(string(txnID))
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Commit{
+		TxnID:        txnID,
+		eventService: eventService,
+		reg:          reg,
+		notifier:     make(chan CommitStatus, 1),
+	}
+
+	go func() {
+		event, ok := <-eventch
+		if !ok {
+			// Unregistered before the status was received
+			return
+		}
+		logger.Debugf("Received code(%s) in block(%d) for txid(%s)\n", event.TxValidationCode, event.BlockNumber, event.TxID)
+		c.unregister()
+		result := txn.NewStatus(event)
+		c.notify(CommitStatus{TxValidationCode: result.Code, BlockNumber: result.BlockNumber, Error: result.Error})
+	}()
+
+	return c, nil
+}
+
+//Done returns a channel that receives the commit status of the transaction
+func (c *Commit) Done() <-chan CommitStatus {
+	return c.notifier
+}
+
+//Wait blocks until the commit status of the transaction is available
+func (c *Commit) Wait() CommitStatus {
+	return <-c.notifier
+}
+
+//Cancel stops waiting for the transaction to be committed. If the status has not
+//been delivered yet then an error status is delivered instead.
+//Note that the transaction may still be committed by the network.
+func (c *Commit) Cancel() {
+	c.unregister()
+	c.notify(CommitStatus{Error: errors.New("waiting for commit was cancelled")})
+}
+
+func (c *Commit) notify(status CommitStatus) {
+	c.once.Do(func() {
+		c.notifier <- status
+	})
+}
+
+func (c *Commit) unregister() {
+	c.unregOnce.Do(func() {
+		c.eventService.Unregister(c.reg)
+	})
+}
@@ -0,0 +1,77 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invoke
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+//Interceptor is notified before and after every stage (handler) of the invoke chain.
+//
+//Interceptors are invoked in the order they were registered before a stage and in the reverse
+//order after it. Since each stage delegates to the next one, the stages are nested: the After
+//notification of a stage follows the notifications of all the stages it delegated to, and the
+//elapsed time includes them.
+type Interceptor interface {
+	//Before is called before the stage is executed. Returning an error fails the request with that
+	//error and skips the stage and the rest of the chain; After is still called for the interceptors
+	//notified so far
+	Before(stage string, requestContext *RequestContext, clientContext *ClientContext) error
+	//After is called once the stage has returned
+	After(stage string, requestContext *RequestContext, clientContext *ClientContext, elapsed time.Duration)
+}
+
+//Delegate executes the given handler, if any, wrapped by the interceptors of the client context.
+//Custom handlers should delegate to their next handler through Delegate so that it is intercepted as well
+func Delegate(handler Handler, requestContext *RequestContext, clientContext *ClientContext) {
+	if handler == nil {
+		return
+	}
+
+	interceptors := clientContext.Interceptors
+	if len(interceptors) == 0 {
+		handler.Handle(requestContext, clientContext)
+		return
+	}
+
+	stage := StageName(handler)
+
+	notified := 0
+	var err error
+	for _, interceptor := range interceptors {
+		err = interceptor.Before(stage, requestContext, clientContext)
+		notified++
+		if err != nil {
+			break
+		}
+	}
+
+	start := time.Now()
+	if err != nil {
+		requestContext.Error = errors.WithMessage(err, fmt.Sprintf("interceptor rejected stage %s", stage))
+	} else {
+		handler.Handle(requestContext, clientContext)
+	}
+	elapsed := time.Since(start)
+
+	for i := notified - 1; i >= 0; i-- {
+		interceptors[i].After(stage, requestContext, clientContext, elapsed)
+	}
+}
+
+//StageName returns the name of the stage executed by the handler, i.e. the name of its type
+func StageName(handler Handler) string {
+	t := reflect.TypeOf(handler)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Name()
+}
@@ -0,0 +1,298 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invoke
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/pkg/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+)
+
+//CCPolicyProvider retrieves the endorsement policy for the given chaincode ID
+type CCPolicyProvider interface {
+	GetChaincodePolicy(chaincodeID string) (*common.SignaturePolicyEnvelope, error)
+}
+
+//NewPolicyEndorsementValidationHandler returns a handler that validates endorsements against the chaincode's endorsement policy
+func NewPolicyEndorsementValidationHandler(policyProvider CCPolicyProvider, next ...Handler) *PolicyEndorsementValidationHandler {
+	return &PolicyEndorsementValidationHandler{policyProvider: policyProvider, next: getNext(next)}
+}
+
+//PolicyEndorsementValidationHandler validates the collected endorsements against the chaincode's endorsement policy.
+//Failed and mismatching responses are dropped as long as the remaining matching endorsements satisfy the policy.
+//Endorsers are validated through the channel membership, which checks the principals if it implements
+//principalValidator. Otherwise only member and identity principals are checked, and the principals that the
+//client can't verify, such as the admin role or organizational units, are not satisfied
+type PolicyEndorsementValidationHandler struct {
+	policyProvider CCPolicyProvider
+	next           Handler
+}
+
+//Handle for validating proposal responses against the endorsement policy.
+//Errors of endorsers that failed to respond are tolerated if the policy is satisfied by the remaining endorsements
+func (f *PolicyEndorsementValidationHandler) Handle(requestContext *RequestContext, clientContext *ClientContext) {
+
+	endorsementErr := requestContext.Error
+	requestContext.Error = nil
+
+	policy, err := f.policyProvider.GetChaincodePolicy(requestContext.Request.ChaincodeID)
+	if err != nil {
+		requestContext.Error = errors.WithMessage(err, "failed to get endorsement policy")
+		return
+	}
+
+	responses, err := f.validate(policy, requestContext.Response.Responses, clientContext.Membership)
+	if err != nil {
+		err = errors.WithMessage(err, "endorsement validation failed")
+		if endorsementErr != nil {
+			// Keep the endorsement errors so that retries can be resolved
+			errs, ok := endorsementErr.(multi.Errors)
+			if !ok {
+				errs = multi.Errors{endorsementErr}
+			}
+			err = multi.New(append(multi.Errors{err}, errs...)...)
+		}
+		requestContext.Error = err
+		return
+	}
+	if endorsementErr != nil {
+		logger.Debugf("Endorsement policy satisfied despite endorsement errors: %s", endorsementErr)
+	}
+
+	if len(responses) != len(requestContext.Response.Responses) {
+		logger.Debugf("Dropped %d of %d proposal responses that did not match the endorsement quorum", len(requestContext.Response.Responses)-len(responses), len(requestContext.Response.Responses))
+		requestContext.Response.Responses = responses
+		requestContext.Response.Payload = responses[0].ProposalResponse.GetResponse().Payload
+		requestContext.Response.RWSet, err = txn.DecodeProposalResponsePayload(responses[0].ProposalResponse.GetPayload())
+		if err != nil {
+			requestContext.Error = errors.WithMessage(err, "decoding read/write set failed")
+			return
+		}
+	}
+
+	//Delegate to next step if any
+	Delegate(f.next, requestContext, clientContext)
+}
+
+//validate returns the largest group of matching successful responses that satisfies the policy
+func (f *PolicyEndorsementValidationHandler) validate(policy *common.SignaturePolicyEnvelope, txProposalResponses []*fab.TransactionProposalResponse, membership fab.ChannelMembership) ([]*fab.TransactionProposalResponse, error) {
+	if policy == nil || policy.Rule == nil {
+		return nil, errors.New("endorsement policy is empty")
+	}
+
+	var failures []string
+	var groups [][]*fab.TransactionProposalResponse
+	for _, r := range txProposalResponses {
+		if r.ProposalResponse.GetResponse().Status != int32(common.Status_SUCCESS) {
+			failures = append(failures, status.NewFromProposalResponse(r.ProposalResponse, r.Endorser).Error())
+			continue
+		}
+		groups = addToMatchingGroup(groups, r)
+	}
+
+	// Prefer the quorum with the most endorsements
+	sort.SliceStable(groups, func(i, j int) bool { return len(groups[i]) > len(groups[j]) })
+
+	for _, group := range groups {
+		if satisfiesPolicy(policy, getEndorsers(group, membership)) {
+			return group, nil
+		}
+	}
+
+	var best []*fab.TransactionProposalResponse
+	if len(groups) > 0 {
+		best = groups[0]
+	}
+	msg := fmt.Sprintf("endorsement policy not satisfied, missing principals: [%s]", missingPrincipals(policy, getEndorsers(best, membership)))
+	if len(groups) > 1 {
+		msg += fmt.Sprintf("; %d groups of mismatching ProposalResponsePayloads received", len(groups))
+	}
+	if len(failures) > 0 {
+		msg += fmt.Sprintf("; failed endorsements: [%s]", strings.Join(failures, ", "))
+	}
+	return nil, status.New(status.EndorserClientStatus, status.EndorsementPolicyFailure.ToInt32(), msg, nil)
+}
+
+//addToMatchingGroup adds the response to the group of responses with the same result
+func addToMatchingGroup(groups [][]*fab.TransactionProposalResponse, r *fab.TransactionProposalResponse) [][]*fab.TransactionProposalResponse {
+	for i, group := range groups {
+		if sameResult(group[0], r) {
+			groups[i] = append(group, r)
+			return groups
+		}
+	}
+	return append(groups, []*fab.TransactionProposalResponse{r})
+}
+
+func sameResult(r1, r2 *fab.TransactionProposalResponse) bool {
+	return bytes.Equal(r1.ProposalResponse.GetPayload(), r2.ProposalResponse.GetPayload()) &&
+		bytes.Equal(r1.ProposalResponse.GetResponse().Payload, r2.ProposalResponse.GetResponse().Payload)
+}
+
+//principalValidator is implemented by channel memberships that check whether an identity satisfies a principal
+type principalValidator interface {
+	SatisfiesPrincipal(serializedID []byte, principal *msp.MSPPrincipal) error
+}
+
+//endorser is an endorsing identity extracted from a proposal response
+type endorser struct {
+	serialized []byte
+	identity   *msp.SerializedIdentity
+	membership fab.ChannelMembership
+}
+
+func getEndorsers(responses []*fab.TransactionProposalResponse, membership fab.ChannelMembership) []*endorser {
+	var endorsers []*endorser
+	for _, r := range responses {
+		serialized := r.ProposalResponse.GetEndorsement().GetEndorser()
+		identity := &msp.SerializedIdentity{}
+		if err := proto.Unmarshal(serialized, identity); err != nil {
+			logger.Debugf("Unable to unmarshal endorser identity of %s: %s", r.Endorser, err)
+			identity = nil
+		}
+		endorsers = append(endorsers, &endorser{serialized: serialized, identity: identity, membership: membership})
+	}
+	return endorsers
+}
+
+func satisfiesPolicy(policy *common.SignaturePolicyEnvelope, endorsers []*endorser) bool {
+	used := make([]bool, len(endorsers))
+	return evaluate(policy.Rule, policy.Identities, endorsers, used)
+}
+
+//evaluate evaluates the rule in the same way as the cauthdsl policy evaluator:
+//each endorsement may only be used to satisfy a single principal
+func evaluate(rule *common.SignaturePolicy, principals []*msp.MSPPrincipal, endorsers []*endorser, used []bool) bool {
+	switch t := rule.Type.(type) {
+	case *common.SignaturePolicy_SignedBy:
+		if t.SignedBy < 0 || int(t.SignedBy) >= len(principals) {
+			return false
+		}
+		for i, e := range endorsers {
+			if !used[i] && e.satisfiesPrincipal(principals[t.SignedBy]) {
+				used[i] = true
+				return true
+			}
+		}
+		return false
+	case *common.SignaturePolicy_NOutOf_:
+		verified := int32(0)
+		tmpUsed := make([]bool, len(used))
+		copy(tmpUsed, used)
+		for _, r := range t.NOutOf.Rules {
+			if evaluate(r, principals, endorsers, tmpUsed) {
+				verified++
+			}
+		}
+		if verified < t.NOutOf.N {
+			return false
+		}
+		copy(used, tmpUsed)
+		return true
+	default:
+		return false
+	}
+}
+
+//satisfiesPrincipal returns whether the endorser is a valid identity of the channel that satisfies the principal
+func (e *endorser) satisfiesPrincipal(principal *msp.MSPPrincipal) bool {
+	if e.identity == nil || e.membership == nil {
+		return false
+	}
+	if validator, ok := e.membership.(principalValidator); ok {
+		return validator.SatisfiesPrincipal(e.serialized, principal) == nil
+	}
+
+	switch principal.PrincipalClassification {
+	case msp.MSPPrincipal_ROLE:
+		role := &msp.MSPRole{}
+		if err := proto.Unmarshal(principal.Principal, role); err != nil {
+			return false
+		}
+		//Other roles can't be verified without the MSP
+		if role.Role != msp.MSPRole_MEMBER || e.identity.Mspid != role.MspIdentifier {
+			return false
+		}
+	case msp.MSPPrincipal_IDENTITY:
+		if !bytes.Equal(e.serialized, principal.Principal) {
+			return false
+		}
+	default:
+		return false
+	}
+	return e.membership.Validate(e.serialized) == nil
+}
+
+//missingPrincipals describes what the endorsers are missing to satisfy the rules of the policy.
+//As in policy evaluation, each endorsement only satisfies a single principal
+func missingPrincipals(policy *common.SignaturePolicyEnvelope, endorsers []*endorser) string {
+	used := make([]bool, len(endorsers))
+	return describeMissing(policy.Rule, policy.Identities, endorsers, used)
+}
+
+//describeMissing describes the principals missing from the unsatisfied rule, such as "1 of [Org1MSP.member, Org2MSP.member]"
+//for the NOutOf rules that lack more than one of their rules
+func describeMissing(rule *common.SignaturePolicy, principals []*msp.MSPPrincipal, endorsers []*endorser, used []bool) string {
+	switch t := rule.Type.(type) {
+	case *common.SignaturePolicy_SignedBy:
+		if t.SignedBy < 0 || int(t.SignedBy) >= len(principals) {
+			return fmt.Sprintf("invalid principal %d", t.SignedBy)
+		}
+		return principalString(principals[t.SignedBy])
+	case *common.SignaturePolicy_NOutOf_:
+		verified := int32(0)
+		tmpUsed := make([]bool, len(used))
+		copy(tmpUsed, used)
+		var missing []string
+		for _, r := range t.NOutOf.Rules {
+			if evaluate(r, principals, endorsers, tmpUsed) {
+				verified++
+			} else {
+				missing = append(missing, describeMissing(r, principals, endorsers, tmpUsed))
+			}
+		}
+		required := t.NOutOf.N - verified
+		if required == 1 && len(missing) == 1 {
+			return missing[0]
+		}
+		return fmt.Sprintf("%d of [%s]", required, strings.Join(missing, ", "))
+	default:
+		return "unsupported rule"
+	}
+}
+
+func principalString(principal *msp.MSPPrincipal) string {
+	switch principal.PrincipalClassification {
+	case msp.MSPPrincipal_ROLE:
+		role := &msp.MSPRole{}
+		if err := proto.Unmarshal(principal.Principal, role); err == nil {
+			return fmt.Sprintf("%s.%s", role.MspIdentifier, strings.ToLower(role.Role.String()))
+		}
+	case msp.MSPPrincipal_ORGANIZATION_UNIT:
+		ou := &msp.OrganizationUnit{}
+		if err := proto.Unmarshal(principal.Principal, ou); err == nil {
+			return fmt.Sprintf("%s.%s", ou.MspIdentifier, ou.OrganizationalUnitIdentifier)
+		}
+	case msp.MSPPrincipal_IDENTITY:
+		identity := &msp.SerializedIdentity{}
+		if err := proto.Unmarshal(principal.Principal, identity); err == nil {
+			return fmt.Sprintf("%s identity", identity.Mspid)
+		}
+	}
+	return principal.PrincipalClassification.String()
+}
@@ -0,0 +1,219 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invoke
+
+import (
+	reqContext "context"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/pkg/errors/status"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+)
+
+type mockCCPolicyProvider struct {
+	policy *common.SignaturePolicyEnvelope
+	err    error
+}
+
+func (p *mockCCPolicyProvider) GetChaincodePolicy(chaincodeID string) (*common.SignaturePolicyEnvelope, error) {
+	return p.policy, p.err
+}
+
+func newMockPolicyProvider(policy string, t *testing.T) *mockCCPolicyProvider {
+	envelope, err := cauthdsl.FromString(policy)
+	if err != nil {
+		t.Fatalf("Failed to parse policy: %s", err)
+	}
+	return &mockCCPolicyProvider{policy: envelope}
+}
+
+func newPolicyTestPeer(name string, mspID string, status int32, payload string, t *testing.T) *fcmocks.MockPeer {
+	endorser, err := proto.Marshal(&msp.SerializedIdentity{Mspid: mspID, IdBytes: []byte(name)})
+	if err != nil {
+		t.Fatalf("Failed to marshal identity: %s", err)
+	}
+	return &fcmocks.MockPeer{MockName: name, MockURL: "http://" + name + ".com", MockMSP: mspID, Status: status, Payload: []byte(payload), Endorser: endorser}
+}
+
+func TestPolicyEndorsementValidationHandler(t *testing.T) {
+	request := Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}}
+
+	peer1 := newPolicyTestPeer("peer1", "Org1MSP", 200, "value", t)
+	peer2 := newPolicyTestPeer("peer2", "Org2MSP", 200, "value", t)
+	peer3 := newPolicyTestPeer("peer3", "Org3MSP", 500, "", t)
+	peer4 := newPolicyTestPeer("peer4", "Org3MSP", 200, "other", t)
+	clientContext := setupChannelClientContext(nil, nil, []fab.Peer{peer1, peer2, peer3, peer4}, t)
+
+	// Bad responses are dropped while the policy is still satisfied
+	requestContext := prepareRequestContext(request, Opts{}, t)
+	handler := NewProposalProcessorHandler(NewEndorsementHandler(
+		NewPolicyEndorsementValidationHandler(newMockPolicyProvider("OutOf(2, 'Org1MSP.member', 'Org2MSP.member', 'Org3MSP.member')", t))))
+	handler.Handle(requestContext, clientContext)
+	if requestContext.Error != nil {
+		t.Fatalf("Expected policy to be satisfied but got error: %s", requestContext.Error)
+	}
+	assert.Len(t, requestContext.Response.Responses, 2, "expected failed and mismatching responses to be dropped")
+	assert.Equal(t, []byte("value"), requestContext.Response.Payload)
+
+	// Policy can't be met by the remaining responses
+	requestContext = prepareRequestContext(request, Opts{}, t)
+	handler = NewProposalProcessorHandler(NewEndorsementHandler(
+		NewPolicyEndorsementValidationHandler(newMockPolicyProvider("AND('Org1MSP.member', 'Org3MSP.member')", t))))
+	handler.Handle(requestContext, clientContext)
+	if requestContext.Error == nil {
+		t.Fatal("Expected endorsement policy failure")
+	}
+	s, ok := status.FromError(requestContext.Error)
+	assert.True(t, ok, "expected status error")
+	assert.EqualValues(t, status.EndorsementPolicyFailure.ToInt32(), s.Code)
+	assert.True(t, strings.Contains(requestContext.Error.Error(), "Org3MSP.member"), "expected missing principal in error: %s", requestContext.Error)
+	assert.False(t, strings.Contains(requestContext.Error.Error(), "Org1MSP.member"), "unexpected principal in error: %s", requestContext.Error)
+
+	// Failure to get the policy fails the request
+	requestContext = prepareRequestContext(request, Opts{}, t)
+	handler = NewProposalProcessorHandler(NewEndorsementHandler(
+		NewPolicyEndorsementValidationHandler(&mockCCPolicyProvider{err: errors.New("policy error")})))
+	handler.Handle(requestContext, clientContext)
+	assert.NotNil(t, requestContext.Error, "expected error when policy is unavailable")
+}
+
+func TestPolicyEndorsementValidationSingleEndorserPerPrincipal(t *testing.T) {
+	request := Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}}
+
+	peer1 := newPolicyTestPeer("peer1", "Org1MSP", 200, "value", t)
+	clientContext := setupChannelClientContext(nil, nil, []fab.Peer{peer1}, t)
+
+	requestContext := prepareRequestContext(request, Opts{}, t)
+	handler := NewProposalProcessorHandler(NewEndorsementHandler(
+		NewPolicyEndorsementValidationHandler(newMockPolicyProvider("AND('Org1MSP.member', 'Org1MSP.peer')", t))))
+	handler.Handle(requestContext, clientContext)
+	if requestContext.Error == nil {
+		t.Fatal("Expected a single endorsement not to satisfy two principals")
+	}
+	assert.True(t, strings.Contains(requestContext.Error.Error(), "Org1MSP.peer"), "expected missing principal in error: %s", requestContext.Error)
+}
+
+//principalMembership is a channel membership that checks principals: identities named admin are admins
+type principalMembership struct {
+	*fcmocks.MockMembership
+}
+
+func (m *principalMembership) SatisfiesPrincipal(serializedID []byte, principal *msp.MSPPrincipal) error {
+	identity := &msp.SerializedIdentity{}
+	if err := proto.Unmarshal(serializedID, identity); err != nil {
+		return err
+	}
+	role := &msp.MSPRole{}
+	if err := proto.Unmarshal(principal.Principal, role); err != nil {
+		return err
+	}
+	if role.MspIdentifier != identity.Mspid || (role.Role == msp.MSPRole_ADMIN && string(identity.IdBytes) != "admin") {
+		return errors.New("principal not satisfied")
+	}
+	return nil
+}
+
+func TestPolicyEndorsementValidationPrincipals(t *testing.T) {
+	request := Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}}
+
+	peer1 := newPolicyTestPeer("peer1", "Org1MSP", 200, "value", t)
+	admin := newPolicyTestPeer("admin", "Org1MSP", 200, "value", t)
+	policy := newMockPolicyProvider("OR('Org1MSP.admin', 'Org2MSP.member')", t)
+
+	// Roles other than member can't be verified without a membership that checks principals
+	clientContext := setupChannelClientContext(nil, nil, []fab.Peer{admin}, t)
+	requestContext := prepareRequestContext(request, Opts{}, t)
+	NewProposalProcessorHandler(NewEndorsementHandler(NewPolicyEndorsementValidationHandler(policy))).Handle(requestContext, clientContext)
+	if requestContext.Error == nil {
+		t.Fatal("Expected unverifiable admin principal not to be satisfied")
+	}
+	assert.True(t, strings.Contains(requestContext.Error.Error(), "missing principals: [1 of [Org1MSP.admin, Org2MSP.member]]"), "expected missing principals of OR rule in error: %s", requestContext.Error)
+
+	// A peer endorsement doesn't satisfy the admin role
+	clientContext = setupChannelClientContext(nil, nil, []fab.Peer{peer1}, t)
+	clientContext.Membership = &principalMembership{MockMembership: fcmocks.NewMockMembership()}
+	requestContext = prepareRequestContext(request, Opts{}, t)
+	NewProposalProcessorHandler(NewEndorsementHandler(NewPolicyEndorsementValidationHandler(policy))).Handle(requestContext, clientContext)
+	assert.NotNil(t, requestContext.Error, "expected peer endorsement not to satisfy the admin role")
+
+	clientContext = setupChannelClientContext(nil, nil, []fab.Peer{admin}, t)
+	clientContext.Membership = &principalMembership{MockMembership: fcmocks.NewMockMembership()}
+	requestContext = prepareRequestContext(request, Opts{}, t)
+	NewProposalProcessorHandler(NewEndorsementHandler(NewPolicyEndorsementValidationHandler(policy))).Handle(requestContext, clientContext)
+	assert.Nil(t, requestContext.Error, "expected admin endorsement to satisfy the admin role")
+
+	// Endorsers that aren't valid identities of the channel satisfy no principal
+	clientContext = setupChannelClientContext(nil, nil, []fab.Peer{peer1}, t)
+	clientContext.Membership = &fcmocks.MockMembership{ValidateErr: errors.New("unknown MSP")}
+	requestContext = prepareRequestContext(request, Opts{}, t)
+	NewProposalProcessorHandler(NewEndorsementHandler(NewPolicyEndorsementValidationHandler(newMockPolicyProvider("OR('Org1MSP.member')", t)))).Handle(requestContext, clientContext)
+	assert.NotNil(t, requestContext.Error, "expected invalid endorser not to satisfy the policy")
+}
+
+func TestMissingPrincipals(t *testing.T) {
+	peer1 := newPolicyTestPeer("peer1", "Org1MSP", 200, "value", t)
+	responses, err := peer1.ProcessTransactionProposal(reqContext.Background(), fab.ProcessProposalRequest{})
+	if err != nil {
+		t.Fatalf("Failed to get proposal response: %s", err)
+	}
+	endorsers := getEndorsers([]*fab.TransactionProposalResponse{responses}, fcmocks.NewMockMembership())
+
+	missing := map[string]string{
+		"AND('Org1MSP.member', 'Org2MSP.member')":                                               "Org2MSP.member",
+		"OR('Org2MSP.member', 'Org3MSP.member')":                                                "1 of [Org2MSP.member, Org3MSP.member]",
+		"AND('Org2MSP.member', 'Org3MSP.member')":                                               "2 of [Org2MSP.member, Org3MSP.member]",
+		"AND('Org1MSP.member', OR('Org2MSP.member', 'Org3MSP.member'))":                         "1 of [Org2MSP.member, Org3MSP.member]",
+		"OutOf(2, 'Org1MSP.member', AND('Org2MSP.member', 'Org3MSP.member'), 'Org4MSP.member')": "1 of [2 of [Org2MSP.member, Org3MSP.member], Org4MSP.member]",
+	}
+	for policy, expected := range missing {
+		envelope, err := cauthdsl.FromString(policy)
+		if err != nil {
+			t.Fatalf("Failed to parse policy: %s", err)
+		}
+		assert.Equal(t, expected, missingPrincipals(envelope, endorsers), "unexpected missing principals of %s", policy)
+	}
+}
+
+func TestPolicyQueryHandlerEndorserError(t *testing.T) {
+	request := Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}}
+
+	peer1 := newPolicyTestPeer("peer1", "Org1MSP", 200, "value", t)
+	peer2 := newPolicyTestPeer("peer2", "Org2MSP", 200, "value", t)
+	peer3 := newPolicyTestPeer("peer3", "Org3MSP", 0, "", t)
+	peer3.Error = status.New(status.EndorserClientStatus, status.ConnectionFailed.ToInt32(), "connection failed", nil)
+	clientContext := setupChannelClientContext(nil, nil, []fab.Peer{peer1, peer2, peer3}, t)
+
+	// Unreachable endorser is tolerated
+	requestContext := prepareRequestContext(request, Opts{}, t)
+	NewPolicyQueryHandler(newMockPolicyProvider("OR('Org1MSP.member', 'Org3MSP.member')", t)).Handle(requestContext, clientContext)
+	if requestContext.Error != nil {
+		t.Fatalf("Expected policy to be satisfied but got error: %s", requestContext.Error)
+	}
+	assert.Len(t, requestContext.Response.Responses, 2)
+
+	// Endorser errors are kept when the policy can't be met
+	requestContext = prepareRequestContext(request, Opts{}, t)
+	NewPolicyQueryHandler(newMockPolicyProvider("AND('Org1MSP.member', 'Org3MSP.member')", t)).Handle(requestContext, clientContext)
+	errs, ok := requestContext.Error.(multi.Errors)
+	if !ok {
+		t.Fatalf("Expected multi errors but got %v", requestContext.Error)
+	}
+	assert.Len(t, errs, 2)
+	s, ok := status.FromError(errs[1])
+	assert.True(t, ok, "expected status error")
+	assert.EqualValues(t, status.ConnectionFailed.ToInt32(), s.Code)
+}
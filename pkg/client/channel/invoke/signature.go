@@ -36,9 +36,7 @@ func (f *SignatureValidationHandler) Handle(requestContext *RequestContext, clie
 	}
 
 	// Delegate to next step if any
-	if f.next != nil {
-		f.next.Handle(requestContext, clientContext)
-	}
+	Delegate(f.next, requestContext, clientContext)
 }
 
 func (f *SignatureValidationHandler) validate(txProposalResponse []*fab.TransactionProposalResponse, ctx *ClientContext) error {
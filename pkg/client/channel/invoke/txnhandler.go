@@ -8,7 +8,8 @@ package invoke
 
 import (
 	"bytes"
-	"time"
+	reqContext "context"
+	"strings"
 
 	"github.com/pkg/errors"
 
@@ -25,6 +26,9 @@ var logger = logging.NewLogger("fabric_sdk_go")
 //EndorsementHandler for handling endorse transactions
 type EndorsementHandler struct {
 	next Handler
+	// partial delegates to the next handler as long as at least one endorsement was received.
+	// The endorsement errors are passed on in requestContext.Error
+	partial bool
 }
 
 //Handle for endorsing transactions
@@ -36,25 +40,52 @@ func (e *EndorsementHandler) Handle(requestContext *RequestContext, clientContex
 	}
 
 	// Endorse Tx
-	transactionProposalResponses, proposal, err := createAndSendTransactionProposal(clientContext.Transactor, &requestContext.Request, requestContext.Opts.ProposalProcessors)
+	transactionProposalResponses, proposal, err := createAndSendTransactionProposal(requestContext.Ctx, clientContext.Transactor, &requestContext.Request, requestContext.Opts)
+
+	//The proposal isn't created if the transaction header fails, such as for an identity that can't be serialized
+	if proposal == nil {
+		requestContext.Error = err
+		return
+	}
 
 	requestContext.Response.Proposal = proposal
 	requestContext.Response.TransactionID = proposal.TxnID // TODO: still needed?
 
 	if err != nil {
 		requestContext.Error = err
-		return
+		if !e.partial {
+			return
+		}
+		transactionProposalResponses = receivedResponses(transactionProposalResponses)
+		if len(transactionProposalResponses) == 0 {
+			return
+		}
+		logger.Debugf("Received %d endorsements, continuing despite endorsement errors: %s", len(transactionProposalResponses), err)
 	}
 
 	requestContext.Response.Responses = transactionProposalResponses
 	if len(transactionProposalResponses) > 0 {
 		requestContext.Response.Payload = transactionProposalResponses[0].ProposalResponse.GetResponse().Payload
+		requestContext.Response.RWSet, err = txn.DecodeProposalResponsePayload(transactionProposalResponses[0].ProposalResponse.GetPayload())
+		if err != nil {
+			requestContext.Error = errors.WithMessage(err, "decoding read/write set failed")
+			return
+		}
 	}
 
 	//Delegate to next step if any
-	if e.next != nil {
-		e.next.Handle(requestContext, clientContext)
+	Delegate(e.next, requestContext, clientContext)
+}
+
+//receivedResponses filters out the responses of endorsers that failed to respond
+func receivedResponses(responses []*fab.TransactionProposalResponse) []*fab.TransactionProposalResponse {
+	var received []*fab.TransactionProposalResponse
+	for _, r := range responses {
+		if r.ProposalResponse != nil {
+			received = append(received, r)
+		}
 	}
+	return received
 }
 
 //ProposalProcessorHandler for selecting proposal processors
@@ -66,6 +97,17 @@ type ProposalProcessorHandler struct {
 func (h *ProposalProcessorHandler) Handle(requestContext *RequestContext, clientContext *ClientContext) {
 	//Get proposal processor, if not supplied then use discovery service to get available peers as endorser
 	//If selection service available then get endorser peers for this chaincode
+	//Private data requests are only endorsed by peers of the collections' member organizations
+	var members map[string]bool
+	if len(requestContext.Request.Collections) > 0 {
+		var err error
+		members, err = collectionMembers(clientContext.CollectionConfig, requestContext.Request.ChaincodeID, requestContext.Request.Collections)
+		if err != nil {
+			requestContext.Error = errors.WithMessage(err, "failed to get collection members")
+			return
+		}
+	}
+
 	if len(requestContext.Opts.ProposalProcessors) == 0 {
 		// Use discovery service to figure out proposal processors
 		peers, err := clientContext.Discovery.GetPeers()
@@ -73,6 +115,13 @@ func (h *ProposalProcessorHandler) Handle(requestContext *RequestContext, client
 			requestContext.Error = errors.WithMessage(err, "GetPeers failed")
 			return
 		}
+		if members != nil {
+			peers = filterCollectionMembers(peers, members)
+			if len(peers) == 0 {
+				requestContext.Error = errors.Errorf("no peers of the member organizations [%s] of the collections found", strings.Join(sortedKeys(members), ", "))
+				return
+			}
+		}
 		endorsers := peers
 		if clientContext.Selection != nil {
 			endorsers, err = clientContext.Selection.GetEndorsersForChaincode(peers, requestContext.Request.ChaincodeID)
@@ -82,12 +131,15 @@ func (h *ProposalProcessorHandler) Handle(requestContext *RequestContext, client
 			}
 		}
 		requestContext.Opts.ProposalProcessors = peer.PeersToTxnProcessors(endorsers)
+	} else if members != nil {
+		if err := validateCollectionTargets(requestContext.Opts.ProposalProcessors, members); err != nil {
+			requestContext.Error = err
+			return
+		}
 	}
 
 	//Delegate to next step if any
-	if h.next != nil {
-		h.next.Handle(requestContext, clientContext)
-	}
+	Delegate(h.next, requestContext, clientContext)
 }
 
 //EndorsementValidationHandler for transaction proposal response filtering
@@ -106,9 +158,7 @@ func (f *EndorsementValidationHandler) Handle(requestContext *RequestContext, cl
 	}
 
 	//Delegate to next step if any
-	if f.next != nil {
-		f.next.Handle(requestContext, clientContext)
-	}
+	Delegate(f.next, requestContext, clientContext)
 }
 
 func (f *EndorsementValidationHandler) validate(txProposalResponse []*fab.TransactionProposalResponse) error {
@@ -139,42 +189,75 @@ type CommitTxHandler struct {
 //Handle handles commit tx
 func (c *CommitTxHandler) Handle(requestContext *RequestContext, clientContext *ClientContext) {
 
-	//Connect to Event hub if not yet connected
-	if clientContext.EventHub.IsConnected() == false {
-		err := clientContext.EventHub.Connect()
-		if err != nil {
-			requestContext.Error = err
-			return
-		}
-	}
-
 	txnID := requestContext.Response.TransactionID
 
 	//Register Tx event
-	statusNotifier := txn.RegisterStatus(txnID, clientContext.EventHub)
-	_, err := createAndSendTransaction(clientContext.Transactor, requestContext.Response.Proposal, requestContext.Response.Responses)
+	reg, statusNotifier, err := clientContext.EventService.RegisterTxStatusEvent(string(txnID))
+	if err != nil {
+		requestContext.Error = errors.Wrap(err, "error registering for TxStatus event")
+		return
+	}
+	defer clientContext.EventService.Unregister(reg)
+
+	_, err = createAndSendTransaction(requestContext.Ctx, clientContext.Transactor, requestContext.Response.Proposal, requestContext.Response.Responses)
 	if err != nil {
 		requestContext.Error = errors.Wrap(err, "CreateAndSendTransaction failed")
 		return
 	}
 
 	select {
-	case result := <-statusNotifier:
+	case event, ok := <-statusNotifier:
+		if !ok {
+			requestContext.Error = errors.New("TxStatus event registration was closed")
+			return
+		}
+		result := txn.NewStatus(event)
 		requestContext.Response.TxValidationCode = result.Code
 
 		if result.Error != nil {
 			requestContext.Error = result.Error
 			return
 		}
-	case <-time.After(requestContext.Opts.Timeout):
-		requestContext.Error = errors.New("Execute didn't receive block event")
+	case <-requestContext.Ctx.Done():
+		if requestContext.Ctx.Err() == reqContext.DeadlineExceeded {
+			requestContext.Error = status.New(status.ClientStatus, status.Timeout.ToInt32(),
+				"Execute didn't receive block event", nil)
+			return
+		}
+		requestContext.Error = errors.Wrap(requestContext.Ctx.Err(), "Execute didn't receive block event")
 		return
 	}
 
 	//Delegate to next step if any
-	if c.next != nil {
-		c.next.Handle(requestContext, clientContext)
+	Delegate(c.next, requestContext, clientContext)
+}
+
+//SendTxHandler for sending transactions to the orderer without waiting for them to be committed
+type SendTxHandler struct {
+	next Handler
+}
+
+//Handle registers for the tx status and sends the transaction to the orderer.
+//The commit status is delivered asynchronously through requestContext.Commit
+func (c *SendTxHandler) Handle(requestContext *RequestContext, clientContext *ClientContext) {
+
+	//Register Tx event
+	commit, err := registerCommit(requestContext.Response.TransactionID, clientContext.EventService)
+	if err != nil {
+		requestContext.Error = errors.Wrap(err, "error registering for TxStatus event")
+		return
+	}
+	_, err = createAndSendTransaction(requestContext.Ctx, clientContext.Transactor, requestContext.Response.Proposal, requestContext.Response.Responses)
+	if err != nil {
+		commit.Cancel()
+		requestContext.Error = errors.Wrap(err, "CreateAndSendTransaction failed")
+		return
 	}
+
+	requestContext.Commit = commit
+
+	//Delegate to next step if any
+	Delegate(c.next, requestContext, clientContext)
 }
 
 //NewQueryHandler returns query handler with EndorseTxHandler & EndorsementValidationHandler Chained
@@ -199,6 +282,42 @@ func NewExecuteHandler(next ...Handler) Handler {
 	)
 }
 
+//NewExecuteAsyncHandler returns execute handler with EndorseTxHandler, EndorsementValidationHandler & SendTxHandler Chained.
+//The transaction is sent to the orderer but the handler does not wait for it to be committed
+func NewExecuteAsyncHandler(next ...Handler) Handler {
+	return NewProposalProcessorHandler(
+		NewEndorsementHandler(
+			NewEndorsementValidationHandler(
+				NewSignatureValidationHandler(NewSendTxHandler(next...)),
+			),
+		),
+	)
+}
+
+//NewPolicyQueryHandler returns query handler with EndorseTxHandler & PolicyEndorsementValidationHandler Chained.
+//Endorsements are validated against the chaincode's endorsement policy instead of requiring all of them to match
+func NewPolicyQueryHandler(policyProvider CCPolicyProvider, next ...Handler) Handler {
+	return NewProposalProcessorHandler(
+		newPartialEndorsementHandler(
+			NewPolicyEndorsementValidationHandler(policyProvider,
+				NewSignatureValidationHandler(next...),
+			),
+		),
+	)
+}
+
+//NewPolicyExecuteHandler returns execute handler with EndorseTxHandler, PolicyEndorsementValidationHandler & CommitTxHandler Chained.
+//Endorsements are validated against the chaincode's endorsement policy instead of requiring all of them to match
+func NewPolicyExecuteHandler(policyProvider CCPolicyProvider, next ...Handler) Handler {
+	return NewProposalProcessorHandler(
+		newPartialEndorsementHandler(
+			NewPolicyEndorsementValidationHandler(policyProvider,
+				NewSignatureValidationHandler(NewCommitHandler(next...)),
+			),
+		),
+	)
+}
+
 //NewProposalProcessorHandler returns a handler that selects proposal processors
 func NewProposalProcessorHandler(next ...Handler) *ProposalProcessorHandler {
 	return &ProposalProcessorHandler{next: getNext(next)}
@@ -209,6 +328,12 @@ func NewEndorsementHandler(next ...Handler) *EndorsementHandler {
 	return &EndorsementHandler{next: getNext(next)}
 }
 
+//newPartialEndorsementHandler returns a handler that endorses a transaction proposal and
+//delegates to the next handler even if some of the endorsers failed
+func newPartialEndorsementHandler(next ...Handler) *EndorsementHandler {
+	return &EndorsementHandler{next: getNext(next), partial: true}
+}
+
 //NewEndorsementValidationHandler returns a handler that validates an endorsement
 func NewEndorsementValidationHandler(next ...Handler) *EndorsementValidationHandler {
 	return &EndorsementValidationHandler{next: getNext(next)}
@@ -219,6 +344,11 @@ func NewCommitHandler(next ...Handler) *CommitTxHandler {
 	return &CommitTxHandler{next: getNext(next)}
 }
 
+//NewSendTxHandler returns a handler that sends transaction proposal responses to the orderer
+func NewSendTxHandler(next ...Handler) *SendTxHandler {
+	return &SendTxHandler{next: getNext(next)}
+}
+
 func getNext(next []Handler) Handler {
 	if len(next) > 0 {
 		return next[0]
@@ -226,7 +356,7 @@ func getNext(next []Handler) Handler {
 	return nil
 }
 
-func createAndSendTransaction(sender fab.Sender, proposal *fab.TransactionProposal, resps []*fab.TransactionProposalResponse) (*fab.TransactionResponse, error) {
+func createAndSendTransaction(reqCtx reqContext.Context, sender fab.Sender, proposal *fab.TransactionProposal, resps []*fab.TransactionProposalResponse) (*fab.TransactionResponse, error) {
 
 	txnRequest := fab.TransactionRequest{
 		Proposal:          proposal,
@@ -238,7 +368,7 @@ func createAndSendTransaction(sender fab.Sender, proposal *fab.TransactionPropos
 		return nil, errors.WithMessage(err, "CreateTransaction failed")
 	}
 
-	transactionResponse, err := sender.SendTransaction(tx)
+	transactionResponse, err := sender.SendTransaction(reqCtx, tx)
 	if err != nil {
 		return nil, errors.WithMessage(err, "SendTransaction failed")
 
@@ -247,7 +377,7 @@ func createAndSendTransaction(sender fab.Sender, proposal *fab.TransactionPropos
 	return transactionResponse, nil
 }
 
-func createAndSendTransactionProposal(transactor fab.Transactor, chrequest *Request, targets []fab.ProposalProcessor) ([]*fab.TransactionProposalResponse, *fab.TransactionProposal, error) {
+func createAndSendTransactionProposal(reqCtx reqContext.Context, transactor fab.Transactor, chrequest *Request, opts Opts) ([]*fab.TransactionProposalResponse, *fab.TransactionProposal, error) {
 	request := fab.ChaincodeInvokeRequest{
 		ChaincodeID:  chrequest.ChaincodeID,
 		Fcn:          chrequest.Fcn,
@@ -255,7 +385,12 @@ func createAndSendTransactionProposal(transactor fab.Transactor, chrequest *Requ
 		TransientMap: chrequest.TransientMap,
 	}
 
-	txh, err := transactor.CreateTransactionHeader()
+	var txhOpts []fab.TxnHeaderOpt
+	if opts.Identity != nil {
+		txhOpts = append(txhOpts, fab.WithIdentity(opts.Identity))
+	}
+
+	txh, err := transactor.CreateTransactionHeader(txhOpts...)
 	if err != nil {
 		return nil, nil, errors.WithMessage(err, "creating transaction header failed")
 	}
@@ -265,6 +400,6 @@ func createAndSendTransactionProposal(transactor fab.Transactor, chrequest *Requ
 		return nil, nil, errors.WithMessage(err, "creating transaction proposal failed")
 	}
 
-	transactionProposalResponses, err := transactor.SendTransactionProposal(proposal, targets)
+	transactionProposalResponses, err := transactor.SendTransactionProposal(reqCtx, proposal, opts.ProposalProcessors)
 	return transactionProposalResponses, proposal, err
 }
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package invoke
 
 import (
+	reqContext "context"
 	"strings"
 	"testing"
 	"time"
@@ -18,6 +19,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
 	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 )
 
 const (
@@ -50,6 +52,9 @@ func TestQueryHandlerSuccess(t *testing.T) {
 	if requestContext.Error != nil {
 		t.Fatal("Query handler failed", requestContext.Error)
 	}
+	if requestContext.Response.RWSet == nil {
+		t.Fatal("Expected decoded read/write set in query response")
+	}
 }
 
 func TestExecuteTxHandlerSuccess(t *testing.T) {
@@ -64,14 +69,14 @@ func TestExecuteTxHandlerSuccess(t *testing.T) {
 
 	clientContext := setupChannelClientContext(nil, nil, []fab.Peer{mockPeer1, mockPeer2}, t)
 
-	//Prepare mock eventhub
-	mockEventHub := fcmocks.NewMockEventHub()
-	clientContext.EventHub = mockEventHub
+	//Prepare mock event service
+	mockEventService := fcmocks.NewMockEventService()
+	clientContext.EventService = mockEventService
 
 	go func() {
 		select {
-		case callback := <-mockEventHub.RegisteredTxCallbacks:
-			callback("txid", 0, nil)
+		case txStatusReg := <-mockEventService.TxStatusRegCh:
+			txStatusReg.Eventch <- &fab.TxStatusEvent{TxID: txStatusReg.TxID, TxValidationCode: pb.TxValidationCode_VALID}
 		case <-time.After(requestContext.Opts.Timeout):
 			t.Fatal("Execute handler : time out not expected")
 		}
@@ -84,6 +89,39 @@ func TestExecuteTxHandlerSuccess(t *testing.T) {
 	assert.Nil(t, requestContext.Error)
 }
 
+func TestExecuteAsyncHandlerSuccess(t *testing.T) {
+	//Sample request
+	request := Request{ChaincodeID: "test", Fcn: "invoke", Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}}
+
+	//Prepare context objects for handler
+	requestContext := prepareRequestContext(request, Opts{}, t)
+
+	mockPeer1 := &fcmocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockRoles: []string{}, MockCert: nil, MockMSP: "Org1MSP", Status: 200, Payload: []byte("value")}
+
+	clientContext := setupChannelClientContext(nil, nil, []fab.Peer{mockPeer1}, t)
+
+	//Prepare mock event service
+	mockEventService := fcmocks.NewMockEventService()
+	clientContext.EventService = mockEventService
+
+	//Get execute async handler
+	executeHandler := NewExecuteAsyncHandler()
+	//Perform action through handler
+	executeHandler.Handle(requestContext, clientContext)
+	assert.Nil(t, requestContext.Error)
+	if requestContext.Commit == nil {
+		t.Fatal("Execute async handler : expected commit handle")
+	}
+
+	txStatusReg := <-mockEventService.TxStatusRegCh
+	txStatusReg.Eventch <- &fab.TxStatusEvent{TxID: txStatusReg.TxID, TxValidationCode: pb.TxValidationCode_MVCC_READ_CONFLICT, BlockNumber: 5}
+
+	status := requestContext.Commit.Wait()
+	assert.Equal(t, pb.TxValidationCode_MVCC_READ_CONFLICT, status.TxValidationCode)
+	assert.EqualValues(t, 5, status.BlockNumber)
+	assert.NotNil(t, status.Error)
+}
+
 func TestQueryHandlerErrors(t *testing.T) {
 
 	//Error Scenario 1
@@ -161,6 +199,31 @@ func TestEndorsementHandler(t *testing.T) {
 	assert.Nil(t, requestContext.Error)
 }
 
+//failingIdentity is an identity that fails to serialize
+type failingIdentity struct {
+	context.Identity
+}
+
+func (i *failingIdentity) SerializedIdentity() ([]byte, error) {
+	return nil, errors.New("serialization failed")
+}
+
+func TestEndorsementHandlerIdentityError(t *testing.T) {
+	request := Request{ChaincodeID: "test", Fcn: "invoke", Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}}
+	identity := &failingIdentity{Identity: fcmocks.NewMockUser("other")}
+
+	requestContext := prepareRequestContext(request, Opts{ProposalProcessors: []fab.ProposalProcessor{fcmocks.NewMockPeer("p2", "")}, Identity: identity}, t)
+	clientContext := setupChannelClientContext(nil, nil, nil, t)
+
+	handler := NewEndorsementHandler()
+	handler.Handle(requestContext, clientContext)
+	if requestContext.Error == nil || !strings.Contains(requestContext.Error.Error(), "serialization failed") {
+		t.Fatal("Expected serialization error, Received error:", requestContext.Error)
+	}
+	assert.Nil(t, requestContext.Response.Proposal)
+	assert.Empty(t, requestContext.Response.Responses)
+}
+
 func TestProposalProcessorHandler(t *testing.T) {
 	peer1 := fcmocks.NewMockPeer("p1", "")
 	peer2 := fcmocks.NewMockPeer("p2", "")
@@ -212,6 +275,7 @@ func prepareRequestContext(request Request, opts Opts, t *testing.T) *RequestCon
 	}
 
 	requestContext.Opts.Timeout = testTimeOut
+	requestContext.Ctx = reqContext.Background()
 
 	return requestContext
 }
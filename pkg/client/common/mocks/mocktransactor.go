@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package mocks
 
 import (
+	reqContext "context"
+
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
@@ -21,8 +23,8 @@ type MockTransactor struct {
 }
 
 // CreateTransactionHeader creates a Transaction Header based on the current context.
-func (t *MockTransactor) CreateTransactionHeader() (fab.TransactionHeader, error) {
-	txh, err := txn.NewHeader(t.Ctx, t.ChannelID)
+func (t *MockTransactor) CreateTransactionHeader(opts ...fab.TxnHeaderOpt) (fab.TransactionHeader, error) {
+	txh, err := txn.NewHeader(t.Ctx, t.ChannelID, opts...)
 	if err != nil {
 		return nil, errors.WithMessage(err, "new transaction ID failed")
 	}
@@ -31,8 +33,8 @@ func (t *MockTransactor) CreateTransactionHeader() (fab.TransactionHeader, error
 }
 
 // SendTransactionProposal sends a TransactionProposal to the target peers.
-func (t *MockTransactor) SendTransactionProposal(proposal *fab.TransactionProposal, targets []fab.ProposalProcessor) ([]*fab.TransactionProposalResponse, error) {
-	return txn.SendProposal(t.Ctx, proposal, targets)
+func (t *MockTransactor) SendTransactionProposal(reqCtx reqContext.Context, proposal *fab.TransactionProposal, targets []fab.ProposalProcessor) ([]*fab.TransactionProposalResponse, error) {
+	return txn.SendProposal(reqCtx, t.Ctx, proposal, targets)
 }
 
 // CreateTransaction create a transaction with proposal response.
@@ -41,6 +43,6 @@ func (t *MockTransactor) CreateTransaction(request fab.TransactionRequest) (*fab
 }
 
 // SendTransaction send a transaction to the chain’s orderer service (one or more orderer endpoints) for consensus and committing to the ledger.
-func (t *MockTransactor) SendTransaction(tx *fab.Transaction) (*fab.TransactionResponse, error) {
-	return txn.Send(t.Ctx, tx, t.Orderers)
+func (t *MockTransactor) SendTransaction(reqCtx reqContext.Context, tx *fab.Transaction) (*fab.TransactionResponse, error) {
+	return txn.Send(reqCtx, t.Ctx, tx, t.Orderers)
 }
@@ -41,7 +41,7 @@ type CCPolicyProvider interface {
 }
 
 // NewCCPolicyProvider creates new chaincode policy data provider
-func newCCPolicyProvider(sdk *fabsdk.FabricSDK, channelID string, userName string, orgName string) (CCPolicyProvider, error) {
+func NewCCPolicyProvider(sdk *fabsdk.FabricSDK, channelID string, userName string, orgName string) (CCPolicyProvider, error) {
 	if channelID == "" || userName == "" || orgName == "" {
 		return nil, errors.New("Must provide channel ID, user name and organisation for cc policy provider")
 	}
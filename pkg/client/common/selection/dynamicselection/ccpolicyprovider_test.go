@@ -23,37 +23,37 @@ func TestCCPolicyProvider(t *testing.T) {
 	defer sdk.Close()
 
 	// Nil sdk
-	ccPolicyProvider, err := newCCPolicyProvider(nil, "mychannel", "User1", "Org1")
+	ccPolicyProvider, err := NewCCPolicyProvider(nil, "mychannel", "User1", "Org1")
 	if err == nil {
 		t.Fatalf("Should have failed for nil sdk")
 	}
 
 	// Invalid channelID
-	ccPolicyProvider, err = newCCPolicyProvider(sdk, "", "User1", "Org1")
+	ccPolicyProvider, err = NewCCPolicyProvider(sdk, "", "User1", "Org1")
 	if err == nil {
 		t.Fatalf("Should have failed for empty channel")
 	}
 
 	// Empty user name
-	ccPolicyProvider, err = newCCPolicyProvider(sdk, "mychannel", "", "Prg1")
+	ccPolicyProvider, err = NewCCPolicyProvider(sdk, "mychannel", "", "Prg1")
 	if err == nil {
 		t.Fatalf("Should have failed for empty user name")
 	}
 
 	// Empty org name
-	ccPolicyProvider, err = newCCPolicyProvider(sdk, "mychannel", "User1", "")
+	ccPolicyProvider, err = NewCCPolicyProvider(sdk, "mychannel", "User1", "")
 	if err == nil {
 		t.Fatalf("Should have failed for nil sdk")
 	}
 
 	// Invalid channel
-	ccPolicyProvider, err = newCCPolicyProvider(sdk, "non-existent", "User1", "Org1")
+	ccPolicyProvider, err = NewCCPolicyProvider(sdk, "non-existent", "User1", "Org1")
 	if err == nil {
 		t.Fatalf("Should have failed for invalid channel name")
 	}
 
 	// All good
-	ccPolicyProvider, err = newCCPolicyProvider(sdk, "mychannel", "User1", "Org1")
+	ccPolicyProvider, err = NewCCPolicyProvider(sdk, "mychannel", "User1", "Org1")
 	if err != nil {
 		t.Fatalf("Failed to setup cc policy provider: %s", err)
 	}
@@ -80,14 +80,14 @@ func TestBadClient(t *testing.T) {
 	defer sdk.Close()
 
 	// Non-existent user
-	ccPolicyProvider, err := newCCPolicyProvider(sdk, "mychannel", "Invalid", "Org1")
+	ccPolicyProvider, err := NewCCPolicyProvider(sdk, "mychannel", "Invalid", "Org1")
 	_, err = ccPolicyProvider.GetChaincodePolicy("mychannel")
 	if !strings.Contains(err.Error(), "user not found") {
 		t.Fatalf("Should have failed for invalid user name: %v", err)
 	}
 
 	// Invalid org
-	ccPolicyProvider, err = newCCPolicyProvider(sdk, "mychannel", "User1", "Invalid")
+	ccPolicyProvider, err = NewCCPolicyProvider(sdk, "mychannel", "User1", "Invalid")
 	_, err = ccPolicyProvider.GetChaincodePolicy("mychannel")
 	if !strings.Contains(err.Error(), "invalid org name") {
 		t.Fatalf("Should have failed for invalid org name")
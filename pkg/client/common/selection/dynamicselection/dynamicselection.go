@@ -75,7 +75,7 @@ func (p *SelectionProvider) NewSelectionService(channelID string) (fab.Selection
 		return nil, errors.New("Must provide user for channel")
 	}
 
-	ccPolicyProvider, err := newCCPolicyProvider(p.sdk, channelID, channelUser.UserName, channelUser.OrgName)
+	ccPolicyProvider, err := NewCCPolicyProvider(p.sdk, channelID, channelUser.UserName, channelUser.OrgName)
 	if err != nil {
 		return nil, errors.WithMessage(err, "Failed to create cc policy provider")
 	}
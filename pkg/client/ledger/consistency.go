@@ -0,0 +1,216 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+)
+
+// PeerLedgerStatus is the state of the ledger of a peer of the channel.
+type PeerLedgerStatus struct {
+	URL               string
+	MSPID             string
+	Height            uint64
+	CurrentBlockHash  []byte
+	PreviousBlockHash []byte
+	// Lag is the number of blocks the peer is behind the highest peer
+	Lag uint64
+	// Error is the error of the query of the peer, in which case the ledger state is unknown
+	Error error
+}
+
+// LedgerFork lists the peers that have different hashes of the block at the height of the fork, which is
+// the current block of the peers at that height and the previous block of the peers one block higher.
+type LedgerFork struct {
+	Height   uint64
+	Branches []*ForkBranch
+}
+
+// ForkBranch lists the peers of a fork that have the same hash of the block at the height of the fork.
+type ForkBranch struct {
+	CurrentBlockHash []byte
+	URLs             []string
+}
+
+// ConsistencyReport compares the ledgers of the peers of the channel.
+type ConsistencyReport struct {
+	// MaxHeight is the highest block height of the peers
+	MaxHeight uint64
+	// Peers is the ledger state of each peer, ordered by URL
+	Peers []*PeerLedgerStatus
+	// Forks are the heights at which the peers diverge, in ascending order
+	Forks []*LedgerFork
+}
+
+// IsConsistent returns true if all peers responded, at the same height and without forks.
+func (r *ConsistencyReport) IsConsistent() bool {
+	if len(r.Forks) > 0 {
+		return false
+	}
+	for _, p := range r.Peers {
+		if p.Error != nil || p.Lag > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// QueryConsistency queries the blockchain info of each peer of the channel and reports the peers whose
+// ledgers diverge (forks) and how far each peer lags the highest peer.
+// By default, all peers returned by the discovery service are queried, regardless of their MSP.
+// Targets and the target filter may be given to restrict the peers, whereas max targets doesn't apply.
+// Peers that fail to respond are part of the report, with their error. The query fails if fewer
+// than min targets peers respond.
+// Peers of the same height are compared by their current block hashes, and peers one block apart by the
+// previous block hash of the higher peer. Peers further apart are not compared, so a peer that lags
+// more than one block behind a fork isn't reported as forked.
+func (c *Client) QueryConsistency(options ...RequestOption) (*ConsistencyReport, error) {
+
+	opts, err := c.prepareRequestOpts(options...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get opts for QueryConsistency")
+	}
+
+	targets, err := c.consistencyTargets(opts)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to determine target peers for QueryConsistency")
+	}
+
+	statuses := make([]*PeerLedgerStatus, len(targets))
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Add(1)
+		go func(i int, target fab.Peer) {
+			defer wg.Done()
+			statuses[i] = c.queryLedgerStatus(target)
+		}(i, target)
+	}
+	wg.Wait()
+
+	report := newConsistencyReport(statuses)
+
+	responses := 0
+	for _, s := range report.Peers {
+		if s.Error == nil {
+			responses++
+		}
+	}
+	if responses < opts.MinTargets {
+		return nil, errors.Errorf("QueryConsistency: Number of responses %d is less than MinTargets %d", responses, opts.MinTargets)
+	}
+
+	return report, nil
+}
+
+// consistencyTargets returns the given targets or all peers of the discovery service, filtered by the target filter
+func (c *Client) consistencyTargets(opts Opts) ([]fab.Peer, error) {
+	if opts.Targets != nil && opts.TargetFilter != nil {
+		return nil, errors.New("If targets are provided, filter cannot be provided")
+	}
+
+	targets := opts.Targets
+	if targets == nil {
+		var err error
+		targets, err = c.discovery.GetPeers()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.TargetFilter != nil {
+		targets = filterTargets(targets, opts.TargetFilter)
+	}
+
+	if len(targets) == 0 {
+		return nil, errors.New("No targets available")
+	}
+	return targets, nil
+}
+
+func (c *Client) queryLedgerStatus(peer fab.Peer) *PeerLedgerStatus {
+	status := &PeerLedgerStatus{URL: peer.URL(), MSPID: peer.MSPID()}
+
+	responses, err := c.ledger.QueryInfo(peersToTxnProcessors([]fab.Peer{peer}))
+	if len(responses) == 0 {
+		if err == nil {
+			err = errors.New("no response")
+		}
+		status.Error = errors.WithMessage(err, "QueryInfo failed")
+		return status
+	}
+
+	bci := responses[0].BCI
+	status.Height = bci.Height
+	status.CurrentBlockHash = bci.CurrentBlockHash
+	status.PreviousBlockHash = bci.PreviousBlockHash
+	return status
+}
+
+// newConsistencyReport computes the lag of the peers and the forks
+func newConsistencyReport(statuses []*PeerLedgerStatus) *ConsistencyReport {
+	report := &ConsistencyReport{Peers: statuses}
+	sort.Slice(report.Peers, func(i, j int) bool { return report.Peers[i].URL < report.Peers[j].URL })
+
+	heights := make(map[uint64][]*PeerLedgerStatus)
+	for _, s := range report.Peers {
+		if s.Error != nil {
+			continue
+		}
+		if s.Height > report.MaxHeight {
+			report.MaxHeight = s.Height
+		}
+		heights[s.Height] = append(heights[s.Height], s)
+	}
+
+	for height, peers := range heights {
+		for _, s := range peers {
+			s.Lag = report.MaxHeight - height
+		}
+		if fork := newLedgerFork(height, peers, heights[height+1]); fork != nil {
+			report.Forks = append(report.Forks, fork)
+		}
+	}
+	sort.Slice(report.Forks, func(i, j int) bool { return report.Forks[i].Height < report.Forks[j].Height })
+
+	return report
+}
+
+// newLedgerFork returns the fork of the peers of the height and of the higher peers one block higher,
+// nil if the current block hashes of the peers and the previous block hashes of the higher peers match
+func newLedgerFork(height uint64, peers []*PeerLedgerStatus, higherPeers []*PeerLedgerStatus) *LedgerFork {
+	fork := &LedgerFork{Height: height}
+	for _, s := range peers {
+		fork.addPeer(s.CurrentBlockHash, s.URL)
+	}
+	for _, s := range higherPeers {
+		if len(s.PreviousBlockHash) > 0 {
+			fork.addPeer(s.PreviousBlockHash, s.URL)
+		}
+	}
+
+	if len(fork.Branches) < 2 {
+		return nil
+	}
+	return fork
+}
+
+// addPeer adds the peer to the branch of the block hash
+func (f *LedgerFork) addPeer(blockHash []byte, url string) {
+	for _, b := range f.Branches {
+		if bytes.Equal(b.CurrentBlockHash, blockHash) {
+			b.URLs = append(b.URLs, url)
+			return
+		}
+	}
+	f.Branches = append(f.Branches, &ForkBranch{CurrentBlockHash: blockHash, URLs: []string{url}})
+}
@@ -0,0 +1,99 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsistencyReport(t *testing.T) {
+	report := newConsistencyReport([]*PeerLedgerStatus{
+		{URL: "peer1.org2", Height: 10, CurrentBlockHash: []byte("b9"), PreviousBlockHash: []byte("b8")},
+		{URL: "peer0.org1", Height: 10, CurrentBlockHash: []byte("b9"), PreviousBlockHash: []byte("b8")},
+		{URL: "peer0.org2", Height: 8, CurrentBlockHash: []byte("b7"), PreviousBlockHash: []byte("b6")},
+	})
+	assert.Equal(t, uint64(10), report.MaxHeight)
+	assert.Empty(t, report.Forks)
+	assert.False(t, report.IsConsistent(), "expected lagging peer to be inconsistent")
+
+	var urls []string
+	var lags []uint64
+	for _, p := range report.Peers {
+		urls = append(urls, p.URL)
+		lags = append(lags, p.Lag)
+	}
+	assert.Equal(t, []string{"peer0.org1", "peer0.org2", "peer1.org2"}, urls)
+	assert.Equal(t, []uint64{0, 2, 0}, lags)
+
+	report = newConsistencyReport([]*PeerLedgerStatus{
+		{URL: "peer0.org1", Height: 10, CurrentBlockHash: []byte("b9")},
+		{URL: "peer0.org2", Height: 10, CurrentBlockHash: []byte("b9")},
+	})
+	assert.True(t, report.IsConsistent())
+}
+
+func TestConsistencyReportForks(t *testing.T) {
+	report := newConsistencyReport([]*PeerLedgerStatus{
+		{URL: "peer0.org1", Height: 10, CurrentBlockHash: []byte("b9")},
+		{URL: "peer1.org1", Height: 10, CurrentBlockHash: []byte("b9")},
+		{URL: "peer0.org2", Height: 10, CurrentBlockHash: []byte("x9")},
+		{URL: "peer0.org3", Height: 6, CurrentBlockHash: []byte("b5")},
+		{URL: "peer1.org3", Height: 6, CurrentBlockHash: []byte("y5")},
+	})
+	assert.False(t, report.IsConsistent())
+	if !assert.Len(t, report.Forks, 2) {
+		return
+	}
+
+	assert.Equal(t, uint64(6), report.Forks[0].Height)
+	assert.Equal(t, uint64(10), report.Forks[1].Height)
+	assert.Equal(t, []*ForkBranch{
+		{CurrentBlockHash: []byte("b9"), URLs: []string{"peer0.org1", "peer1.org1"}},
+		{CurrentBlockHash: []byte("x9"), URLs: []string{"peer0.org2"}},
+	}, report.Forks[1].Branches)
+}
+
+func TestConsistencyReportAdjacentHeights(t *testing.T) {
+	// The previous block hash of the peers at height 11 is the current block hash at height 10
+	report := newConsistencyReport([]*PeerLedgerStatus{
+		{URL: "peer0.org1", Height: 11, CurrentBlockHash: []byte("b10"), PreviousBlockHash: []byte("b9")},
+		{URL: "peer0.org2", Height: 10, CurrentBlockHash: []byte("b9"), PreviousBlockHash: []byte("b8")},
+		{URL: "peer0.org3", Height: 8, CurrentBlockHash: []byte("x7"), PreviousBlockHash: []byte("x6")},
+	})
+	assert.Empty(t, report.Forks, "expected no fork of peers one block apart with matching hashes")
+
+	report = newConsistencyReport([]*PeerLedgerStatus{
+		{URL: "peer0.org1", Height: 11, CurrentBlockHash: []byte("b10"), PreviousBlockHash: []byte("b9")},
+		{URL: "peer1.org1", Height: 11, CurrentBlockHash: []byte("b10"), PreviousBlockHash: []byte("b9")},
+		{URL: "peer0.org2", Height: 10, CurrentBlockHash: []byte("x9"), PreviousBlockHash: []byte("b8")},
+	})
+	assert.False(t, report.IsConsistent())
+	if !assert.Len(t, report.Forks, 1) {
+		return
+	}
+	assert.Equal(t, uint64(10), report.Forks[0].Height)
+	assert.Equal(t, []*ForkBranch{
+		{CurrentBlockHash: []byte("x9"), URLs: []string{"peer0.org2"}},
+		{CurrentBlockHash: []byte("b9"), URLs: []string{"peer0.org1", "peer1.org1"}},
+	}, report.Forks[0].Branches)
+}
+
+func TestConsistencyReportErrors(t *testing.T) {
+	report := newConsistencyReport([]*PeerLedgerStatus{
+		{URL: "peer0.org1", Height: 10, CurrentBlockHash: []byte("b9")},
+		{URL: "peer0.org2", Error: errors.New("connection refused")},
+	})
+
+	// Peers that failed to respond are neither lagging nor forked
+	assert.Equal(t, uint64(10), report.MaxHeight)
+	assert.Empty(t, report.Forks)
+	assert.Equal(t, uint64(0), report.Peers[1].Lag)
+	assert.False(t, report.IsConsistent(), "expected peer error to be inconsistent")
+}
@@ -0,0 +1,291 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	reqContext "context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/orderer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+const (
+	defaultBlockParallelism = 4
+)
+
+// BlockIterator delivers the blocks of a range in order. The blocks are fetched in parallel,
+// ahead of their delivery.
+type BlockIterator struct {
+	ctx     reqContext.Context
+	cancel  reqContext.CancelFunc
+	pending chan chan *blockResult
+	fetches chan struct{}
+	err     error
+}
+
+type blockResult struct {
+	block *common.Block
+	err   error
+}
+
+// blockSource fetches the blocks of a block iterator. The queries are cancelled once the context is done.
+type blockSource interface {
+	// height returns the number of blocks of the channel
+	height(ctx reqContext.Context) (uint64, error)
+	block(ctx reqContext.Context, blockNumber uint64) (*common.Block, error)
+}
+
+// QueryBlockRange returns an iterator of the blocks of the ledger from block number from to block number to,
+// inclusive. The blocks are queried from the specified targets as QueryBlock does, and from the orderer if
+// the targets are unavailable. Blocks delivered by the orderer have no transaction validation flags.
+// Without WithFollow, the range ends at the newest block if to is beyond it. With WithFollow,
+// the iterator waits for new blocks until block number to is delivered or the iterator is closed,
+// so that math.MaxUint64 follows the ledger indefinitely.
+func (c *Client) QueryBlockRange(from uint64, to uint64, options ...RequestOption) (*BlockIterator, error) {
+	if from > to {
+		return nil, errors.Errorf("invalid block range [%d, %d]", from, to)
+	}
+
+	opts, err := c.prepareRequestOpts(options...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get opts for QueryBlockRange")
+	}
+
+	parallelism := opts.Parallelism
+	if parallelism <= 0 {
+		parallelism = defaultBlockParallelism
+	}
+
+	source := &ledgerBlockSource{client: c, options: options, orderer: opts.Orderer}
+	return newBlockIterator(parentContext(opts), source, from, to, parallelism, opts.FollowInterval), nil
+}
+
+func newBlockIterator(parent reqContext.Context, source blockSource, from uint64, to uint64, parallelism int, followInterval time.Duration) *BlockIterator {
+	ctx, cancel := reqContext.WithCancel(parent)
+	it := &BlockIterator{
+		ctx:     ctx,
+		cancel:  cancel,
+		pending: make(chan chan *blockResult, parallelism),
+		fetches: make(chan struct{}, parallelism),
+	}
+	go it.dispatch(source, from, to, followInterval)
+	return it
+}
+
+// Next returns the next block of the range. It returns io.EOF after the last block of the range,
+// the context error once the iterator is closed, and the error of the first block that couldn't be fetched.
+func (it *BlockIterator) Next() (*common.Block, error) {
+	if it.err != nil {
+		return nil, it.err
+	}
+
+	select {
+	case pending, ok := <-it.pending:
+		if !ok {
+			it.stop(io.EOF)
+			return nil, it.err
+		}
+		select {
+		case result := <-pending:
+			if result.err != nil {
+				it.stop(result.err)
+				return nil, it.err
+			}
+			return result.block, nil
+		case <-it.ctx.Done():
+			it.stop(it.ctx.Err())
+			return nil, it.err
+		}
+	case <-it.ctx.Done():
+		it.stop(it.ctx.Err())
+		return nil, it.err
+	}
+}
+
+// Close stops the iterator and the fetching of its blocks.
+func (it *BlockIterator) Close() {
+	it.cancel()
+}
+
+func (it *BlockIterator) stop(err error) {
+	// Closed iterators report the context error rather than the end of the range
+	if ctxErr := it.ctx.Err(); ctxErr != nil && err == io.EOF {
+		err = ctxErr
+	}
+	it.err = err
+	it.cancel()
+}
+
+// dispatch starts fetching each block of the range, in order, once the channel has the block
+// and fewer blocks than the parallelism are pending delivery and being fetched
+func (it *BlockIterator) dispatch(source blockSource, from uint64, to uint64, followInterval time.Duration) {
+	defer close(it.pending)
+
+	var height uint64
+	for blockNumber := from; ; blockNumber++ {
+		if blockNumber >= height {
+			var err error
+			if height, err = it.waitForBlock(source, blockNumber, followInterval); err != nil {
+				it.fail(err)
+				return
+			}
+			if blockNumber >= height {
+				// End of the ledger
+				return
+			}
+		}
+
+		result := make(chan *blockResult, 1)
+		select {
+		case it.pending <- result:
+		case <-it.ctx.Done():
+			return
+		}
+
+		// Next frees the pending slot of a block while waiting for its fetch, so the fetches
+		// are bounded separately
+		select {
+		case it.fetches <- struct{}{}:
+		case <-it.ctx.Done():
+			return
+		}
+
+		go func(blockNumber uint64) {
+			defer func() { <-it.fetches }()
+
+			block, err := source.block(it.ctx, blockNumber)
+			if err != nil {
+				err = errors.WithMessage(err, "failed to fetch block")
+			}
+			result <- &blockResult{block: block, err: err}
+		}(blockNumber)
+
+		if blockNumber == to {
+			return
+		}
+	}
+}
+
+// waitForBlock returns the height of the channel. When following the ledger, it polls the height
+// until the channel has the block
+func (it *BlockIterator) waitForBlock(source blockSource, blockNumber uint64, followInterval time.Duration) (uint64, error) {
+	for {
+		height, err := source.height(it.ctx)
+		if err != nil {
+			return 0, errors.WithMessage(err, "failed to query block height")
+		}
+		if height > blockNumber || followInterval <= 0 {
+			return height, nil
+		}
+
+		select {
+		case <-time.After(followInterval):
+		case <-it.ctx.Done():
+			return 0, it.ctx.Err()
+		}
+	}
+}
+
+// fail delivers the error after the pending blocks
+func (it *BlockIterator) fail(err error) {
+	result := make(chan *blockResult, 1)
+	result <- &blockResult{err: err}
+	select {
+	case it.pending <- result:
+	case <-it.ctx.Done():
+	}
+}
+
+// ledgerBlockSource queries the blocks from the peers and falls back to the orderer
+type ledgerBlockSource struct {
+	client  *Client
+	options []RequestOption
+	mutex   sync.Mutex
+	orderer fab.Orderer
+}
+
+func (s *ledgerBlockSource) height(ctx reqContext.Context) (uint64, error) {
+	info, err := s.client.queryInfo(ctx, s.options...)
+	if err == nil {
+		return info.BCI.Height, nil
+	}
+	logger.Debugf("QueryInfo failed, querying newest block from orderer: %s", err)
+
+	o, ordererErr := s.getOrderer()
+	if ordererErr != nil {
+		return 0, errors.WithMessage(err, "QueryInfo failed and no orderer is available")
+	}
+	block, ordererErr := s.client.resource().NewestBlockFromOrderer(ctx, s.client.chName, o)
+	if ordererErr != nil {
+		return 0, errors.WithMessage(ordererErr, "newest block retrieval from orderer failed")
+	}
+	return block.Header.Number + 1, nil
+}
+
+func (s *ledgerBlockSource) block(ctx reqContext.Context, blockNumber uint64) (*common.Block, error) {
+	block, err := s.client.queryBlock(ctx, int(blockNumber), s.options...)
+	if err == nil {
+		return block, nil
+	}
+	logger.Debugf("QueryBlock %d failed, querying block from orderer: %s", blockNumber, err)
+
+	o, ordererErr := s.getOrderer()
+	if ordererErr != nil {
+		return nil, errors.WithMessage(err, "QueryBlock failed and no orderer is available")
+	}
+	block, ordererErr = s.client.resource().BlockFromOrderer(ctx, s.client.chName, o, blockNumber)
+	if ordererErr != nil {
+		return nil, errors.WithMessage(ordererErr, "block retrieval from orderer failed")
+	}
+	return block, nil
+}
+
+// getOrderer returns the orderer given in the request options or, by default, the first orderer
+// of the channel in the configuration
+func (s *ledgerBlockSource) getOrderer() (fab.Orderer, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.orderer != nil {
+		return s.orderer, nil
+	}
+
+	oConfig, err := s.client.provider.Config().ChannelOrderers(s.client.chName)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to load orderer config")
+	}
+	if len(oConfig) == 0 {
+		return nil, errors.Errorf("no orderers are configured for channel %s", s.client.chName)
+	}
+
+	o, err := orderer.New(s.client.provider.Config(), orderer.FromOrdererConfig(&oConfig[0]))
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create orderer from config")
+	}
+	s.orderer = o
+	return o, nil
+}
+
+// parentContext returns the parent context of the request options or, by default, the background context
+func parentContext(opts Opts) reqContext.Context {
+	if opts.ParentContext == nil {
+		return reqContext.Background()
+	}
+	return opts.ParentContext
+}
+
+// resource returns the resource client of the orderer queries
+func (c *Client) resource() *resource.Resource {
+	return resource.New(fabContext{Providers: c.provider, Identity: c.identity})
+}
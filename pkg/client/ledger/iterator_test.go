@@ -0,0 +1,218 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	reqContext "context"
+	"io"
+	"math"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+// mockBlockSource is a ledger of the given height whose blocks are fetched with random delays,
+// or until the fetch is cancelled if the fetches hang
+type mockBlockSource struct {
+	mutex      sync.Mutex
+	blocks     uint64
+	failBlock  uint64
+	hang       bool
+	fetching   int
+	maxFetched int
+	cancelled  int
+}
+
+func (s *mockBlockSource) height(ctx reqContext.Context) (uint64, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.blocks, nil
+}
+
+func (s *mockBlockSource) setHeight(height uint64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.blocks = height
+}
+
+func (s *mockBlockSource) block(ctx reqContext.Context, blockNumber uint64) (*common.Block, error) {
+	s.mutex.Lock()
+	s.fetching++
+	if s.fetching > s.maxFetched {
+		s.maxFetched = s.fetching
+	}
+	s.mutex.Unlock()
+
+	if s.hang {
+		<-ctx.Done()
+		s.mutex.Lock()
+		s.fetching--
+		s.cancelled++
+		s.mutex.Unlock()
+		return nil, ctx.Err()
+	}
+
+	time.Sleep(time.Duration(rand.Intn(5)) * time.Millisecond)
+
+	s.mutex.Lock()
+	s.fetching--
+	s.mutex.Unlock()
+
+	if s.failBlock != 0 && blockNumber == s.failBlock {
+		return nil, errors.New("block not available")
+	}
+	return &common.Block{Header: &common.BlockHeader{Number: blockNumber}}, nil
+}
+
+func (s *mockBlockSource) fetches() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.fetching
+}
+
+func readBlocks(t *testing.T, it *BlockIterator, count int) []uint64 {
+	var numbers []uint64
+	for i := 0; i < count; i++ {
+		block, err := it.Next()
+		if err != nil {
+			t.Fatalf("Failed to get block: %s", err)
+		}
+		numbers = append(numbers, block.Header.Number)
+	}
+	return numbers
+}
+
+func blockRange(from uint64, to uint64) []uint64 {
+	var numbers []uint64
+	for n := from; n <= to; n++ {
+		numbers = append(numbers, n)
+	}
+	return numbers
+}
+
+func TestBlockIterator(t *testing.T) {
+	source := &mockBlockSource{blocks: 50}
+	it := newBlockIterator(reqContext.Background(), source, 5, 30, 3, 0)
+	defer it.Close()
+
+	// Blocks are delivered in order
+	assert.Equal(t, blockRange(5, 30), readBlocks(t, it, 26))
+	_, err := it.Next()
+	assert.Equal(t, io.EOF, err)
+	_, err = it.Next()
+	assert.Equal(t, io.EOF, err, "expected end of range to be final")
+
+	// The block awaited by Next counts toward the parallelism
+	assert.True(t, source.maxFetched <= 3, "fetched %d blocks in parallel", source.maxFetched)
+}
+
+func TestBlockIteratorEndOfLedger(t *testing.T) {
+	source := &mockBlockSource{blocks: 10}
+	it := newBlockIterator(reqContext.Background(), source, 7, math.MaxUint64, 4, 0)
+	defer it.Close()
+
+	assert.Equal(t, blockRange(7, 9), readBlocks(t, it, 3))
+	_, err := it.Next()
+	assert.Equal(t, io.EOF, err)
+
+	it = newBlockIterator(reqContext.Background(), source, 10, 20, 4, 0)
+	_, err = it.Next()
+	assert.Equal(t, io.EOF, err, "expected empty range beyond the ledger")
+}
+
+func TestBlockIteratorFollow(t *testing.T) {
+	source := &mockBlockSource{blocks: 3}
+	it := newBlockIterator(reqContext.Background(), source, 0, 7, 2, time.Millisecond)
+	defer it.Close()
+
+	assert.Equal(t, blockRange(0, 2), readBlocks(t, it, 3))
+
+	// Commit the remaining blocks
+	go func() {
+		for height := uint64(4); height <= 10; height++ {
+			time.Sleep(2 * time.Millisecond)
+			source.setHeight(height)
+		}
+	}()
+	assert.Equal(t, blockRange(3, 7), readBlocks(t, it, 5))
+	_, err := it.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestBlockIteratorClose(t *testing.T) {
+	source := &mockBlockSource{blocks: 2}
+	it := newBlockIterator(reqContext.Background(), source, 0, math.MaxUint64, 2, time.Millisecond)
+
+	assert.Equal(t, blockRange(0, 1), readBlocks(t, it, 2))
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		it.Close()
+	}()
+	_, err := it.Next()
+	assert.Equal(t, reqContext.Canceled, err)
+
+	// Parent context cancellation
+	ctx, cancel := reqContext.WithTimeout(reqContext.Background(), 5*time.Millisecond)
+	defer cancel()
+	it = newBlockIterator(ctx, source, 2, math.MaxUint64, 2, time.Millisecond)
+	_, err = it.Next()
+	assert.Equal(t, reqContext.DeadlineExceeded, err)
+}
+
+func TestBlockIteratorCloseCancelsFetches(t *testing.T) {
+	source := &mockBlockSource{blocks: 20, hang: true}
+	it := newBlockIterator(reqContext.Background(), source, 0, 19, 4, 0)
+
+	go func() {
+		// Close once the fetches are in flight, and some more time for extra fetches
+		for source.fetches() < 4 {
+			time.Sleep(time.Millisecond)
+		}
+		time.Sleep(10 * time.Millisecond)
+		it.Close()
+	}()
+	_, err := it.Next()
+	assert.Equal(t, reqContext.Canceled, err)
+
+	// The fetches in flight are cancelled
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		source.mutex.Lock()
+		fetching, cancelled, maxFetched := source.fetching, source.cancelled, source.maxFetched
+		source.mutex.Unlock()
+		if fetching == 0 {
+			assert.Equal(t, 4, cancelled, "expected the fetches in flight to be cancelled")
+			assert.Equal(t, 4, maxFetched, "expected the fetches to be bounded by the parallelism")
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d fetches weren't cancelled", fetching)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestBlockIteratorError(t *testing.T) {
+	source := &mockBlockSource{blocks: 20, failBlock: 12}
+	it := newBlockIterator(reqContext.Background(), source, 10, 19, 4, 0)
+	defer it.Close()
+
+	// The blocks before the failed block are delivered
+	assert.Equal(t, blockRange(10, 11), readBlocks(t, it, 2))
+	_, err := it.Next()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "block not available")
+	}
+	_, err2 := it.Next()
+	assert.Equal(t, err, err2, "expected error to be final")
+}
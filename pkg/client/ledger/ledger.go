@@ -8,6 +8,7 @@ SPDX-License-Identifier: Apache-2.0
 package ledger
 
 import (
+	reqContext "context"
 	"math/rand"
 	"time"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/blockdecoder"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/channel"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/chconfig"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
@@ -36,12 +38,13 @@ const (
 // An application that requires interaction with multiple channels should create a separate
 // instance of the ledger client for each channel. Ledger client supports specific queries only.
 type Client struct {
-	provider  core.Providers
-	identity  context.Identity
-	discovery fab.DiscoveryService
-	ledger    *channel.Ledger
-	filter    TargetFilter
-	chName    string
+	provider       core.Providers
+	identity       context.Identity
+	discovery      fab.DiscoveryService
+	channelService fab.ChannelService
+	ledger         *channel.Ledger
+	filter         TargetFilter
+	chName         string
 }
 
 // Context holds the providers and services needed to create a Client.
@@ -76,11 +79,12 @@ func New(c Context, chName string, opts ...ClientOption) (*Client, error) {
 	}
 
 	ledgerClient := Client{
-		provider:  c,
-		identity:  c,
-		discovery: c.DiscoveryService,
-		ledger:    l,
-		chName:    chName,
+		provider:       c,
+		identity:       c,
+		discovery:      c.DiscoveryService,
+		channelService: c.ChannelService,
+		ledger:         l,
+		chName:         chName,
 	}
 
 	for _, opt := range opts {
@@ -106,6 +110,10 @@ func New(c Context, chName string, opts ...ClientOption) (*Client, error) {
 // QueryInfo queries for various useful information on the state of the channel
 // (height, known peers).
 func (c *Client) QueryInfo(options ...RequestOption) (*fab.BlockchainInfoResponse, error) {
+	return c.queryInfo(reqContext.Background(), options...)
+}
+
+func (c *Client) queryInfo(reqCtx reqContext.Context, options ...RequestOption) (*fab.BlockchainInfoResponse, error) {
 
 	opts, err := c.prepareRequestOpts(options...)
 	if err != nil {
@@ -118,7 +126,7 @@ func (c *Client) QueryInfo(options ...RequestOption) (*fab.BlockchainInfoRespons
 		return nil, errors.WithMessage(err, "failed to determine target peers for QueryBlockByHash")
 	}
 
-	responses, err := c.ledger.QueryInfo(peersToTxnProcessors(targets))
+	responses, err := c.ledger.QueryInfoWithContext(reqCtx, peersToTxnProcessors(targets))
 	if err != nil && len(responses) == 0 {
 		return nil, errors.WithMessage(err, "Failed to QueryBlockByHash")
 	}
@@ -190,6 +198,10 @@ func (c *Client) QueryBlockByHash(blockHash []byte, options ...RequestOption) (*
 // blockNumber: The number which is the ID of the Block.
 // It returns the block.
 func (c *Client) QueryBlock(blockNumber int, options ...RequestOption) (*common.Block, error) {
+	return c.queryBlock(reqContext.Background(), blockNumber, options...)
+}
+
+func (c *Client) queryBlock(reqCtx reqContext.Context, blockNumber int, options ...RequestOption) (*common.Block, error) {
 
 	opts, err := c.prepareRequestOpts(options...)
 	if err != nil {
@@ -202,7 +214,7 @@ func (c *Client) QueryBlock(blockNumber int, options ...RequestOption) (*common.
 		return nil, errors.WithMessage(err, "failed to determine target peers for QueryBlock")
 	}
 
-	responses, err := c.ledger.QueryBlock(blockNumber, peersToTxnProcessors(targets))
+	responses, err := c.ledger.QueryBlockWithContext(reqCtx, blockNumber, peersToTxnProcessors(targets))
 	if err != nil && len(responses) == 0 {
 		return nil, errors.WithMessage(err, "Failed to QueryBlock")
 	}
@@ -228,6 +240,36 @@ func (c *Client) QueryBlock(blockNumber int, options ...RequestOption) (*common.
 	return response, err
 }
 
+// QueryDecodedBlockByHash queries the ledger for Block by block hash, as QueryBlockByHash does.
+// Returns the decoded block.
+func (c *Client) QueryDecodedBlockByHash(blockHash []byte, options ...RequestOption) (*blockdecoder.Block, error) {
+	block, err := c.QueryBlockByHash(blockHash, options...)
+	if err != nil {
+		return nil, err
+	}
+	return blockdecoder.Decode(block)
+}
+
+// QueryDecodedBlock queries the ledger for Block by block number, as QueryBlock does.
+// Returns the decoded block.
+func (c *Client) QueryDecodedBlock(blockNumber int, options ...RequestOption) (*blockdecoder.Block, error) {
+	block, err := c.QueryBlock(blockNumber, options...)
+	if err != nil {
+		return nil, err
+	}
+	return blockdecoder.Decode(block)
+}
+
+// QueryDecodedTransaction queries the ledger for Transaction by number, as QueryTransaction does.
+// Returns the decoded transaction with its validation code.
+func (c *Client) QueryDecodedTransaction(transactionID fab.TransactionID, options ...RequestOption) (*blockdecoder.Transaction, error) {
+	processedTx, err := c.QueryTransaction(transactionID, options...)
+	if err != nil {
+		return nil, err
+	}
+	return blockdecoder.DecodeProcessedTransaction(processedTx)
+}
+
 // QueryTransaction queries the ledger for Transaction by number.
 // This query will be made to specified targets.
 // Returns the ProcessedTransaction information containing the transaction.
@@ -332,11 +374,12 @@ func (c *Client) calculateTargets(opts Opts) ([]fab.Peer, error) {
 		return nil, errors.New("If targets are provided, filter cannot be provided")
 	}
 
-	targets := opts.Targets
+	// Targets are shuffled below, so the given targets are copied for concurrent queries
+	targets := append([]fab.Peer(nil), opts.Targets...)
 	targetFilter := opts.TargetFilter
 
 	var err error
-	if targets == nil {
+	if opts.Targets == nil {
 		// Retrieve targets from discovery
 		targets, err = c.discovery.GetPeers()
 		if err != nil {
@@ -6,7 +6,13 @@ SPDX-License-Identifier: Apache-2.0
 
 package ledger
 
-import "github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+import (
+	reqContext "context"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/pkg/errors"
+)
 
 const (
 	minTargets = 1
@@ -39,6 +45,11 @@ type Opts struct {
 	TargetFilter TargetFilter // target filter
 	MaxTargets   int          // maximum number of targets to select
 	MinTargets   int          // min number of targets that have to respond with no error (or agree on result)
+
+	ParentContext  reqContext.Context // parent context of the block iterator
+	FollowInterval time.Duration      // interval at which the block iterator polls for new blocks (0 to not follow)
+	Parallelism    int                // number of blocks the block iterator fetches in parallel
+	Orderer        fab.Orderer        // orderer the block iterator falls back to
 }
 
 //WithTargets encapsulates fab.Peer targets to ledger RequestOption
@@ -72,3 +83,41 @@ func WithMinTargets(minTargets int) RequestOption {
 		return nil
 	}
 }
+
+//WithParentContext encapsulates the parent context of the block iterator to ledger RequestOption
+func WithParentContext(parentContext reqContext.Context) RequestOption {
+	return func(opts *Opts) error {
+		opts.ParentContext = parentContext
+		return nil
+	}
+}
+
+//WithFollow makes the block iterator wait for new blocks, polling the block height at the given interval
+func WithFollow(interval time.Duration) RequestOption {
+	return func(opts *Opts) error {
+		if interval <= 0 {
+			return errors.New("follow interval must be positive")
+		}
+		opts.FollowInterval = interval
+		return nil
+	}
+}
+
+//WithParallelism encapsulates the number of blocks the block iterator fetches in parallel to ledger RequestOption
+func WithParallelism(parallelism int) RequestOption {
+	return func(opts *Opts) error {
+		if parallelism <= 0 {
+			return errors.New("parallelism must be positive")
+		}
+		opts.Parallelism = parallelism
+		return nil
+	}
+}
+
+//WithOrderer encapsulates the orderer the block iterator falls back to when the target peers are unavailable
+func WithOrderer(orderer fab.Orderer) RequestOption {
+	return func(opts *Opts) error {
+		opts.Orderer = orderer
+		return nil
+	}
+}
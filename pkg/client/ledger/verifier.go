@@ -0,0 +1,435 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/asn1"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/channel/membership"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/chconfig"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mspCfg "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	protos_utils "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+// VerificationFailure identifies the first block that failed verification and why.
+type VerificationFailure struct {
+	BlockNumber uint64
+	Reason      string
+}
+
+// String describes the failure.
+func (f *VerificationFailure) String() string {
+	return fmt.Sprintf("block %d failed verification: %s", f.BlockNumber, f.Reason)
+}
+
+// VerificationResult is the result of the verification of a block range.
+type VerificationResult struct {
+	// Verified is the number of blocks that passed verification
+	Verified uint64
+	// Failure is the first block that failed verification, nil if all blocks passed
+	Failure *VerificationFailure
+}
+
+// VerifyBlock verifies the block with the given number, as VerifyBlockRange does.
+func (c *Client) VerifyBlock(blockNumber uint64, options ...RequestOption) (*VerificationResult, error) {
+	return c.VerifyBlockRange(blockNumber, blockNumber, options...)
+}
+
+// VerifyBlockRange verifies that the blocks of the range weren't tampered with. For each block, it checks
+// the data hash of the header against the block data, the previous hash against the header of the prior
+// block, and the orderer signatures against the MSPs of the orderer organizations of the channel
+// configuration the block refers to. A block passes with one valid orderer signature, as with the
+// default block validation policy. The genesis block has no orderer signatures.
+//
+// The config blocks come from the queried peers as the other blocks do, so the orderer MSPs of a config
+// block are trusted only if they are orderer MSPs of the channel configuration of the client's channel service.
+// Otherwise the config block is verified against the previous config, as are the config blocks in the range,
+// which are signed by the orderers of the previous config, back to a config block whose orderer MSPs are
+// trusted. The blocks of a genesis block whose orderer MSPs are no longer in the channel can't be verified.
+// The blocks are queried as QueryBlockRange does, and verification stops at the first failed block.
+func (c *Client) VerifyBlockRange(from uint64, to uint64, options ...RequestOption) (*VerificationResult, error) {
+	opts, err := c.prepareRequestOpts(options...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get opts for VerifyBlockRange")
+	}
+
+	trustedMSPs, err := c.ordererMSPs()
+	if err != nil {
+		return nil, errors.WithMessage(err, "loading orderer MSPs of channel configuration failed")
+	}
+
+	source := &ledgerBlockSource{client: c, options: options, orderer: opts.Orderer}
+	ctx := membership.Context{Providers: c.provider}
+	parent := parentContext(opts)
+	block := func(blockNumber uint64) (*common.Block, error) {
+		return source.block(parent, blockNumber)
+	}
+	verifier := newBlockVerifier(block, func(configBlock *common.Block) (fab.ChannelMembership, bool, error) {
+		ordererCfg, err := ordererConfig(c.chName, configBlock)
+		if err != nil {
+			return nil, false, err
+		}
+		orderers, err := membership.New(ctx, ordererCfg)
+		if err != nil {
+			return nil, false, err
+		}
+		trusted, err := containsMSPs(trustedMSPs, ordererCfg.Msps())
+		if err != nil {
+			return nil, false, err
+		}
+		return orderers, trusted, nil
+	})
+
+	// The range starts at the prior block of the previous hash
+	start := from
+	if from > 0 {
+		start = from - 1
+	}
+	it, err := c.QueryBlockRange(start, to, options...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "VerifyBlockRange failed")
+	}
+	defer it.Close()
+
+	result := &VerificationResult{}
+	for expected := start; ; expected++ {
+		block, err := it.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.WithMessage(err, "VerifyBlockRange failed")
+		}
+
+		if expected < from {
+			verifier.previous = block
+			continue
+		}
+
+		failure, err := verifier.verify(block, expected)
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("verification of block %d failed", expected))
+		}
+		if failure != nil {
+			result.Failure = failure
+			return result, nil
+		}
+		result.Verified++
+	}
+
+	if result.Verified == 0 {
+		return nil, errors.Errorf("no blocks in range [%d, %d]", from, to)
+	}
+	return result, nil
+}
+
+// ordererMSPs returns the orderer MSPs of the channel configuration of the channel service, which are trusted
+func (c *Client) ordererMSPs() (map[string]*mspCfg.MSPConfig, error) {
+	if c.channelService == nil {
+		return nil, errors.New("channel service is required")
+	}
+	channelConfig, err := c.channelService.Config()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := channelConfig.Query()
+	if err != nil {
+		return nil, err
+	}
+	msps, err := chconfig.OrdererMSPs(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return mspsByID(msps)
+}
+
+// blockVerifier verifies consecutive blocks
+type blockVerifier struct {
+	// block returns the block with the given number, such as the config blocks the blocks refer to
+	block func(blockNumber uint64) (*common.Block, error)
+	// ordererMembership returns the membership of the orderer organizations of the config block,
+	// and whether their MSPs are trusted
+	ordererMembership func(configBlock *common.Block) (fab.ChannelMembership, bool, error)
+	// memberships are the orderer memberships of the verified config blocks
+	memberships map[uint64]fab.ChannelMembership
+	// previous is the prior block, if known
+	previous *common.Block
+}
+
+func newBlockVerifier(block func(blockNumber uint64) (*common.Block, error), ordererMembership func(configBlock *common.Block) (fab.ChannelMembership, bool, error)) *blockVerifier {
+	return &blockVerifier{
+		block:             block,
+		ordererMembership: ordererMembership,
+		memberships:       make(map[uint64]fab.ChannelMembership),
+	}
+}
+
+// verify returns the failure of a tampered block, or an error if the block couldn't be verified
+func (v *blockVerifier) verify(block *common.Block, blockNumber uint64) (*VerificationFailure, error) {
+	reason, err := v.check(block, blockNumber, v.previous)
+	if err != nil {
+		return nil, err
+	}
+	if reason != "" {
+		return &VerificationFailure{BlockNumber: blockNumber, Reason: reason}, nil
+	}
+
+	v.previous = block
+	return nil, nil
+}
+
+// check returns why the block was tampered with, given its prior block if known
+func (v *blockVerifier) check(block *common.Block, blockNumber uint64, prior *common.Block) (string, error) {
+	if block.Header == nil || block.Data == nil {
+		return "missing block header or data", nil
+	}
+	if block.Header.Number != blockNumber {
+		return fmt.Sprintf("block has number %d", block.Header.Number), nil
+	}
+
+	if dataHash := blockDataHash(block.Data); !bytes.Equal(block.Header.DataHash, dataHash) {
+		return fmt.Sprintf("data hash %x doesn't match the hash of the block data %x", block.Header.DataHash, dataHash), nil
+	}
+
+	if prior != nil {
+		if prior.Header == nil {
+			return fmt.Sprintf("missing header of block %d", blockNumber-1), nil
+		}
+		previousHash, err := blockHeaderHash(prior.Header)
+		if err != nil {
+			return "", err
+		}
+		if !bytes.Equal(block.Header.PreviousHash, previousHash) {
+			return fmt.Sprintf("previous hash %x doesn't match the hash of the header of block %d %x", block.Header.PreviousHash, prior.Header.Number, previousHash), nil
+		}
+	}
+
+	if blockNumber == 0 {
+		return "", nil
+	}
+	return v.verifySignatures(block, prior)
+}
+
+// verifySignatures returns why none of the orderer signatures of the block is valid
+func (v *blockVerifier) verifySignatures(block *common.Block, prior *common.Block) (string, error) {
+	configBlockNumber, reason := lastConfigIndex(block)
+	if reason != "" {
+		return reason, nil
+	}
+	if configBlockNumber == block.Header.Number {
+		// The last config of a config block is the block itself, whereas the block is signed
+		// by the orderers of the previous config
+		if prior == nil {
+			var err error
+			if prior, err = v.block(block.Header.Number - 1); err != nil {
+				return "", errors.WithMessage(err, fmt.Sprintf("loading block %d failed", block.Header.Number-1))
+			}
+		}
+		if configBlockNumber, reason = lastConfigIndex(prior); reason != "" {
+			return fmt.Sprintf("block %d: %s", block.Header.Number-1, reason), nil
+		}
+		if configBlockNumber >= block.Header.Number {
+			return fmt.Sprintf("last config %d of block %d isn't a previous config", configBlockNumber, block.Header.Number-1), nil
+		}
+	}
+
+	orderers, reason, err := v.membership(configBlockNumber)
+	if err != nil {
+		return "", errors.WithMessage(err, fmt.Sprintf("loading orderer MSPs of config block %d failed", configBlockNumber))
+	}
+	if reason != "" {
+		return reason, nil
+	}
+
+	metadata := &common.Metadata{}
+	if err := proto.Unmarshal(block.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES], metadata); err != nil {
+		return fmt.Sprintf("invalid signatures metadata: %s", err), nil
+	}
+	if len(metadata.Signatures) == 0 {
+		return "block has no orderer signatures", nil
+	}
+
+	headerBytes, err := blockHeaderBytes(block.Header)
+	if err != nil {
+		return "", err
+	}
+
+	var reasons []string
+	for i, signature := range metadata.Signatures {
+		signatureHeader, err := protos_utils.GetSignatureHeader(signature.SignatureHeader)
+		if err != nil {
+			reasons = append(reasons, fmt.Sprintf("signature %d: invalid signature header", i))
+			continue
+		}
+		if err := orderers.Validate(signatureHeader.Creator); err != nil {
+			reasons = append(reasons, fmt.Sprintf("signature %d: signer is not a valid orderer identity: %s", i, err))
+			continue
+		}
+		signedBytes := concatenateBytes(metadata.Value, signature.SignatureHeader, headerBytes)
+		if err := orderers.Verify(signatureHeader.Creator, signedBytes, signature.Signature); err != nil {
+			reasons = append(reasons, fmt.Sprintf("signature %d: %s", i, err))
+			continue
+		}
+		return "", nil
+	}
+	return fmt.Sprintf("no valid orderer signature (%s)", strings.Join(reasons, "; ")), nil
+}
+
+// membership returns the orderer membership of the config block, or why the config block can't be trusted.
+// A config block whose orderer MSPs aren't trusted is verified against its previous config.
+func (v *blockVerifier) membership(configBlockNumber uint64) (fab.ChannelMembership, string, error) {
+	if m, ok := v.memberships[configBlockNumber]; ok {
+		return m, "", nil
+	}
+
+	configBlock, err := v.block(configBlockNumber)
+	if err != nil {
+		return nil, "", err
+	}
+	m, trusted, err := v.ordererMembership(configBlock)
+	if err != nil {
+		return nil, "", err
+	}
+	if !trusted {
+		if configBlockNumber == 0 {
+			return nil, "orderer MSPs of the genesis block aren't MSPs of the channel configuration", nil
+		}
+		prior, err := v.block(configBlockNumber - 1)
+		if err != nil {
+			return nil, "", errors.WithMessage(err, fmt.Sprintf("loading block %d failed", configBlockNumber-1))
+		}
+		reason, err := v.check(configBlock, configBlockNumber, prior)
+		if err != nil {
+			return nil, "", err
+		}
+		if reason != "" {
+			return nil, fmt.Sprintf("config block %d failed verification: %s", configBlockNumber, reason), nil
+		}
+	}
+
+	v.memberships[configBlockNumber] = m
+	return m, "", nil
+}
+
+// lastConfigIndex returns the number of the config block of the block, or why the block has none
+func lastConfigIndex(block *common.Block) (uint64, string) {
+	if block.Header == nil || block.Metadata == nil || len(block.Metadata.Metadata) <= int(common.BlockMetadataIndex_LAST_CONFIG) {
+		return 0, "missing block metadata"
+	}
+	lastConfig, err := resource.GetLastConfigFromBlock(block)
+	if err != nil {
+		return 0, fmt.Sprintf("invalid last config metadata: %s", err)
+	}
+	if lastConfig.Index > block.Header.Number {
+		return 0, fmt.Sprintf("last config %d is after the block", lastConfig.Index)
+	}
+	return lastConfig.Index, ""
+}
+
+// ordererConfig returns the configuration of the orderer organizations of the config block
+func ordererConfig(channelID string, configBlock *common.Block) (fab.ChannelCfg, error) {
+	if configBlock.Data == nil || len(configBlock.Data.Data) == 0 {
+		return nil, errors.New("config block has no data")
+	}
+	configEnvelope, err := resource.CreateConfigEnvelope(configBlock.Data.Data[0])
+	if err != nil {
+		return nil, err
+	}
+	return chconfig.ExtractOrdererCfg(channelID, configEnvelope.Config)
+}
+
+// containsMSPs returns whether each MSP is a trusted MSP, with the same MSP ID and config
+func containsMSPs(trusted map[string]*mspCfg.MSPConfig, msps []*mspCfg.MSPConfig) (bool, error) {
+	for _, m := range msps {
+		mspID, err := mspName(m)
+		if err != nil {
+			return false, err
+		}
+		t, ok := trusted[mspID]
+		if !ok || !proto.Equal(m, t) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// mspsByID returns the MSPs by MSP ID
+func mspsByID(msps []*mspCfg.MSPConfig) (map[string]*mspCfg.MSPConfig, error) {
+	byID := make(map[string]*mspCfg.MSPConfig)
+	for _, m := range msps {
+		mspID, err := mspName(m)
+		if err != nil {
+			return nil, err
+		}
+		byID[mspID] = m
+	}
+	return byID, nil
+}
+
+// mspName returns the MSP ID of the fabric MSP config
+func mspName(m *mspCfg.MSPConfig) (string, error) {
+	fabricConfig := &mspCfg.FabricMSPConfig{}
+	if err := proto.Unmarshal(m.Config, fabricConfig); err != nil {
+		return "", errors.Wrap(err, "unmarshal fabric MSP config failed")
+	}
+	return fabricConfig.Name, nil
+}
+
+type asn1Header struct {
+	Number       *big.Int
+	PreviousHash []byte
+	DataHash     []byte
+}
+
+// blockHeaderBytes returns the ASN.1 encoding of the header that the previous hash and orderer signatures refer to
+func blockHeaderBytes(header *common.BlockHeader) ([]byte, error) {
+	headerBytes, err := asn1.Marshal(asn1Header{
+		Number:       new(big.Int).SetUint64(header.Number),
+		PreviousHash: header.PreviousHash,
+		DataHash:     header.DataHash,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "ASN.1 encoding of block header failed")
+	}
+	return headerBytes, nil
+}
+
+// blockHeaderHash returns the hash of the header, as the previous hash of the next block
+func blockHeaderHash(header *common.BlockHeader) ([]byte, error) {
+	headerBytes, err := blockHeaderBytes(header)
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256(headerBytes)
+	return hash[:], nil
+}
+
+// blockDataHash returns the hash of the block data, as the data hash of the header
+func blockDataHash(data *common.BlockData) []byte {
+	hash := sha256.Sum256(concatenateBytes(data.Data...))
+	return hash[:]
+}
+
+func concatenateBytes(data ...[]byte) []byte {
+	var concatenated []byte
+	for _, d := range data {
+		concatenated = append(concatenated, d...)
+	}
+	return concatenated
+}
@@ -0,0 +1,353 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/asn1"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/chconfig"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+)
+
+// mockOrdererMembership accepts the identities of its MSP and signatures that are the hash of the message
+type mockOrdererMembership struct {
+	mspID string
+}
+
+func (m *mockOrdererMembership) Validate(serializedID []byte) error {
+	identity := &msp.SerializedIdentity{}
+	if err := proto.Unmarshal(serializedID, identity); err != nil {
+		return err
+	}
+	if identity.Mspid != m.mspID {
+		return errors.Errorf("unknown MSP %s", identity.Mspid)
+	}
+	return nil
+}
+
+func (m *mockOrdererMembership) Verify(serializedID []byte, msg []byte, sig []byte) error {
+	hash := sha256.Sum256(msg)
+	if !bytes.Equal(hash[:], sig) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+func marshalOrPanic(msg proto.Message) []byte {
+	bytes, err := proto.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return bytes
+}
+
+// signBlock adds the signature of an orderer of the given MSP to the block
+func signBlock(block *common.Block, mspID string) {
+	signatureHeader := marshalOrPanic(&common.SignatureHeader{
+		Creator: marshalOrPanic(&msp.SerializedIdentity{Mspid: mspID, IdBytes: []byte("orderer")}),
+		Nonce:   []byte("nonce"),
+	})
+	headerBytes, err := blockHeaderBytes(block.Header)
+	if err != nil {
+		panic(err)
+	}
+	signature := sha256.Sum256(concatenateBytes(signatureHeader, headerBytes))
+
+	metadata := &common.Metadata{}
+	if err := proto.Unmarshal(block.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES], metadata); err != nil {
+		panic(err)
+	}
+	metadata.Signatures = append(metadata.Signatures, &common.MetadataSignature{SignatureHeader: signatureHeader, Signature: signature[:]})
+	block.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES] = marshalOrPanic(metadata)
+}
+
+// newTestChain returns a chain of blocks signed by OrdererMSP whose config block is block 0
+func newTestChain(count int) []*common.Block {
+	return newTestConfigChain(count, map[int]string{0: "OrdererMSP"}, nil)
+}
+
+// newTestConfigChain returns a chain of blocks with the config blocks of the given orderer MSPs.
+// The blocks are signed by the orderer MSP of their last config, except for config blocks, which are
+// signed by that of the previous config, and the blocks of the given signers.
+func newTestConfigChain(count int, configs map[int]string, signers map[int]string) []*common.Block {
+	var blocks []*common.Block
+	var previousHash []byte
+	lastConfig := 0
+	for i := 0; i < count; i++ {
+		data := &common.BlockData{Data: [][]byte{[]byte("tx1"), []byte("tx2")}}
+		signer := configs[lastConfig]
+		if mspID, ok := configs[i]; ok {
+			data = &common.BlockData{Data: [][]byte{[]byte("config:" + mspID)}}
+			lastConfig = i
+		}
+		if mspID, ok := signers[i]; ok {
+			signer = mspID
+		}
+
+		block := &common.Block{
+			Header: &common.BlockHeader{Number: uint64(i), PreviousHash: previousHash, DataHash: blockDataHash(data)},
+			Data:   data,
+			Metadata: &common.BlockMetadata{Metadata: [][]byte{
+				{},
+				marshalOrPanic(&common.Metadata{Value: marshalOrPanic(&common.LastConfig{Index: uint64(lastConfig)})}),
+				{},
+				{},
+			}},
+		}
+		if i > 0 {
+			signBlock(block, signer)
+		}
+
+		hash, err := blockHeaderHash(block.Header)
+		if err != nil {
+			panic(err)
+		}
+		previousHash = hash
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+// newTestVerifier returns a verifier of the blocks whose config blocks are trusted if their orderer MSP
+// is one of the trusted MSPs
+func newTestVerifier(blocks []*common.Block, trustedMSPs ...string) *blockVerifier {
+	return newBlockVerifier(
+		func(blockNumber uint64) (*common.Block, error) {
+			if blockNumber >= uint64(len(blocks)) {
+				return nil, errors.Errorf("block %d not found", blockNumber)
+			}
+			return blocks[blockNumber], nil
+		},
+		func(configBlock *common.Block) (fab.ChannelMembership, bool, error) {
+			if len(configBlock.Data.Data) != 1 || !strings.HasPrefix(string(configBlock.Data.Data[0]), "config:") {
+				return nil, false, errors.Errorf("block %d is not a config block", configBlock.Header.Number)
+			}
+			mspID := strings.TrimPrefix(string(configBlock.Data.Data[0]), "config:")
+			trusted := false
+			for _, trustedMSP := range trustedMSPs {
+				trusted = trusted || trustedMSP == mspID
+			}
+			return &mockOrdererMembership{mspID: mspID}, trusted, nil
+		},
+	)
+}
+
+func verifyChain(t *testing.T, blocks []*common.Block, trustedMSPs ...string) *VerificationFailure {
+	if len(trustedMSPs) == 0 {
+		trustedMSPs = []string{"OrdererMSP"}
+	}
+	verifier := newTestVerifier(blocks, trustedMSPs...)
+	for i, block := range blocks {
+		failure, err := verifier.verify(block, uint64(i))
+		if err != nil {
+			t.Fatalf("Failed to verify block %d: %s", i, err)
+		}
+		if failure != nil {
+			return failure
+		}
+	}
+	return nil
+}
+
+func TestVerifyBlocks(t *testing.T) {
+	assert.Nil(t, verifyChain(t, newTestChain(5)))
+
+	// Blocks signed by other orgs besides the orderer are valid
+	blocks := newTestChain(3)
+	signBlock(blocks[2], "Org1MSP")
+	assert.Nil(t, verifyChain(t, blocks))
+}
+
+func TestVerifyTamperedBlocks(t *testing.T) {
+	tamper := map[string]func(blocks []*common.Block){
+		"data hash":     func(blocks []*common.Block) { blocks[2].Data.Data[1] = []byte("tx3") },
+		"previous hash": func(blocks []*common.Block) { blocks[2].Header.PreviousHash = []byte("previous") },
+		"block number":  func(blocks []*common.Block) { blocks[2] = blocks[3] },
+		"no signatures": func(blocks []*common.Block) { blocks[2].Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES] = nil },
+		"no metadata":   func(blocks []*common.Block) { blocks[2].Metadata = nil },
+		"last config": func(blocks []*common.Block) {
+			blocks[2].Metadata.Metadata[common.BlockMetadataIndex_LAST_CONFIG] = []byte("invalid")
+		},
+		"signer org": func(blocks []*common.Block) {
+			blocks[2].Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES] = nil
+			signBlock(blocks[2], "Org1MSP")
+		},
+		"signed header": func(blocks []*common.Block) {
+			// Consistent data and data hash, but not those the orderer signed
+			blocks[2].Data.Data = nil
+			blocks[2].Header.DataHash = blockDataHash(blocks[2].Data)
+		},
+		"invalid header": func(blocks []*common.Block) { blocks[2].Header = nil },
+	}
+
+	for desc, tamperWith := range tamper {
+		blocks := newTestChain(4)
+		tamperWith(blocks)
+		failure := verifyChain(t, blocks)
+		if assert.NotNil(t, failure, "expected failure for tampered %s", desc) {
+			assert.Equal(t, uint64(2), failure.BlockNumber, "unexpected failed block for tampered %s", desc)
+			assert.NotEmpty(t, failure.Reason)
+		}
+	}
+}
+
+func TestVerifyConfigBlocks(t *testing.T) {
+	// Block 2 adds the orderer organization of Orderer2MSP, which isn't in the channel configuration
+	configs := map[int]string{0: "OrdererMSP", 2: "Orderer2MSP"}
+	assert.Nil(t, verifyChain(t, newTestConfigChain(5, configs, nil)))
+
+	// Config block 2 is verified against the config of block 0 when verifying the blocks after it
+	blocks := newTestConfigChain(5, configs, nil)
+	verifier := newTestVerifier(blocks, "OrdererMSP")
+	verifier.previous = blocks[2]
+	failure, err := verifier.verify(blocks[3], 3)
+	if err != nil {
+		t.Fatalf("Failed to verify block 3: %s", err)
+	}
+	assert.Nil(t, failure)
+
+	// Config blocks are signed by the orderers of the previous config rather than their own
+	forged := newTestConfigChain(5, configs, map[int]string{2: "Orderer2MSP"})
+	failure = verifyChain(t, forged)
+	if assert.NotNil(t, failure, "expected failure for config block signed by its own orderers") {
+		assert.Equal(t, uint64(2), failure.BlockNumber)
+	}
+
+	// The forged config block fails the blocks that refer to it
+	verifier = newTestVerifier(forged, "OrdererMSP")
+	verifier.previous = forged[2]
+	failure, err = verifier.verify(forged[3], 3)
+	if err != nil {
+		t.Fatalf("Failed to verify block 3: %s", err)
+	}
+	if assert.NotNil(t, failure, "expected failure for block of forged config block") {
+		assert.Equal(t, uint64(3), failure.BlockNumber)
+		assert.Contains(t, failure.Reason, "config block 2 failed verification")
+	}
+
+	// Once the channel configuration has the new orderer organization, the config block is trusted
+	assert.Nil(t, verifyChain(t, newTestConfigChain(5, map[int]string{0: "Orderer2MSP"}, nil), "Orderer2MSP"))
+
+	// Blocks of a genesis block whose orderer MSPs aren't in the channel configuration can't be verified
+	failure = verifyChain(t, newTestChain(3), "Orderer2MSP")
+	if assert.NotNil(t, failure, "expected failure for untrusted genesis block") {
+		assert.Equal(t, uint64(1), failure.BlockNumber)
+	}
+}
+
+func TestTrustedOrdererMSPs(t *testing.T) {
+	newConfigBlock := func(rootCA string) *common.Block {
+		builder := &mocks.MockConfigBlockBuilder{
+			MockConfigGroupBuilder: mocks.MockConfigGroupBuilder{
+				ModPolicy:      "Admins",
+				MSPNames:       []string{"Org1MSP", "Org2MSP"},
+				OrdererAddress: "localhost:7054",
+				RootCA:         rootCA,
+			},
+		}
+		return builder.Build()
+	}
+	newConfig := func(configBlock *common.Block) *common.Config {
+		configEnvelope, err := resource.CreateConfigEnvelope(configBlock.Data.Data[0])
+		if err != nil {
+			t.Fatalf("Failed to get config envelope: %s", err)
+		}
+		return configEnvelope.Config
+	}
+
+	// The trusted MSPs are the orderer MSPs of the channel configuration
+	configBlock := newConfigBlock("root")
+	channelCfg, err := chconfig.ExtractChannelCfg("mychannel", newConfig(configBlock))
+	if err != nil {
+		t.Fatalf("Failed to extract channel config: %s", err)
+	}
+	msps, err := chconfig.OrdererMSPs(channelCfg)
+	if err != nil {
+		t.Fatalf("Failed to get orderer MSPs: %s", err)
+	}
+	trustedMSPs, err := mspsByID(msps)
+	if err != nil {
+		t.Fatalf("Failed to get orderer MSPs: %s", err)
+	}
+	assert.Len(t, trustedMSPs, 1)
+	assert.Contains(t, trustedMSPs, "OrdererMSP")
+
+	ordererCfg, err := ordererConfig("mychannel", configBlock)
+	if err != nil {
+		t.Fatalf("Failed to extract orderer config: %s", err)
+	}
+	trusted, err := containsMSPs(trustedMSPs, ordererCfg.Msps())
+	assert.NoError(t, err)
+	assert.True(t, trusted, "expected orderer MSPs of config block to be trusted")
+
+	// A forged config block that names an application MSP in its orderer group isn't trusted
+	config := newConfig(configBlock)
+	ordererGroup := config.ChannelGroup.Groups["Orderer"]
+	ordererGroup.Groups["Org1MSP"] = config.ChannelGroup.Groups["Application"].Groups["Org1MSP"]
+	ordererCfg, err = chconfig.ExtractOrdererCfg("mychannel", config)
+	if err != nil {
+		t.Fatalf("Failed to extract orderer config: %s", err)
+	}
+	trusted, err = containsMSPs(trustedMSPs, ordererCfg.Msps())
+	assert.NoError(t, err)
+	assert.False(t, trusted, "expected application MSP in orderer group not to be trusted")
+
+	// Nor is an orderer MSP of the same ID with another config
+	ordererCfg, err = ordererConfig("mychannel", newConfigBlock("other"))
+	if err != nil {
+		t.Fatalf("Failed to extract orderer config: %s", err)
+	}
+	trusted, err = containsMSPs(trustedMSPs, ordererCfg.Msps())
+	assert.NoError(t, err)
+	assert.False(t, trusted, "expected orderer MSP with other config not to be trusted")
+}
+
+func TestVerifyBlockErrors(t *testing.T) {
+	blocks := newTestChain(3)
+	blocks[2].Metadata.Metadata[common.BlockMetadataIndex_LAST_CONFIG] = marshalOrPanic(&common.Metadata{Value: marshalOrPanic(&common.LastConfig{Index: 1})})
+	signBlock(blocks[2], "OrdererMSP")
+
+	verifier := newTestVerifier(blocks, "OrdererMSP")
+	verifier.previous = blocks[1]
+	_, err := verifier.verify(blocks[2], 2)
+	assert.Error(t, err, "expected error for last config that isn't a config block")
+
+	verifier = newTestVerifier(blocks[2:], "OrdererMSP")
+	_, err = verifier.verify(blocks[2], 2)
+	assert.Error(t, err, "expected error for missing config block")
+}
+
+func TestBlockHeaderBytes(t *testing.T) {
+	header := &common.BlockHeader{Number: 42, PreviousHash: []byte("previous"), DataHash: []byte("data")}
+	headerBytes, err := blockHeaderBytes(header)
+	if err != nil {
+		t.Fatalf("Failed to encode header: %s", err)
+	}
+
+	// Same encoding as the int64 block number of earlier Fabric releases
+	expected, err := asn1.Marshal(struct {
+		Number       int64
+		PreviousHash []byte
+		DataHash     []byte
+	}{42, []byte("previous"), []byte("data")})
+	if err != nil {
+		t.Fatalf("Failed to encode header: %s", err)
+	}
+	assert.Equal(t, expected, headerBytes)
+}
@@ -0,0 +1,317 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package worldstate materializes a read-only local replica of the world state of a channel from its blocks.
+// The write sets of the valid transactions of each block are applied to a key-value store, per chaincode
+// namespace, along with the version and history of each key and a checkpoint of the last applied block,
+// so that materialization resumes from the next block. Private data is not materialized.
+package worldstate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/blockdecoder"
+	"github.com/hyperledger/fabric-sdk-go/pkg/logging"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+var logger = logging.NewLogger("fabric_sdk_go")
+
+const checkpointKey = "checkpoint"
+
+// Version is the height of the transaction that last wrote a key, as in the peer's state database.
+type Version struct {
+	BlockNum uint64
+	TxNum    uint64
+}
+
+// less returns true if the version is lower than the other version
+func (v Version) less(other Version) bool {
+	return v.BlockNum < other.BlockNum || (v.BlockNum == other.BlockNum && v.TxNum < other.TxNum)
+}
+
+// VersionedValue is the value of a key with its version.
+type VersionedValue struct {
+	Value   []byte
+	Version Version
+}
+
+// HistoryEntry is a write or delete of a key by a valid transaction.
+type HistoryEntry struct {
+	TxID      string
+	Version   Version
+	Timestamp time.Time
+	Value     []byte
+	IsDelete  bool
+}
+
+// checkpoint is the number of the last applied block
+type checkpoint struct {
+	BlockNum uint64
+}
+
+// BlockIterator delivers the blocks to materialize in order, as the block iterator of the ledger client does.
+type BlockIterator interface {
+	Next() (*common.Block, error)
+}
+
+// Option configures the materializer.
+type Option func(m *Materializer)
+
+// WithNamespaces materializes only the given chaincode namespaces.
+func WithNamespaces(namespaces ...string) Option {
+	return func(m *Materializer) {
+		m.namespaces = make(map[string]bool)
+		for _, ns := range namespaces {
+			m.namespaces[ns] = true
+		}
+	}
+}
+
+// Materializer applies blocks to the world state of its store.
+//
+// The store holds the values, histories and checkpoint as JSON encoded bytes under string keys,
+// so that any store of byte values may be used, such as the file key-value store. Values are written
+// before the checkpoint, and reapplying a block that was partially applied is harmless, so that
+// materialization resumes correctly after a failure.
+type Materializer struct {
+	store      core.KVStore
+	namespaces map[string]bool
+	mutex      sync.RWMutex
+	// next is the number of the next block to apply
+	next uint64
+}
+
+// New returns a materializer of the world state of the store, which resumes from the checkpoint of the store.
+func New(store core.KVStore, opts ...Option) (*Materializer, error) {
+	if store == nil {
+		return nil, errors.New("store is required")
+	}
+
+	m := &Materializer{store: store}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	cp := &checkpoint{}
+	err := m.load(checkpointKey, cp)
+	if err == nil {
+		m.next = cp.BlockNum + 1
+	} else if err != core.ErrKeyValueNotFound {
+		return nil, errors.WithMessage(err, "loading checkpoint failed")
+	}
+
+	return m, nil
+}
+
+// NextBlock returns the number of the next block to apply, from which materialization resumes.
+func (m *Materializer) NextBlock() uint64 {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.next
+}
+
+// Run applies the blocks of the iterator until the end of its range.
+// The iterator should start at NextBlock; blocks that were already applied are skipped.
+func (m *Materializer) Run(it BlockIterator) error {
+	for {
+		block, err := it.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.WithMessage(err, "failed to get next block")
+		}
+		if err := m.Apply(block); err != nil {
+			return err
+		}
+	}
+}
+
+// RunEvents applies the blocks of the block events of the event service until the event channel is closed,
+// which happens once the registration is unregistered.
+func (m *Materializer) RunEvents(events <-chan *fab.BlockEvent) error {
+	for event := range events {
+		if err := m.Apply(event.Block); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Apply applies the writes of the valid endorser transactions of the block and checkpoints the block.
+// Blocks before NextBlock were already applied and are skipped, whereas blocks after NextBlock are rejected.
+// The block must have its transaction validation flags, which blocks delivered by the orderer don't have.
+func (m *Materializer) Apply(block *common.Block) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if block == nil || block.Header == nil {
+		return errors.New("block and block header are required")
+	}
+	if block.Header.Number < m.next {
+		logger.Debugf("Skipping block %d, which was already applied", block.Header.Number)
+		return nil
+	}
+	if block.Header.Number > m.next {
+		return errors.Errorf("expected block %d but got block %d", m.next, block.Header.Number)
+	}
+
+	decoded, err := blockdecoder.Decode(block)
+	if err != nil {
+		return errors.WithMessage(err, "decoding block failed")
+	}
+
+	for i, tx := range decoded.Transactions {
+		if !tx.Validated {
+			return errors.Errorf("transaction %d of block %d has no validation flag", i, decoded.Number)
+		}
+		if tx.Type != common.HeaderType_ENDORSER_TRANSACTION || !tx.IsValid() {
+			continue
+		}
+		if err := m.applyTransaction(tx, Version{BlockNum: decoded.Number, TxNum: uint64(i)}); err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("applying transaction %s of block %d failed", tx.TxID, decoded.Number))
+		}
+	}
+
+	if err := m.save(checkpointKey, &checkpoint{BlockNum: decoded.Number}); err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("saving checkpoint of block %d failed", decoded.Number))
+	}
+	m.next = decoded.Number + 1
+	return nil
+}
+
+// Get returns the value of the key of the namespace, or core.ErrKeyValueNotFound if the key has no value.
+func (m *Materializer) Get(namespace string, key string) (*VersionedValue, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	value := &VersionedValue{}
+	if err := m.load(stateKey(namespace, key), value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// History returns the writes and deletes of the key of the namespace, oldest first.
+func (m *Materializer) History(namespace string, key string) ([]*HistoryEntry, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.history(namespace, key)
+}
+
+func (m *Materializer) applyTransaction(tx *blockdecoder.Transaction, version Version) error {
+	for _, action := range tx.Actions {
+		if action.RWSet == nil {
+			continue
+		}
+		for _, nsRWSet := range action.RWSet.NsRWSets {
+			if m.namespaces != nil && !m.namespaces[nsRWSet.Namespace] {
+				continue
+			}
+			for _, write := range nsRWSet.Writes {
+				entry := &HistoryEntry{TxID: tx.TxID, Version: version, Timestamp: tx.Timestamp, Value: write.Value}
+				if err := m.applyWrite(nsRWSet.Namespace, write.Key, entry); err != nil {
+					return err
+				}
+			}
+			for _, key := range nsRWSet.Deletes {
+				entry := &HistoryEntry{TxID: tx.TxID, Version: version, Timestamp: tx.Timestamp, IsDelete: true}
+				if err := m.applyWrite(nsRWSet.Namespace, key, entry); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// applyWrite updates the value of the key and appends the entry to its history, unless the history
+// already has the entry of a partially applied block
+func (m *Materializer) applyWrite(namespace string, key string, entry *HistoryEntry) error {
+	if entry.IsDelete {
+		if err := m.store.Delete(stateKey(namespace, key)); err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("deleting key %s of namespace %s failed", key, namespace))
+		}
+	} else {
+		value := &VersionedValue{Value: entry.Value, Version: entry.Version}
+		if err := m.save(stateKey(namespace, key), value); err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("saving key %s of namespace %s failed", key, namespace))
+		}
+	}
+
+	history, err := m.history(namespace, key)
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("loading history of key %s of namespace %s failed", key, namespace))
+	}
+	if len(history) > 0 && !history[len(history)-1].Version.less(entry.Version) {
+		return nil
+	}
+	if err := m.save(historyKey(namespace, key), append(history, entry)); err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("saving history of key %s of namespace %s failed", key, namespace))
+	}
+	return nil
+}
+
+func (m *Materializer) history(namespace string, key string) ([]*HistoryEntry, error) {
+	var history []*HistoryEntry
+	err := m.load(historyKey(namespace, key), &history)
+	if err != nil && err != core.ErrKeyValueNotFound {
+		return nil, err
+	}
+	return history, nil
+}
+
+func (m *Materializer) load(key string, value interface{}) error {
+	stored, err := m.store.Load(key)
+	if err != nil {
+		return err
+	}
+	if stored == nil {
+		return core.ErrKeyValueNotFound
+	}
+	bytes, ok := stored.([]byte)
+	if !ok {
+		return errors.Errorf("value of %s is not a byte array", key)
+	}
+	if err := json.Unmarshal(bytes, value); err != nil {
+		return errors.Wrap(err, fmt.Sprintf("unmarshal of %s failed", key))
+	}
+	return nil
+}
+
+func (m *Materializer) save(key string, value interface{}) error {
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("marshal of %s failed", key))
+	}
+	return m.store.Store(key, bytes)
+}
+
+func stateKey(namespace string, key string) string {
+	return "state/" + escape(namespace) + "/" + escape(key)
+}
+
+func historyKey(namespace string, key string) string {
+	return "history/" + escape(namespace) + "/" + escape(key)
+}
+
+// escape escapes the namespaces and keys, which may contain any character, so that the store keys
+// may be file paths
+func escape(s string) string {
+	return strings.Replace(url.PathEscape(s), ".", "%2E", -1)
+}
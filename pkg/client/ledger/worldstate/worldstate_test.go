@@ -0,0 +1,310 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package worldstate
+
+import (
+	"io"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	ledgerutil "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// memStore is an in-memory store of byte values that counts its writes
+type memStore struct {
+	values map[interface{}][]byte
+	writes int
+}
+
+func newMemStore() *memStore {
+	return &memStore{values: make(map[interface{}][]byte)}
+}
+
+func (s *memStore) Store(key interface{}, value interface{}) error {
+	s.values[key] = value.([]byte)
+	s.writes++
+	return nil
+}
+
+func (s *memStore) Load(key interface{}) (interface{}, error) {
+	value, ok := s.values[key]
+	if !ok {
+		return nil, core.ErrKeyValueNotFound
+	}
+	return value, nil
+}
+
+func (s *memStore) Delete(key interface{}) error {
+	delete(s.values, key)
+	return nil
+}
+
+func marshalOrPanic(msg proto.Message) []byte {
+	bytes, err := proto.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return bytes
+}
+
+type testTx struct {
+	txID    string
+	ns      string
+	writes  map[string]string
+	deletes []string
+	code    pb.TxValidationCode
+}
+
+func newTestEnvelope(tx *testTx) []byte {
+	kvRWSet := &kvrwset.KVRWSet{}
+	for key, value := range tx.writes {
+		kvRWSet.Writes = append(kvRWSet.Writes, &kvrwset.KVWrite{Key: key, Value: []byte(value)})
+	}
+	for _, key := range tx.deletes {
+		kvRWSet.Writes = append(kvRWSet.Writes, &kvrwset.KVWrite{Key: key, IsDelete: true})
+	}
+	txRwSet := &rwsetutil.TxRwSet{NsRwSets: []*rwsetutil.NsRwSet{{NameSpace: tx.ns, KvRwSet: kvRWSet}}}
+	results, err := txRwSet.ToProtoBytes()
+	if err != nil {
+		panic(err)
+	}
+
+	actionPayload := &pb.ChaincodeActionPayload{
+		ChaincodeProposalPayload: marshalOrPanic(&pb.ChaincodeProposalPayload{}),
+		Action: &pb.ChaincodeEndorsedAction{
+			ProposalResponsePayload: marshalOrPanic(&pb.ProposalResponsePayload{Extension: marshalOrPanic(&pb.ChaincodeAction{Results: results})}),
+		},
+	}
+	payload := &common.Payload{
+		Header: &common.Header{
+			ChannelHeader: marshalOrPanic(&common.ChannelHeader{Type: int32(common.HeaderType_ENDORSER_TRANSACTION), ChannelId: "mychannel", TxId: tx.txID}),
+		},
+		Data: marshalOrPanic(&pb.Transaction{Actions: []*pb.TransactionAction{{Payload: marshalOrPanic(actionPayload)}}}),
+	}
+	return marshalOrPanic(&common.Envelope{Payload: marshalOrPanic(payload)})
+}
+
+func newTestBlock(number uint64, txs ...*testTx) *common.Block {
+	block := &common.Block{
+		Header:   &common.BlockHeader{Number: number},
+		Data:     &common.BlockData{},
+		Metadata: &common.BlockMetadata{Metadata: [][]byte{{}, {}, {}, {}}},
+	}
+	flags := ledgerutil.NewTxValidationFlags(len(txs))
+	for i, tx := range txs {
+		block.Data.Data = append(block.Data.Data, newTestEnvelope(tx))
+		flags[i] = uint8(tx.code)
+	}
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = flags
+	return block
+}
+
+func newTestBlocks() []*common.Block {
+	return []*common.Block{
+		newTestBlock(0,
+			&testTx{txID: "tx1", ns: "example_cc", writes: map[string]string{"a": "100", "b": "200"}},
+		),
+		newTestBlock(1,
+			&testTx{txID: "tx2", ns: "example_cc", writes: map[string]string{"a": "90", "b": "210"}},
+			&testTx{txID: "tx3", ns: "example_cc", writes: map[string]string{"a": "0"}, code: pb.TxValidationCode_MVCC_READ_CONFLICT},
+			&testTx{txID: "tx4", ns: "other_cc", writes: map[string]string{"a": "other"}},
+		),
+		newTestBlock(2,
+			&testTx{txID: "tx5", ns: "example_cc", deletes: []string{"b"}},
+		),
+	}
+}
+
+// blockIterator delivers the blocks then the error
+type blockIterator struct {
+	blocks []*common.Block
+	err    error
+}
+
+func (it *blockIterator) Next() (*common.Block, error) {
+	if len(it.blocks) == 0 {
+		return nil, it.err
+	}
+	block := it.blocks[0]
+	it.blocks = it.blocks[1:]
+	return block, nil
+}
+
+func TestMaterializer(t *testing.T) {
+	m, err := New(newMemStore())
+	if err != nil {
+		t.Fatalf("Failed to create materializer: %s", err)
+	}
+	assert.Equal(t, uint64(0), m.NextBlock())
+
+	if err := m.Run(&blockIterator{blocks: newTestBlocks(), err: io.EOF}); err != nil {
+		t.Fatalf("Failed to materialize blocks: %s", err)
+	}
+	assert.Equal(t, uint64(3), m.NextBlock())
+
+	// The invalid transaction tx3 isn't applied
+	value, err := m.Get("example_cc", "a")
+	if err != nil {
+		t.Fatalf("Failed to get value: %s", err)
+	}
+	assert.Equal(t, &VersionedValue{Value: []byte("90"), Version: Version{BlockNum: 1, TxNum: 0}}, value)
+
+	value, err = m.Get("other_cc", "a")
+	if err != nil {
+		t.Fatalf("Failed to get value: %s", err)
+	}
+	assert.Equal(t, &VersionedValue{Value: []byte("other"), Version: Version{BlockNum: 1, TxNum: 2}}, value)
+
+	_, err = m.Get("example_cc", "b")
+	assert.Equal(t, core.ErrKeyValueNotFound, err, "expected deleted key to have no value")
+
+	history, err := m.History("example_cc", "b")
+	if err != nil {
+		t.Fatalf("Failed to get history: %s", err)
+	}
+	var txIDs []string
+	for _, entry := range history {
+		txIDs = append(txIDs, entry.TxID)
+	}
+	assert.Equal(t, []string{"tx1", "tx2", "tx5"}, txIDs)
+	assert.Equal(t, []byte("210"), history[1].Value)
+	assert.True(t, history[2].IsDelete)
+
+	history, err = m.History("example_cc", "c")
+	assert.NoError(t, err)
+	assert.Empty(t, history)
+}
+
+func TestMaterializerResume(t *testing.T) {
+	store := newMemStore()
+	blocks := newTestBlocks()
+
+	m, err := New(store)
+	if err != nil {
+		t.Fatalf("Failed to create materializer: %s", err)
+	}
+	if err := m.Run(&blockIterator{blocks: blocks[:2], err: io.EOF}); err != nil {
+		t.Fatalf("Failed to materialize blocks: %s", err)
+	}
+
+	// A new materializer of the store resumes from the checkpoint
+	m, err = New(store)
+	if err != nil {
+		t.Fatalf("Failed to create materializer: %s", err)
+	}
+	assert.Equal(t, uint64(2), m.NextBlock())
+
+	// Blocks that were already applied are skipped
+	writes := store.writes
+	assert.NoError(t, m.Apply(blocks[1]))
+	assert.Equal(t, writes, store.writes, "expected no writes for applied block")
+
+	err = m.Apply(newTestBlock(3))
+	assert.Error(t, err, "expected error for missing block 2")
+
+	events := make(chan *fab.BlockEvent, 1)
+	events <- &fab.BlockEvent{Block: blocks[2]}
+	close(events)
+	assert.NoError(t, m.RunEvents(events))
+	assert.Equal(t, uint64(3), m.NextBlock())
+
+	history, err := m.History("example_cc", "b")
+	if err != nil {
+		t.Fatalf("Failed to get history: %s", err)
+	}
+	assert.Len(t, history, 3)
+}
+
+func TestMaterializerReapplyPartialBlock(t *testing.T) {
+	store := newMemStore()
+	blocks := newTestBlocks()
+
+	m, err := New(store)
+	if err != nil {
+		t.Fatalf("Failed to create materializer: %s", err)
+	}
+	assert.NoError(t, m.Run(&blockIterator{blocks: blocks, err: io.EOF}))
+
+	// Reapplying block 2 as if the checkpoint had failed doesn't duplicate its history
+	m.next = 2
+	assert.NoError(t, m.Apply(blocks[2]))
+	history, err := m.History("example_cc", "b")
+	if err != nil {
+		t.Fatalf("Failed to get history: %s", err)
+	}
+	assert.Len(t, history, 3)
+}
+
+func TestMaterializerNamespaces(t *testing.T) {
+	m, err := New(newMemStore(), WithNamespaces("other_cc"))
+	if err != nil {
+		t.Fatalf("Failed to create materializer: %s", err)
+	}
+	assert.NoError(t, m.Run(&blockIterator{blocks: newTestBlocks(), err: io.EOF}))
+
+	_, err = m.Get("example_cc", "a")
+	assert.Equal(t, core.ErrKeyValueNotFound, err, "expected namespace to be excluded")
+	_, err = m.Get("other_cc", "a")
+	assert.NoError(t, err)
+}
+
+func TestMaterializerMalformedTransaction(t *testing.T) {
+	m, err := New(newMemStore())
+	if err != nil {
+		t.Fatalf("Failed to create materializer: %s", err)
+	}
+
+	// A malformed transaction that the peer flagged as invalid is skipped
+	block := newTestBlock(0,
+		&testTx{txID: "tx1", ns: "example_cc", writes: map[string]string{"a": "100"}},
+		&testTx{code: pb.TxValidationCode_BAD_PAYLOAD},
+	)
+	block.Data.Data[1] = []byte("invalid")
+	if err := m.Apply(block); err != nil {
+		t.Fatalf("Failed to apply block with malformed invalid transaction: %s", err)
+	}
+	assert.Equal(t, uint64(1), m.NextBlock())
+
+	value, err := m.Get("example_cc", "a")
+	if err != nil {
+		t.Fatalf("Failed to get value: %s", err)
+	}
+	assert.Equal(t, []byte("100"), value.Value)
+}
+
+func TestMaterializerErrors(t *testing.T) {
+	_, err := New(nil)
+	assert.Error(t, err, "expected error for missing store")
+
+	m, err := New(newMemStore())
+	if err != nil {
+		t.Fatalf("Failed to create materializer: %s", err)
+	}
+
+	err = m.Run(&blockIterator{blocks: newTestBlocks()[:1], err: errors.New("connection lost")})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "connection lost")
+	}
+	assert.Equal(t, uint64(1), m.NextBlock(), "expected blocks before the error to be applied")
+
+	// Blocks delivered by the orderer have no validation flags
+	block := newTestBlock(1, &testTx{txID: "tx2", ns: "example_cc", writes: map[string]string{"a": "90"}})
+	block.Metadata = nil
+	assert.Error(t, m.Apply(block), "expected error for block without validation flags")
+	assert.Equal(t, uint64(1), m.NextBlock())
+
+	assert.Error(t, m.Apply(nil), "expected error for nil block")
+}
@@ -0,0 +1,200 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	"net"
+	"sort"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+const applicationGroupKey = "Application"
+
+//AnchorPeersRequest used to set the anchor peers of an organization on a channel
+type AnchorPeersRequest struct {
+	// Channel Name (ID)
+	ChannelID string
+	// MSP ID of the organization, defaults to the MSP ID of the signing identity
+	OrgMSPID string
+	// Anchor peers of the organization as host:port
+	AnchorPeers []string
+	// Admin of the organization that signs the config update, defaults to the client's identity
+	SigningIdentity context.Identity
+}
+
+//UpdateAnchorPeers sets the anchor peers of the organization on the channel. The config update is computed
+//against the current channel config, signed by the organization's admin and submitted to the orderer.
+//Nothing is submitted if the organization already has the given anchor peers
+func (rc *Client) UpdateAnchorPeers(req AnchorPeersRequest, options ...RequestOption) error {
+	if req.ChannelID == "" {
+		return errors.New("must provide channel ID")
+	}
+
+	signer := rc.identity
+	if req.SigningIdentity != nil {
+		signer = req.SigningIdentity
+	}
+	if signer == nil {
+		return errors.New("must provide signing user")
+	}
+
+	mspID := req.OrgMSPID
+	if mspID == "" {
+		mspID = signer.MspID()
+	}
+
+	anchorPeers, err := newAnchorPeers(req.AnchorPeers)
+	if err != nil {
+		return err
+	}
+
+	opts, err := rc.prepareRequestOpts(options...)
+	if err != nil {
+		return err
+	}
+
+	orderer, err := rc.requestOrderer(opts)
+	if err != nil {
+		return err
+	}
+
+	original, err := rc.lastConfig(req.ChannelID, orderer)
+	if err != nil {
+		return err
+	}
+
+	updated := proto.Clone(original).(*common.Config)
+	_, orgGroup, err := applicationOrgGroup(updated, mspID)
+	if err != nil {
+		return err
+	}
+
+	value, ok := orgGroup.Values[channelconfig.AnchorPeersKey]
+	if ok {
+		current := &pb.AnchorPeers{}
+		if err := proto.Unmarshal(value.Value, current); err != nil {
+			return errors.Wrap(err, "unmarshal anchor peers from config failed")
+		}
+		if sameAnchorPeers(current, anchorPeers) {
+			logger.Debugf("anchor peers of [%s] on channel [%s] are already set", mspID, req.ChannelID)
+			return nil
+		}
+	} else {
+		if len(anchorPeers.AnchorPeers) == 0 {
+			logger.Debugf("[%s] has no anchor peers on channel [%s]", mspID, req.ChannelID)
+			return nil
+		}
+		value = &common.ConfigValue{ModPolicy: channelconfig.AdminsPolicyKey}
+		if orgGroup.Values == nil {
+			orgGroup.Values = make(map[string]*common.ConfigValue)
+		}
+		orgGroup.Values[channelconfig.AnchorPeersKey] = value
+	}
+
+	value.Value, err = proto.Marshal(anchorPeers)
+	if err != nil {
+		return errors.Wrap(err, "marshal anchor peers failed")
+	}
+
+	configUpdate, err := CreateConfigUpdate(req.ChannelID, original, updated)
+	if err != nil {
+		return err
+	}
+
+	signature, err := rc.CreateConfigSignature(configUpdate, signer)
+	if err != nil {
+		return err
+	}
+
+	logger.Debugf("***** Updating anchor peers of [%s] on channel: %s *****\n", mspID, req.ChannelID)
+
+	return rc.submitConfigUpdate(req.ChannelID, configUpdate, []*common.ConfigSignature{signature}, orderer, "")
+}
+
+//newAnchorPeers parses the host:port anchor peers
+func newAnchorPeers(addresses []string) (*pb.AnchorPeers, error) {
+	anchorPeers := &pb.AnchorPeers{}
+	for _, address := range addresses {
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid anchor peer address [%s]", address)
+		}
+		portNum, err := strconv.ParseUint(port, 10, 16)
+		if err != nil || portNum == 0 || host == "" {
+			return nil, errors.Errorf("invalid anchor peer address [%s]", address)
+		}
+		anchorPeers.AnchorPeers = append(anchorPeers.AnchorPeers, &pb.AnchorPeer{Host: host, Port: int32(portNum)})
+	}
+	return anchorPeers, nil
+}
+
+//applicationOrgGroup returns the key and the config group of the application organization with the given MSP ID
+func applicationOrgGroup(config *common.Config, mspID string) (string, *common.ConfigGroup, error) {
+	application, ok := config.GetChannelGroup().GetGroups()[applicationGroupKey]
+	if !ok {
+		return "", nil, errors.New("channel config has no application group")
+	}
+
+	if orgGroup, ok := application.Groups[mspID]; ok {
+		return mspID, orgGroup, nil
+	}
+
+	// the group of the organization is named after the organization, look it up by its MSP ID
+	for key, orgGroup := range application.Groups {
+		value, ok := orgGroup.Values[channelconfig.MSPKey]
+		if !ok {
+			continue
+		}
+		mspConfig := &msp.MSPConfig{}
+		if err := proto.Unmarshal(value.Value, mspConfig); err != nil {
+			return "", nil, errors.Wrap(err, "unmarshal MSP config failed")
+		}
+		fabricConfig := &msp.FabricMSPConfig{}
+		if err := proto.Unmarshal(mspConfig.Config, fabricConfig); err != nil {
+			return "", nil, errors.Wrap(err, "unmarshal fabric MSP config failed")
+		}
+		if fabricConfig.Name == mspID {
+			return key, orgGroup, nil
+		}
+	}
+
+	return "", nil, errors.Errorf("organization [%s] is not a member of the channel", mspID)
+}
+
+func sameAnchorPeers(current, anchorPeers *pb.AnchorPeers) bool {
+	return sameStrings(anchorPeerAddresses(current), anchorPeerAddresses(anchorPeers))
+}
+
+func anchorPeerAddresses(anchorPeers *pb.AnchorPeers) []string {
+	var addresses []string
+	for _, anchorPeer := range anchorPeers.AnchorPeers {
+		addresses = append(addresses, net.JoinHostPort(anchorPeer.Host, strconv.Itoa(int(anchorPeer.Port))))
+	}
+	sort.Strings(addresses)
+	return addresses
+}
+
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
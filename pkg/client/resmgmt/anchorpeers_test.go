@@ -0,0 +1,94 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+func TestUpdateAnchorPeers(t *testing.T) {
+	cc := setupDefaultResMgmtClient(t)
+	mockResource := fcmocks.NewMockResource()
+	cc.resource = mockResource
+
+	config := newTestChannelConfig()
+	anchorPeers := &pb.AnchorPeers{AnchorPeers: []*pb.AnchorPeer{{Host: "peer0.org1.example.com", Port: 7051}}}
+	config.ChannelGroup.Groups["Application"].Groups["Org1MSP"].Values["AnchorPeers"].Value = marshalOrPanic(anchorPeers)
+	mockResource.ConfigEnvelope = &common.ConfigEnvelope{Config: config}
+
+	org1Admin := &testIdentity{mspID: "Org1MSP", cert: []byte("org1")}
+
+	// Anchor peers are already set
+	req := AnchorPeersRequest{ChannelID: "mychannel", AnchorPeers: []string{"peer0.org1.example.com:7051"}, SigningIdentity: org1Admin}
+	err := cc.UpdateAnchorPeers(req)
+	if err != nil {
+		t.Fatalf("Failed to update anchor peers: %s", err)
+	}
+	assert.Empty(t, mockResource.ChannelRequests, "expected no config update for unchanged anchor peers")
+
+	// New anchor peers
+	req.AnchorPeers = []string{"peer1.org1.example.com:8051", "peer0.org1.example.com:7051"}
+	err = cc.UpdateAnchorPeers(req)
+	if err != nil {
+		t.Fatalf("Failed to update anchor peers: %s", err)
+	}
+	if !assert.Len(t, mockResource.ChannelRequests, 1) {
+		return
+	}
+
+	configUpdate := &common.ConfigUpdate{}
+	if err := proto.Unmarshal(mockResource.ChannelRequests[0].Config, configUpdate); err != nil {
+		t.Fatalf("Failed to unmarshal config update: %s", err)
+	}
+	value := configUpdate.WriteSet.Groups["Application"].Groups["Org1MSP"].Values["AnchorPeers"]
+	assert.Equal(t, uint64(1), value.Version)
+	updated := &pb.AnchorPeers{}
+	if err := proto.Unmarshal(value.Value, updated); err != nil {
+		t.Fatalf("Failed to unmarshal anchor peers: %s", err)
+	}
+	assert.Equal(t, []string{"peer0.org1.example.com:7051", "peer1.org1.example.com:8051"}, anchorPeerAddresses(updated))
+	assert.Len(t, mockResource.ChannelRequests[0].Signatures, 1)
+}
+
+func TestUpdateAnchorPeersErrors(t *testing.T) {
+	cc := setupDefaultResMgmtClient(t)
+	mockResource := fcmocks.NewMockResource()
+	mockResource.ConfigEnvelope = &common.ConfigEnvelope{Config: newTestChannelConfig()}
+	cc.resource = mockResource
+
+	org1Admin := &testIdentity{mspID: "Org1MSP", cert: []byte("org1")}
+	org2Admin := &testIdentity{mspID: "Org2MSP", cert: []byte("org2")}
+
+	err := cc.UpdateAnchorPeers(AnchorPeersRequest{AnchorPeers: []string{"peer0.org1.example.com:7051"}, SigningIdentity: org1Admin})
+	assert.NotNil(t, err, "expected error for empty channel ID")
+
+	err = cc.UpdateAnchorPeers(AnchorPeersRequest{ChannelID: "mychannel", AnchorPeers: []string{"peer0.org1.example.com"}, SigningIdentity: org1Admin})
+	assert.NotNil(t, err, "expected error for anchor peer without port")
+
+	for _, port := range []string{"0", "-1", "65536", "99999", "port"} {
+		err = cc.UpdateAnchorPeers(AnchorPeersRequest{ChannelID: "mychannel", AnchorPeers: []string{"peer0.org1.example.com:" + port}, SigningIdentity: org1Admin})
+		assert.NotNil(t, err, "expected error for anchor peer port %s", port)
+	}
+
+	_, err = newAnchorPeers([]string{"peer0.org1.example.com:65535"})
+	assert.Nil(t, err, "expected highest port to be valid")
+
+	err = cc.UpdateAnchorPeers(AnchorPeersRequest{ChannelID: "mychannel", OrgMSPID: "Org3MSP", AnchorPeers: []string{"peer0.org3.example.com:7051"}, SigningIdentity: org1Admin})
+	assert.NotNil(t, err, "expected error for org that is not a channel member")
+
+	// The org admin must sign the update
+	err = cc.UpdateAnchorPeers(AnchorPeersRequest{ChannelID: "mychannel", OrgMSPID: "Org1MSP", AnchorPeers: []string{"peer0.org1.example.com:7051"}, SigningIdentity: org2Admin})
+	assert.NotNil(t, err, "expected error for signature of another org")
+	assert.Empty(t, mockResource.ChannelRequests)
+}
@@ -0,0 +1,203 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	"io/ioutil"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource/api"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+//SignedCCPackageRequest contains the parameters to create a signed chaincode deployment package
+type SignedCCPackageRequest struct {
+	Name    string
+	Path    string
+	Version string
+	Package *api.CCPackage
+	//InstantiationPolicy restricts who may instantiate the chaincode (optional).
+	//Defaults to the admins of the client's organization
+	InstantiationPolicy *common.SignaturePolicyEnvelope
+	//Owners endorse the package in order, the last owner signs the package (optional).
+	//Defaults to the client identity
+	Owners []context.Identity
+}
+
+//CreateSignedCCPackage creates a signed chaincode deployment package with the instantiation policy
+//of the chaincode, endorsed by the owners of the chaincode
+func (rc *Client) CreateSignedCCPackage(req SignedCCPackageRequest) (*common.Envelope, error) {
+	if req.Name == "" || req.Version == "" || req.Path == "" || req.Package == nil {
+		return nil, errors.New("Chaincode name, version, path and chaincode package are required")
+	}
+
+	owners := req.Owners
+	if len(owners) == 0 {
+		owners = []context.Identity{rc.identity}
+	}
+
+	policy := req.InstantiationPolicy
+	if policy == nil {
+		policy = cauthdsl.SignedByMspAdmin(rc.identity.MspID())
+	}
+
+	cds, err := resource.CreateChaincodeDeploymentSpec(resource.ChaincodeInstallRequest{
+		Name:    req.Name,
+		Path:    req.Path,
+		Version: req.Version,
+		Package: &resource.ChaincodePackage{Type: req.Package.Type, Code: req.Package.Code},
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "creating chaincode deployment spec failed")
+	}
+
+	pkg, err := resource.CreateSignedCCPackage(rc.ownerContext(owners[0]), cds, policy)
+	if err != nil {
+		return nil, errors.WithMessage(err, "creating signed chaincode package failed")
+	}
+	for _, owner := range owners[1:] {
+		pkg, err = rc.SignCCPackage(pkg, owner)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return pkg, nil
+}
+
+//SignCCPackage adds the endorsement of the owner to the signed chaincode deployment package.
+//The owner defaults to the client identity
+func (rc *Client) SignCCPackage(pkg *common.Envelope, owner context.Identity) (*common.Envelope, error) {
+	if owner == nil {
+		owner = rc.identity
+	}
+
+	signedPkg, err := resource.SignCCPackage(rc.ownerContext(owner), pkg)
+	if err != nil {
+		return nil, errors.WithMessage(err, "signing chaincode package failed")
+	}
+	return signedPkg, nil
+}
+
+//MergeSignedCCPackages merges the endorsements of signed chaincode deployment packages
+//that were endorsed separately by the owners of the chaincode
+func MergeSignedCCPackages(pkgs ...*common.Envelope) (*common.Envelope, error) {
+	pkg, err := resource.MergeSignedCCPackages(pkgs...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "merging signed chaincode packages failed")
+	}
+	return pkg, nil
+}
+
+//WriteSignedCCPackage writes the signed chaincode deployment package to a file,
+//in the format of the peer CLI's signed chaincode packages
+func WriteSignedCCPackage(path string, pkg *common.Envelope) error {
+	if pkg == nil {
+		return errors.New("signed chaincode package is required")
+	}
+
+	pkgBytes, err := proto.Marshal(pkg)
+	if err != nil {
+		return errors.Wrap(err, "marshal of signed chaincode package failed")
+	}
+	if err := ioutil.WriteFile(path, pkgBytes, 0644); err != nil {
+		return errors.Wrap(err, "writing signed chaincode package failed")
+	}
+	return nil
+}
+
+//ReadSignedCCPackage reads a signed chaincode deployment package from a file
+func ReadSignedCCPackage(path string) (*common.Envelope, error) {
+	pkgBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading signed chaincode package failed")
+	}
+
+	pkg := &common.Envelope{}
+	if err := proto.Unmarshal(pkgBytes, pkg); err != nil {
+		return nil, errors.Wrap(err, "unmarshal of signed chaincode package failed")
+	}
+	if _, _, err := resource.ExtractSignedCCPackage(pkg); err != nil {
+		return nil, errors.WithMessage(err, "invalid signed chaincode package")
+	}
+	return pkg, nil
+}
+
+//InstallSignedCC installs the signed chaincode deployment package with optional custom options (specific peers, filtered peers)
+func (rc *Client) InstallSignedCC(pkg *common.Envelope, options ...RequestOption) ([]InstallCCResponse, error) {
+	signedCDS, cds, err := resource.ExtractSignedCCPackage(pkg)
+	if err != nil {
+		return nil, errors.WithMessage(err, "invalid signed chaincode package")
+	}
+	if len(signedCDS.OwnerEndorsements) == 0 {
+		return nil, errors.New("signed chaincode package has no owner endorsements")
+	}
+
+	chaincodeID := cds.ChaincodeSpec.ChaincodeId
+	req := InstallCCRequest{
+		Name:    chaincodeID.Name,
+		Path:    chaincodeID.Path,
+		Version: chaincodeID.Version,
+		Package: &api.CCPackage{Type: cds.ChaincodeSpec.Type, Code: cds.CodePackage},
+	}
+	return rc.installCC(req, nil, pkg, options...)
+}
+
+//installCCPackageFile installs the prebuilt chaincode deployment spec or signed chaincode package file as is
+func (rc *Client) installCCPackageFile(req InstallCCRequest, options ...RequestOption) ([]InstallCCResponse, error) {
+	if req.Package != nil || req.MetadataPath != "" {
+		return nil, errors.New("chaincode package and metadata path can't be provided with a chaincode package file")
+	}
+
+	raw, err := ioutil.ReadFile(req.PackageFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading chaincode package file failed")
+	}
+	cds, signedPkg, err := resource.UnmarshalChaincodePackage(raw)
+	if err != nil {
+		return nil, errors.WithMessage(err, "invalid chaincode package file")
+	}
+
+	chaincodeID := cds.ChaincodeSpec.ChaincodeId
+	if (req.Name != "" && req.Name != chaincodeID.Name) || (req.Version != "" && req.Version != chaincodeID.Version) || (req.Path != "" && req.Path != chaincodeID.Path) {
+		return nil, errors.Errorf("chaincode package file is for chaincode %s:%s (%s)", chaincodeID.Name, chaincodeID.Version, chaincodeID.Path)
+	}
+
+	req.Name = chaincodeID.Name
+	req.Version = chaincodeID.Version
+	req.Path = chaincodeID.Path
+	req.Package = &api.CCPackage{Type: cds.ChaincodeSpec.Type, Code: cds.CodePackage}
+	if signedPkg != nil {
+		return rc.installCC(req, nil, signedPkg, options...)
+	}
+	return rc.installCC(req, cds, nil, options...)
+}
+
+//InspectCCPackageFile describes the chaincode deployment spec or signed chaincode package file, as written
+//by the peer CLI's package command. The ID of the package is the ID the peer reports for the installed chaincode
+func InspectCCPackageFile(path string) (*resource.ChaincodePackageInfo, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading chaincode package file failed")
+	}
+	info, err := resource.InspectChaincodePackage(raw)
+	if err != nil {
+		return nil, errors.WithMessage(err, "inspecting chaincode package failed")
+	}
+	return info, nil
+}
+
+func (rc *Client) ownerContext(owner context.Identity) *Context {
+	return &Context{
+		Identity:  owner,
+		Providers: rc.provider,
+	}
+}
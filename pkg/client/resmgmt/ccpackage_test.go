@@ -0,0 +1,256 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource/api"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
+)
+
+func newTestSignedCCPackageRequest(owners ...context.Identity) SignedCCPackageRequest {
+	return SignedCCPackageRequest{
+		Name:                "ID",
+		Path:                "path",
+		Version:             "v0",
+		Package:             &api.CCPackage{Type: 1, Code: []byte("code")},
+		InstantiationPolicy: cauthdsl.SignedByAnyAdmin([]string{"Org1MSP", "Org2MSP"}),
+		Owners:              owners,
+	}
+}
+
+func TestSignedCCPackage(t *testing.T) {
+	rc := setupDefaultResMgmtClient(t)
+
+	org1Admin := &testIdentity{mspID: "Org1MSP", cert: []byte("org1admin")}
+	org2Admin := &testIdentity{mspID: "Org2MSP", cert: []byte("org2admin")}
+
+	pkg, err := rc.CreateSignedCCPackage(newTestSignedCCPackageRequest(org1Admin))
+	if err != nil {
+		t.Fatalf("Failed to create signed chaincode package: %s", err)
+	}
+	pkg, err = rc.SignCCPackage(pkg, org2Admin)
+	if err != nil {
+		t.Fatalf("Failed to sign chaincode package: %s", err)
+	}
+
+	// Write the package to a file and read it back
+	file, err := ioutil.TempFile("", "signedcc")
+	if err != nil {
+		t.Fatalf("Failed to create signed chaincode package file: %s", err)
+	}
+	file.Close()
+	defer os.Remove(file.Name())
+
+	if err := WriteSignedCCPackage(file.Name(), pkg); err != nil {
+		t.Fatalf("Failed to write signed chaincode package: %s", err)
+	}
+	readPkg, err := ReadSignedCCPackage(file.Name())
+	if err != nil {
+		t.Fatalf("Failed to read signed chaincode package: %s", err)
+	}
+	assert.True(t, proto.Equal(pkg, readPkg), "unexpected signed chaincode package read from file")
+
+	signedCDS, cds, err := resource.ExtractSignedCCPackage(readPkg)
+	if err != nil {
+		t.Fatalf("Failed to extract signed chaincode package: %s", err)
+	}
+	assert.Equal(t, "ID", cds.ChaincodeSpec.ChaincodeId.Name)
+	assert.Len(t, signedCDS.OwnerEndorsements, 2)
+
+	responses, err := rc.InstallSignedCC(readPkg)
+	if err != nil {
+		t.Fatalf("Failed to install signed chaincode package: %s", err)
+	}
+	if len(responses) != 1 || responses[0].Target != "http://peer1.com" {
+		t.Fatalf("Expecting one response from http://peer1.com, got %v", responses)
+	}
+}
+
+func TestSignedCCPackageSeparateOwners(t *testing.T) {
+	rc := setupDefaultResMgmtClient(t)
+
+	req := newTestSignedCCPackageRequest()
+	org1Pkg, err := rc.CreateSignedCCPackage(req)
+	if err != nil {
+		t.Fatalf("Failed to create signed chaincode package: %s", err)
+	}
+
+	// Owners of other orgs endorse the package the client created
+	org2Pkg, err := rc.SignCCPackage(org1Pkg, &testIdentity{mspID: "Org2MSP", cert: []byte("org2admin")})
+	if err != nil {
+		t.Fatalf("Failed to sign chaincode package: %s", err)
+	}
+	org3Pkg, err := rc.SignCCPackage(org1Pkg, &testIdentity{mspID: "Org3MSP", cert: []byte("org3admin")})
+	if err != nil {
+		t.Fatalf("Failed to sign chaincode package: %s", err)
+	}
+
+	pkg, err := MergeSignedCCPackages(org2Pkg, org3Pkg)
+	if err != nil {
+		t.Fatalf("Failed to merge signed chaincode packages: %s", err)
+	}
+	signedCDS, _, err := resource.ExtractSignedCCPackage(pkg)
+	if err != nil {
+		t.Fatalf("Failed to extract signed chaincode package: %s", err)
+	}
+	assert.Len(t, signedCDS.OwnerEndorsements, 3)
+
+	// The client identity endorsed the package already
+	_, err = rc.SignCCPackage(pkg, nil)
+	assert.Error(t, err, "expected error for owner that already endorsed the package")
+}
+
+func TestSignedCCPackageDefaultPolicy(t *testing.T) {
+	rc := setupDefaultResMgmtClient(t)
+
+	req := newTestSignedCCPackageRequest()
+	req.InstantiationPolicy = nil
+	pkg, err := rc.CreateSignedCCPackage(req)
+	if err != nil {
+		t.Fatalf("Failed to create signed chaincode package: %s", err)
+	}
+
+	signedCDS, _, err := resource.ExtractSignedCCPackage(pkg)
+	if err != nil {
+		t.Fatalf("Failed to extract signed chaincode package: %s", err)
+	}
+	assert.Equal(t, marshalOrPanic(cauthdsl.SignedByMspAdmin("Org1MSP")), signedCDS.InstantiationPolicy)
+}
+
+func TestSignedCCPackageErrors(t *testing.T) {
+	rc := setupDefaultResMgmtClient(t)
+
+	_, err := rc.CreateSignedCCPackage(SignedCCPackageRequest{Name: "ID", Version: "v0"})
+	assert.Error(t, err, "expected error for missing chaincode parameters")
+
+	_, err = rc.InstallSignedCC(nil)
+	assert.Error(t, err, "expected error for missing signed chaincode package")
+
+	err = WriteSignedCCPackage("signedcc", nil)
+	assert.Error(t, err, "expected error for missing signed chaincode package")
+
+	_, err = ReadSignedCCPackage("invalid/signedcc")
+	assert.Error(t, err, "expected error for missing signed chaincode package file")
+}
+
+func newTestCodePackage(t *testing.T, files ...string) []byte {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for _, file := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: file, Size: int64(len("package main")), Mode: 0644}); err != nil {
+			t.Fatalf("Failed to write code package: %s", err)
+		}
+		if _, err := tw.Write([]byte("package main")); err != nil {
+			t.Fatalf("Failed to write code package: %s", err)
+		}
+	}
+	tw.Close()
+	gw.Close()
+	return buf.Bytes()
+}
+
+func writeTestCCPackageFile(t *testing.T, pkg proto.Message) string {
+	file, err := ioutil.TempFile("", "ccpackage")
+	if err != nil {
+		t.Fatalf("Failed to create chaincode package file: %s", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(marshalOrPanic(pkg)); err != nil {
+		t.Fatalf("Failed to write chaincode package file: %s", err)
+	}
+	return file.Name()
+}
+
+func TestInstallCCPackageFile(t *testing.T) {
+	rc := setupDefaultResMgmtClient(t)
+
+	cds, err := resource.CreateChaincodeDeploymentSpec(resource.ChaincodeInstallRequest{Name: "ID", Path: "path", Version: "v0", Package: &resource.ChaincodePackage{Type: 1}})
+	if err != nil {
+		t.Fatalf("Failed to create chaincode deployment spec: %s", err)
+	}
+	cdsFile := writeTestCCPackageFile(t, cds)
+	defer os.Remove(cdsFile)
+
+	info, err := InspectCCPackageFile(cdsFile)
+	if err != nil {
+		t.Fatalf("Failed to inspect chaincode package file: %s", err)
+	}
+	assert.Equal(t, "ID", info.Name)
+	assert.Equal(t, "v0", info.Version)
+	assert.Equal(t, "path", info.Path)
+	assert.False(t, info.Signed)
+	assert.Equal(t, resource.ChaincodePackageID(cds, nil), info.ID)
+
+	responses, err := rc.InstallCC(InstallCCRequest{PackageFile: cdsFile})
+	if err != nil {
+		t.Fatalf("Failed to install chaincode package file: %s", err)
+	}
+	if len(responses) != 1 || responses[0].Target != "http://peer1.com" {
+		t.Fatalf("Expecting one response from http://peer1.com, got %v", responses)
+	}
+
+	// Signed chaincode packages are installed from file too
+	signedReq := newTestSignedCCPackageRequest()
+	signedReq.Package.Code = newTestCodePackage(t, "src/path/cc.go")
+	pkg, err := rc.CreateSignedCCPackage(signedReq)
+	if err != nil {
+		t.Fatalf("Failed to create signed chaincode package: %s", err)
+	}
+	signedFile := writeTestCCPackageFile(t, pkg)
+	defer os.Remove(signedFile)
+
+	info, err = InspectCCPackageFile(signedFile)
+	if err != nil {
+		t.Fatalf("Failed to inspect chaincode package file: %s", err)
+	}
+	assert.True(t, info.Signed)
+	assert.Len(t, info.Owners, 1)
+	assert.Equal(t, []resource.ChaincodePackageFile{{Name: "src/path/cc.go", Size: int64(len("package main"))}}, info.Files)
+
+	_, err = rc.InstallCC(InstallCCRequest{Name: "ID", Version: "v0", PackageFile: signedFile})
+	if err != nil {
+		t.Fatalf("Failed to install signed chaincode package file: %s", err)
+	}
+}
+
+func TestInstallCCPackageFileErrors(t *testing.T) {
+	rc := setupDefaultResMgmtClient(t)
+
+	cds, err := resource.CreateChaincodeDeploymentSpec(resource.ChaincodeInstallRequest{Name: "ID", Path: "path", Version: "v0", Package: &resource.ChaincodePackage{Type: 1}})
+	if err != nil {
+		t.Fatalf("Failed to create chaincode deployment spec: %s", err)
+	}
+	cdsFile := writeTestCCPackageFile(t, cds)
+	defer os.Remove(cdsFile)
+
+	// The request must match the chaincode of the package file
+	_, err = rc.InstallCC(InstallCCRequest{Name: "ID", Version: "v1", PackageFile: cdsFile})
+	assert.Error(t, err, "expected error for different chaincode version")
+
+	// The package file is installed as is
+	_, err = rc.InstallCC(InstallCCRequest{PackageFile: cdsFile, Package: &api.CCPackage{Type: 1, Code: []byte("code")}})
+	assert.Error(t, err, "expected error for package with package file")
+
+	_, err = rc.InstallCC(InstallCCRequest{PackageFile: "invalid/ccpackage"})
+	assert.Error(t, err, "expected error for missing package file")
+
+	_, err = InspectCCPackageFile("invalid/ccpackage")
+	assert.Error(t, err, "expected error for missing package file")
+}
@@ -0,0 +1,245 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	"io/ioutil"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource/api"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+//UpdateChannelRequest used to submit a channel config update
+type UpdateChannelRequest struct {
+	// Channel Name (ID)
+	ChannelID string
+	// Marshalled config update, see CreateConfigUpdate
+	ConfigUpdate []byte
+	// Signatures of the config update, collected from the admins required by the channel's modification policies
+	Signatures []*common.ConfigSignature
+}
+
+//QueryConfig returns the current configuration of the channel, retrieved from the orderer.
+//Modify a copy of the returned configuration and pass both to CreateConfigUpdate to compute the config update
+func (rc *Client) QueryConfig(channelID string, options ...RequestOption) (*common.Config, error) {
+	if channelID == "" {
+		return nil, errors.New("must provide channel ID")
+	}
+
+	opts, err := rc.prepareRequestOpts(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	orderer, err := rc.requestOrderer(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return rc.lastConfig(channelID, orderer)
+}
+
+func (rc *Client) lastConfig(channelID string, orderer fab.Orderer) (*common.Config, error) {
+	configEnvelope, err := rc.resource.LastConfigFromOrderer(channelID, orderer)
+	if err != nil {
+		return nil, errors.WithMessage(err, "retrieving last config from orderer failed")
+	}
+	if configEnvelope == nil || configEnvelope.Config == nil {
+		return nil, errors.Errorf("no config found for channel [%s]", channelID)
+	}
+
+	return configEnvelope.Config, nil
+}
+
+//ExtractConfigUpdate returns the unsigned config update of a channel configuration file (.tx).
+//The config update may be exported to the admins of the organizations, who sign it independently
+//with resource.CreateConfigSignature. The collected signatures are submitted with SaveChannel
+func ExtractConfigUpdate(channelConfigPath string) ([]byte, error) {
+	configTx, err := ioutil.ReadFile(channelConfigPath)
+	if err != nil {
+		return nil, errors.WithMessage(err, "reading channel config file failed")
+	}
+
+	configUpdate, err := resource.ExtractChannelConfig(configTx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "extracting channel config failed")
+	}
+	return configUpdate, nil
+}
+
+//MergeConfigSignatures merges config signatures collected from several signers, dropping duplicate signatures
+func MergeConfigSignatures(signatures ...[]*common.ConfigSignature) []*common.ConfigSignature {
+	var merged []*common.ConfigSignature
+	seen := make(map[string]bool)
+	for _, sigs := range signatures {
+		for _, sig := range sigs {
+			if sig == nil || seen[string(sig.SignatureHeader)] {
+				continue
+			}
+			seen[string(sig.SignatureHeader)] = true
+			merged = append(merged, sig)
+		}
+	}
+	return merged
+}
+
+//CreateConfigUpdate computes the config update from the original to the updated channel configuration.
+//The returned bytes are the marshalled config update to be signed with CreateConfigSignature and submitted with UpdateChannel
+func CreateConfigUpdate(channelID string, original, updated *common.Config) ([]byte, error) {
+	if channelID == "" {
+		return nil, errors.New("must provide channel ID")
+	}
+
+	configUpdate, err := resource.ComputeConfigUpdate(channelID, original, updated)
+	if err != nil {
+		return nil, errors.WithMessage(err, "computing config update failed")
+	}
+
+	configUpdateBytes, err := proto.Marshal(configUpdate)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal config update failed")
+	}
+	return configUpdateBytes, nil
+}
+
+//CreateConfigSignature signs the config update with the given identity, or with the client's identity if none is given.
+//Signatures of the admins of several organizations may be collected and submitted together with UpdateChannel
+func (rc *Client) CreateConfigSignature(configUpdate []byte, signer context.Identity) (*common.ConfigSignature, error) {
+	if len(configUpdate) == 0 {
+		return nil, errors.New("must provide config update")
+	}
+
+	if signer == nil {
+		signer = rc.identity
+	}
+	if signer == nil {
+		return nil, errors.New("must provide signing user")
+	}
+
+	sigCtx := Context{
+		Identity:  signer,
+		Providers: rc.provider,
+	}
+	configSignature, err := resource.CreateConfigSignature(&sigCtx, configUpdate)
+	if err != nil {
+		return nil, errors.WithMessage(err, "signing configuration failed")
+	}
+	return configSignature, nil
+}
+
+//UpdateChannel submits the signed config update of the channel to the orderer, once the signatures
+//are checked against the modification policies of the current channel config
+func (rc *Client) UpdateChannel(req UpdateChannelRequest, options ...RequestOption) error {
+	if req.ChannelID == "" || len(req.ConfigUpdate) == 0 {
+		return errors.New("must provide channel ID and config update")
+	}
+
+	if len(req.Signatures) == 0 {
+		return errors.New("must provide config signatures")
+	}
+
+	configUpdate := &common.ConfigUpdate{}
+	if err := proto.Unmarshal(req.ConfigUpdate, configUpdate); err != nil {
+		return errors.Wrap(err, "unmarshal config update failed")
+	}
+	if configUpdate.ChannelId != req.ChannelID {
+		return errors.Errorf("config update is for channel [%s], not [%s]", configUpdate.ChannelId, req.ChannelID)
+	}
+
+	opts, err := rc.prepareRequestOpts(options...)
+	if err != nil {
+		return err
+	}
+
+	logger.Debugf("***** Updating channel: %s *****\n", req.ChannelID)
+
+	orderer, err := rc.requestOrderer(opts)
+	if err != nil {
+		return err
+	}
+
+	return rc.submitConfigUpdate(req.ChannelID, req.ConfigUpdate, req.Signatures, orderer, opts.SystemChannelID)
+}
+
+//submitConfigUpdate checks the signatures of the config update and submits it to the orderer
+func (rc *Client) submitConfigUpdate(channelID string, configUpdate []byte, signatures []*common.ConfigSignature, orderer fab.Orderer, systemChannelID string) error {
+	if err := rc.checkConfigSignatures(channelID, configUpdate, signatures, orderer, systemChannelID); err != nil {
+		return err
+	}
+
+	request := api.CreateChannelRequest{
+		Name:       channelID,
+		Orderer:    orderer,
+		Config:     configUpdate,
+		Signatures: signatures,
+	}
+
+	_, err := rc.resource.CreateChannel(request)
+	if err != nil {
+		return errors.WithMessage(err, "update channel failed")
+	}
+
+	return nil
+}
+
+//checkConfigSignatures checks the signatures against the modification policies of the current channel config.
+//The signatures of a channel creation are checked against the channel creation policy of the consortium,
+//which is defined in the config of the orderer system channel, if the system channel ID is given
+func (rc *Client) checkConfigSignatures(channelID string, configUpdate []byte, signatures []*common.ConfigSignature, orderer fab.Orderer, systemChannelID string) error {
+	creation, err := resource.IsChannelCreation(configUpdate)
+	if err != nil {
+		return err
+	}
+	if creation {
+		return rc.checkChannelCreationSignatures(channelID, configUpdate, signatures, orderer, systemChannelID)
+	}
+
+	config, err := rc.lastConfig(channelID, orderer)
+	if err != nil {
+		return err
+	}
+
+	channelService, err := rc.channelProvider.ChannelService(rc.identity, channelID)
+	if err != nil {
+		return errors.WithMessage(err, "Unable to get channel service")
+	}
+	membership, err := channelService.Membership()
+	if err != nil {
+		return errors.WithMessage(err, "membership creation failed")
+	}
+
+	if err := resource.CheckConfigSignatures(config, configUpdate, signatures, membership); err != nil {
+		return errors.WithMessage(err, "config signatures check failed")
+	}
+	return nil
+}
+
+//checkChannelCreationSignatures checks the signatures of a channel creation against the channel creation policy
+//of the consortium. Without the system channel ID, the signatures can't be checked before they reach the orderer
+func (rc *Client) checkChannelCreationSignatures(channelID string, configUpdate []byte, signatures []*common.ConfigSignature, orderer fab.Orderer, systemChannelID string) error {
+	if systemChannelID == "" {
+		logger.Warnf("config update creates channel [%s]: its signatures are NOT checked against the channel creation policy of the consortium, "+
+			"the orderer rejects insufficient signatures. Provide the orderer system channel with WithSystemChannelID to check them", channelID)
+		return nil
+	}
+
+	systemConfig, err := rc.lastConfig(systemChannelID, orderer)
+	if err != nil {
+		return errors.WithMessage(err, "retrieving system channel config failed")
+	}
+
+	if err := resource.CheckChannelCreationSignatures(systemConfig, configUpdate, signatures); err != nil {
+		return errors.WithMessage(err, "channel creation signatures check failed")
+	}
+	return nil
+}
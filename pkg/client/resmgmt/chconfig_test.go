@@ -0,0 +1,321 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/core"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+func marshalOrPanic(msg proto.Message) []byte {
+	bytes, err := proto.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return bytes
+}
+
+func newTestOrgGroup(mspID string) *common.ConfigGroup {
+	return &common.ConfigGroup{
+		ModPolicy: "Admins",
+		Values: map[string]*common.ConfigValue{
+			"AnchorPeers": {ModPolicy: "Admins", Value: marshalOrPanic(&pb.AnchorPeers{AnchorPeers: []*pb.AnchorPeer{{Host: "peer0." + mspID, Port: 7051}}})},
+		},
+		Policies: map[string]*common.ConfigPolicy{
+			"Admins": {
+				ModPolicy: "Admins",
+				Policy:    &common.Policy{Type: int32(common.Policy_SIGNATURE), Value: marshalOrPanic(cauthdsl.SignedByMspMember(mspID))},
+			},
+		},
+	}
+}
+
+func newTestChannelConfig() *common.Config {
+	majorityAdmins := &common.ImplicitMetaPolicy{SubPolicy: "Admins", Rule: common.ImplicitMetaPolicy_MAJORITY}
+	return &common.Config{
+		ChannelGroup: &common.ConfigGroup{
+			Groups: map[string]*common.ConfigGroup{
+				"Application": {
+					Version:   1,
+					ModPolicy: "Admins",
+					Groups: map[string]*common.ConfigGroup{
+						"Org1MSP": newTestOrgGroup("Org1MSP"),
+						"Org2MSP": newTestOrgGroup("Org2MSP"),
+					},
+					Policies: map[string]*common.ConfigPolicy{
+						"Admins": {
+							ModPolicy: "Admins",
+							Policy:    &common.Policy{Type: int32(common.Policy_IMPLICIT_META), Value: marshalOrPanic(majorityAdmins)},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+type testIdentity struct {
+	mspID string
+	cert  []byte
+}
+
+func (i *testIdentity) MspID() string {
+	return i.mspID
+}
+
+func (i *testIdentity) SerializedIdentity() ([]byte, error) {
+	return proto.Marshal(&msp.SerializedIdentity{Mspid: i.mspID, IdBytes: i.cert})
+}
+
+func (i *testIdentity) PrivateKey() core.Key {
+	return nil
+}
+
+func writeTestChannelConfig(t *testing.T, configUpdate []byte) string {
+	envelope := &common.Envelope{
+		Payload: marshalOrPanic(&common.Payload{
+			Data: marshalOrPanic(&common.ConfigUpdateEnvelope{ConfigUpdate: configUpdate}),
+		}),
+	}
+
+	file, err := ioutil.TempFile("", "channel")
+	if err != nil {
+		t.Fatalf("Failed to create channel config file: %s", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(marshalOrPanic(envelope)); err != nil {
+		t.Fatalf("Failed to write channel config file: %s", err)
+	}
+	return file.Name()
+}
+
+func TestQueryConfig(t *testing.T) {
+	cc := setupDefaultResMgmtClient(t)
+	mockResource := fcmocks.NewMockResource()
+	mockResource.ConfigEnvelope = &common.ConfigEnvelope{Config: newTestChannelConfig()}
+	cc.resource = mockResource
+
+	_, err := cc.QueryConfig("")
+	assert.NotNil(t, err, "expected error for empty channel ID")
+
+	config, err := cc.QueryConfig("mychannel", WithOrdererID("orderer.example.com"))
+	if err != nil {
+		t.Fatalf("Failed to query config: %s", err)
+	}
+	assert.True(t, proto.Equal(newTestChannelConfig(), config))
+
+	_, err = cc.QueryConfig("mychannel", WithOrdererID("Invalid"))
+	assert.NotNil(t, err, "expected error for invalid orderer ID")
+
+	mockResource.ConfigEnvelope = nil
+	_, err = cc.QueryConfig("mychannel")
+	assert.NotNil(t, err, "expected error when no config is returned")
+
+	cc.resource = fcmocks.NewMockInvalidResource()
+	_, err = cc.QueryConfig("mychannel")
+	assert.NotNil(t, err, "expected error from orderer")
+}
+
+func TestUpdateChannel(t *testing.T) {
+	cc := setupDefaultResMgmtClient(t)
+	mockResource := fcmocks.NewMockResource()
+	cc.resource = mockResource
+
+	original := newTestChannelConfig()
+	updated := proto.Clone(original).(*common.Config)
+	updated.ChannelGroup.Groups["Application"].Groups["Org1MSP"].Values["AnchorPeers"].Value = []byte("peer1.org1")
+
+	_, err := CreateConfigUpdate("mychannel", original, original)
+	assert.NotNil(t, err, "expected error for unchanged config")
+
+	configUpdate, err := CreateConfigUpdate("mychannel", original, updated)
+	if err != nil {
+		t.Fatalf("Failed to create config update: %s", err)
+	}
+
+	// Signatures of several identities
+	org1 := &testIdentity{mspID: "Org1MSP", cert: []byte("org1")}
+	org2 := &testIdentity{mspID: "Org2MSP", cert: []byte("org2")}
+	signature1, err := cc.CreateConfigSignature(configUpdate, org1)
+	if err != nil {
+		t.Fatalf("Failed to sign config update: %s", err)
+	}
+	signature2, err := cc.CreateConfigSignature(configUpdate, org2)
+	if err != nil {
+		t.Fatalf("Failed to sign config update: %s", err)
+	}
+	assert.NotEqual(t, signature1.SignatureHeader, signature2.SignatureHeader)
+
+	_, err = cc.CreateConfigSignature(nil, nil)
+	assert.NotNil(t, err, "expected error for empty config update")
+
+	// Required parameters
+	err = cc.UpdateChannel(UpdateChannelRequest{ConfigUpdate: configUpdate, Signatures: []*common.ConfigSignature{signature1}})
+	assert.NotNil(t, err, "expected error for empty channel ID")
+	err = cc.UpdateChannel(UpdateChannelRequest{ChannelID: "mychannel", ConfigUpdate: configUpdate})
+	assert.NotNil(t, err, "expected error without signatures")
+	err = cc.UpdateChannel(UpdateChannelRequest{ChannelID: "otherchannel", ConfigUpdate: configUpdate, Signatures: []*common.ConfigSignature{signature1}})
+	assert.NotNil(t, err, "expected error for config update of another channel")
+
+	// Signatures must satisfy the modification policy of the current config
+	err = cc.UpdateChannel(UpdateChannelRequest{ChannelID: "mychannel", ConfigUpdate: configUpdate, Signatures: []*common.ConfigSignature{signature1}})
+	assert.NotNil(t, err, "expected error without current config")
+	mockResource.ConfigEnvelope = &common.ConfigEnvelope{Config: original}
+	err = cc.UpdateChannel(UpdateChannelRequest{ChannelID: "mychannel", ConfigUpdate: configUpdate, Signatures: []*common.ConfigSignature{signature2}})
+	assert.NotNil(t, err, "expected error for signature of another org")
+
+	req := UpdateChannelRequest{
+		ChannelID:    "mychannel",
+		ConfigUpdate: configUpdate,
+		Signatures:   []*common.ConfigSignature{signature1, signature2},
+	}
+	err = cc.UpdateChannel(req, WithOrdererID("orderer.example.com"))
+	if err != nil {
+		t.Fatalf("Failed to update channel: %s", err)
+	}
+	if assert.Len(t, mockResource.ChannelRequests, 1) {
+		assert.Equal(t, "mychannel", mockResource.ChannelRequests[0].Name)
+		assert.Equal(t, configUpdate, mockResource.ChannelRequests[0].Config)
+		assert.Len(t, mockResource.ChannelRequests[0].Signatures, 2)
+	}
+
+	cc.resource = fcmocks.NewMockInvalidResource()
+	err = cc.UpdateChannel(req)
+	assert.NotNil(t, err, "expected error from orderer")
+}
+
+func TestSaveChannelWithSignatures(t *testing.T) {
+	cc := setupDefaultResMgmtClient(t)
+	mockResource := fcmocks.NewMockResource()
+	cc.resource = mockResource
+
+	original := newTestChannelConfig()
+	mockResource.ConfigEnvelope = &common.ConfigEnvelope{Config: original}
+	updated := proto.Clone(original).(*common.Config)
+	updated.ChannelGroup.Groups["Application"].Groups["Org3MSP"] = newTestOrgGroup("Org3MSP")
+	configUpdate, err := CreateConfigUpdate("mychannel", original, updated)
+	if err != nil {
+		t.Fatalf("Failed to create config update: %s", err)
+	}
+
+	channelConfigPath := writeTestChannelConfig(t, configUpdate)
+	defer os.Remove(channelConfigPath)
+
+	// Export the unsigned config update, to be signed by each org
+	exported, err := ExtractConfigUpdate(channelConfigPath)
+	if err != nil {
+		t.Fatalf("Failed to extract config update: %s", err)
+	}
+	assert.Equal(t, configUpdate, exported)
+
+	sigCtx := Context{Providers: cc.provider, Identity: &testIdentity{mspID: "Org1MSP", cert: []byte("org1")}}
+	signature1, err := resource.CreateConfigSignature(&sigCtx, exported)
+	if err != nil {
+		t.Fatalf("Failed to sign config update: %s", err)
+	}
+	sigCtx.Identity = &testIdentity{mspID: "Org2MSP", cert: []byte("org2")}
+	signature2, err := resource.CreateConfigSignature(&sigCtx, exported)
+	if err != nil {
+		t.Fatalf("Failed to sign config update: %s", err)
+	}
+
+	// Adding an org requires the signatures of a majority of the orgs
+	req := SaveChannelRequest{ChannelID: "mychannel", ChannelConfig: channelConfigPath, Signatures: []*common.ConfigSignature{signature1}}
+	err = cc.SaveChannel(req)
+	assert.NotNil(t, err, "expected error without a majority of signatures")
+	assert.Empty(t, mockResource.ChannelRequests)
+
+	req.Signatures = MergeConfigSignatures([]*common.ConfigSignature{signature1}, []*common.ConfigSignature{signature2, signature1})
+	assert.Len(t, req.Signatures, 2)
+	err = cc.SaveChannel(req)
+	if err != nil {
+		t.Fatalf("Failed to save channel: %s", err)
+	}
+	if assert.Len(t, mockResource.ChannelRequests, 1) {
+		assert.Equal(t, configUpdate, mockResource.ChannelRequests[0].Config)
+		assert.Equal(t, req.Signatures, mockResource.ChannelRequests[0].Signatures)
+	}
+}
+
+func TestSaveChannelCreationSignatures(t *testing.T) {
+	cc := setupDefaultResMgmtClient(t)
+	mockResource := fcmocks.NewMockResource()
+	cc.resource = mockResource
+
+	anyAdmins := &common.ImplicitMetaPolicy{SubPolicy: "Admins", Rule: common.ImplicitMetaPolicy_ANY}
+	creationPolicy := &common.Policy{Type: int32(common.Policy_IMPLICIT_META), Value: marshalOrPanic(anyAdmins)}
+	mockResource.ConfigEnvelope = &common.ConfigEnvelope{Config: &common.Config{
+		ChannelGroup: &common.ConfigGroup{
+			Groups: map[string]*common.ConfigGroup{
+				"Consortiums": {
+					Groups: map[string]*common.ConfigGroup{
+						"SampleConsortium": {
+							Groups: map[string]*common.ConfigGroup{
+								"Org1MSP": newTestOrgGroup("Org1MSP"),
+								"Org2MSP": newTestOrgGroup("Org2MSP"),
+							},
+							Values: map[string]*common.ConfigValue{
+								"ChannelCreationPolicy": {Value: marshalOrPanic(creationPolicy)},
+							},
+						},
+					},
+				},
+			},
+		},
+	}}
+
+	configUpdate := marshalOrPanic(&common.ConfigUpdate{
+		ChannelId: "mychannel",
+		WriteSet: &common.ConfigGroup{
+			Groups: map[string]*common.ConfigGroup{
+				"Application": {Groups: map[string]*common.ConfigGroup{"Org1MSP": {}}},
+			},
+			Values: map[string]*common.ConfigValue{"Consortium": {Value: marshalOrPanic(&common.Consortium{Name: "SampleConsortium"})}},
+		},
+	})
+	channelConfigPath := writeTestChannelConfig(t, configUpdate)
+	defer os.Remove(channelConfigPath)
+
+	sigCtx := Context{Providers: cc.provider, Identity: &testIdentity{mspID: "Org2MSP", cert: []byte("org2")}}
+	signature, err := resource.CreateConfigSignature(&sigCtx, configUpdate)
+	if err != nil {
+		t.Fatalf("Failed to sign config update: %s", err)
+	}
+
+	// Org2MSP is a member of the consortium but not of the channel
+	req := SaveChannelRequest{ChannelID: "mychannel", ChannelConfig: channelConfigPath, Signatures: []*common.ConfigSignature{signature}}
+	err = cc.SaveChannel(req, WithSystemChannelID("testchainid"))
+	assert.NotNil(t, err, "expected error for signature that doesn't satisfy the channel creation policy")
+	assert.Empty(t, mockResource.ChannelRequests)
+
+	// Without the system channel, the signatures are only checked by the orderer
+	err = cc.SaveChannel(req)
+	assert.Nil(t, err, "expected channel creation to be submitted")
+	assert.Len(t, mockResource.ChannelRequests, 1)
+
+	sigCtx.Identity = &testIdentity{mspID: "Org1MSP", cert: []byte("org1")}
+	signature, err = resource.CreateConfigSignature(&sigCtx, configUpdate)
+	if err != nil {
+		t.Fatalf("Failed to sign config update: %s", err)
+	}
+	req.Signatures = []*common.ConfigSignature{signature}
+	err = cc.SaveChannel(req, WithSystemChannelID("testchainid"))
+	assert.Nil(t, err, "expected signature to satisfy the channel creation policy")
+	assert.Len(t, mockResource.ChannelRequests, 2)
+}
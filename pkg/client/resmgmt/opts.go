@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package resmgmt
 
 import (
+	reqContext "context"
 	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
@@ -36,7 +37,8 @@ func WithTargetFilter(targetFilter TargetFilter) RequestOption {
 	}
 }
 
-//WithTimeout encapsulates time.Duration to resmgmtclient RequestOption
+//WithTimeout encapsulates time.Duration to resmgmtclient RequestOption.
+//The timeout is applied as a deadline on the request context
 func WithTimeout(timeout time.Duration) RequestOption {
 	return func(opts *Opts) error {
 		opts.Timeout = timeout
@@ -44,6 +46,14 @@ func WithTimeout(timeout time.Duration) RequestOption {
 	}
 }
 
+//WithParentContext encapsulates a parent context to resmgmtclient RequestOption
+func WithParentContext(parentContext reqContext.Context) RequestOption {
+	return func(opts *Opts) error {
+		opts.ParentContext = parentContext
+		return nil
+	}
+}
+
 //WithOrdererID encapsulates OrdererID to RequestOption
 func WithOrdererID(ordererID string) RequestOption {
 	return func(opts *Opts) error {
@@ -51,3 +61,14 @@ func WithOrdererID(ordererID string) RequestOption {
 		return nil
 	}
 }
+
+//WithSystemChannelID encapsulates the orderer system channel ID to RequestOption.
+//The signatures of a channel creation are checked against the channel creation policy of the consortium,
+//in the config of the system channel, before the channel creation is submitted. The client identity must be
+//allowed to read the config of the system channel
+func WithSystemChannelID(channelID string) RequestOption {
+	return func(opts *Opts) error {
+		opts.SystemChannelID = channelID
+		return nil
+	}
+}
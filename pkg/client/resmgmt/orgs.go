@@ -0,0 +1,213 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/chconfig"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+//OrgDefinition contains the MSP definition of an organization joining a channel
+type OrgDefinition struct {
+	// MSP ID of the organization, also the name of its config group
+	MSPID string
+	// PEM encoded root CA certificates of the organization
+	RootCerts [][]byte
+	// PEM encoded TLS root CA certificates of the organization
+	TLSRootCerts [][]byte
+	// PEM encoded certificates of the organization's admins
+	AdminCerts [][]byte
+	// Anchor peers of the organization as host:port
+	AnchorPeers []string
+}
+
+//AddOrgRequest used to add an organization to a channel
+type AddOrgRequest struct {
+	// Channel Name (ID)
+	ChannelID string
+	// Organization joining the channel
+	Org OrgDefinition
+	// Admins of the channel's organizations that sign the config update, defaults to the client's identity.
+	// Signatures of admins that aren't available to the client are collected offline, see AddOrgConfigUpdate
+	SigningIdentities []context.Identity
+}
+
+//RemoveOrgRequest used to remove an organization from a channel
+type RemoveOrgRequest struct {
+	// Channel Name (ID)
+	ChannelID string
+	// MSP ID of the organization leaving the channel
+	MSPID string
+	// Admins of the channel's organizations that sign the config update, defaults to the client's identity.
+	// Signatures of admins that aren't available to the client are collected offline, see RemoveOrgConfigUpdate
+	SigningIdentities []context.Identity
+}
+
+//AddOrgConfigUpdate returns the config update adding the organization to the current channel config.
+//The admins of the channel's organizations sign it with CreateConfigSignature and it is submitted with UpdateChannel
+func (rc *Client) AddOrgConfigUpdate(channelID string, org OrgDefinition, options ...RequestOption) ([]byte, error) {
+	orderer, err := rc.configOrderer(channelID, options...)
+	if err != nil {
+		return nil, err
+	}
+	return rc.addOrgConfigUpdate(channelID, org, orderer)
+}
+
+//AddOrg adds the organization to the channel. The config update is signed by the signing identities,
+//which must satisfy the modification policy of the channel's application group, and submitted to the orderer
+func (rc *Client) AddOrg(req AddOrgRequest, options ...RequestOption) error {
+	orderer, err := rc.configOrderer(req.ChannelID, options...)
+	if err != nil {
+		return err
+	}
+
+	configUpdate, err := rc.addOrgConfigUpdate(req.ChannelID, req.Org, orderer)
+	if err != nil {
+		return err
+	}
+
+	logger.Debugf("***** Adding organization [%s] to channel: %s *****\n", req.Org.MSPID, req.ChannelID)
+
+	return rc.signAndSubmitConfigUpdate(req.ChannelID, configUpdate, req.SigningIdentities, orderer)
+}
+
+//RemoveOrgConfigUpdate returns the config update removing the organization from the current channel config.
+//The admins of the channel's organizations sign it with CreateConfigSignature and it is submitted with UpdateChannel
+func (rc *Client) RemoveOrgConfigUpdate(channelID string, mspID string, options ...RequestOption) ([]byte, error) {
+	orderer, err := rc.configOrderer(channelID, options...)
+	if err != nil {
+		return nil, err
+	}
+	return rc.removeOrgConfigUpdate(channelID, mspID, orderer)
+}
+
+//RemoveOrg removes the organization from the channel. The config update is signed by the signing identities,
+//which must satisfy the modification policy of the channel's application group, and submitted to the orderer
+func (rc *Client) RemoveOrg(req RemoveOrgRequest, options ...RequestOption) error {
+	orderer, err := rc.configOrderer(req.ChannelID, options...)
+	if err != nil {
+		return err
+	}
+
+	configUpdate, err := rc.removeOrgConfigUpdate(req.ChannelID, req.MSPID, orderer)
+	if err != nil {
+		return err
+	}
+
+	logger.Debugf("***** Removing organization [%s] from channel: %s *****\n", req.MSPID, req.ChannelID)
+
+	return rc.signAndSubmitConfigUpdate(req.ChannelID, configUpdate, req.SigningIdentities, orderer)
+}
+
+//configOrderer returns the orderer of the request options for a config update of the channel
+func (rc *Client) configOrderer(channelID string, options ...RequestOption) (fab.Orderer, error) {
+	if channelID == "" {
+		return nil, errors.New("must provide channel ID")
+	}
+
+	opts, err := rc.prepareRequestOpts(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return rc.requestOrderer(opts)
+}
+
+func (rc *Client) addOrgConfigUpdate(channelID string, org OrgDefinition, orderer fab.Orderer) ([]byte, error) {
+	anchorPeers, err := newAnchorPeers(org.AnchorPeers)
+	if err != nil {
+		return nil, err
+	}
+
+	orgGroup, err := chconfig.NewApplicationOrgGroup(chconfig.OrgMSP{
+		MSPID:        org.MSPID,
+		RootCerts:    org.RootCerts,
+		TLSRootCerts: org.TLSRootCerts,
+		AdminCerts:   org.AdminCerts,
+	}, anchorPeers.AnchorPeers)
+	if err != nil {
+		return nil, errors.WithMessage(err, "invalid organization definition")
+	}
+
+	original, err := rc.lastConfig(channelID, orderer)
+	if err != nil {
+		return nil, err
+	}
+
+	channelCfg, err := chconfig.ExtractChannelCfg(channelID, original)
+	if err != nil {
+		return nil, errors.WithMessage(err, "loading channel config failed")
+	}
+	mspIDs, err := chconfig.MSPIDs(channelCfg)
+	if err != nil {
+		return nil, err
+	}
+	for _, mspID := range mspIDs {
+		if mspID == org.MSPID {
+			return nil, errors.Errorf("organization [%s] is already a member of channel [%s]", org.MSPID, channelID)
+		}
+	}
+
+	updated := proto.Clone(original).(*common.Config)
+	application, ok := updated.GetChannelGroup().GetGroups()[applicationGroupKey]
+	if !ok {
+		return nil, errors.New("channel config has no application group")
+	}
+	if _, ok := application.Groups[org.MSPID]; ok {
+		return nil, errors.Errorf("channel [%s] already has an organization group [%s]", channelID, org.MSPID)
+	}
+	if application.Groups == nil {
+		application.Groups = make(map[string]*common.ConfigGroup)
+	}
+	application.Groups[org.MSPID] = orgGroup
+
+	return CreateConfigUpdate(channelID, original, updated)
+}
+
+func (rc *Client) removeOrgConfigUpdate(channelID string, mspID string, orderer fab.Orderer) ([]byte, error) {
+	if mspID == "" {
+		return nil, errors.New("must provide MSP ID")
+	}
+
+	original, err := rc.lastConfig(channelID, orderer)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := proto.Clone(original).(*common.Config)
+	key, _, err := applicationOrgGroup(updated, mspID)
+	if err != nil {
+		return nil, err
+	}
+	delete(updated.ChannelGroup.Groups[applicationGroupKey].Groups, key)
+
+	return CreateConfigUpdate(channelID, original, updated)
+}
+
+//signAndSubmitConfigUpdate signs the config update with the signing identities, or the client's identity
+//if none is given, and submits it
+func (rc *Client) signAndSubmitConfigUpdate(channelID string, configUpdate []byte, signers []context.Identity, orderer fab.Orderer) error {
+	if len(signers) == 0 {
+		signers = []context.Identity{rc.identity}
+	}
+
+	var signatures []*common.ConfigSignature
+	for _, signer := range signers {
+		signature, err := rc.CreateConfigSignature(configUpdate, signer)
+		if err != nil {
+			return err
+		}
+		signatures = append(signatures, signature)
+	}
+
+	return rc.submitConfigUpdate(channelID, configUpdate, signatures, orderer, "")
+}
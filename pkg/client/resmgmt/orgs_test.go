@@ -0,0 +1,127 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+func newTestOrgDefinition(mspID string) OrgDefinition {
+	return OrgDefinition{
+		MSPID:        mspID,
+		RootCerts:    [][]byte{[]byte("root")},
+		TLSRootCerts: [][]byte{[]byte("tlsroot")},
+		AdminCerts:   [][]byte{[]byte("admin")},
+		AnchorPeers:  []string{"peer0.org3.example.com:7051"},
+	}
+}
+
+func unmarshalTestConfigUpdate(t *testing.T, configUpdate []byte) *common.ConfigUpdate {
+	update := &common.ConfigUpdate{}
+	if err := proto.Unmarshal(configUpdate, update); err != nil {
+		t.Fatalf("Failed to unmarshal config update: %s", err)
+	}
+	return update
+}
+
+func TestAddOrg(t *testing.T) {
+	cc := setupDefaultResMgmtClient(t)
+	mockResource := fcmocks.NewMockResource()
+	mockResource.ConfigEnvelope = &common.ConfigEnvelope{Config: newTestChannelConfig()}
+	cc.resource = mockResource
+
+	org1Admin := &testIdentity{mspID: "Org1MSP", cert: []byte("org1")}
+	org2Admin := &testIdentity{mspID: "Org2MSP", cert: []byte("org2")}
+
+	// The config update can be exported to collect signatures offline
+	configUpdate, err := cc.AddOrgConfigUpdate("mychannel", newTestOrgDefinition("Org3MSP"))
+	if err != nil {
+		t.Fatalf("Failed to create add org config update: %s", err)
+	}
+	application := unmarshalTestConfigUpdate(t, configUpdate).WriteSet.Groups["Application"]
+	assert.Equal(t, uint64(2), application.Version)
+	if assert.Contains(t, application.Groups, "Org3MSP") {
+		assert.Contains(t, application.Groups["Org3MSP"].Values, "MSP")
+		assert.Contains(t, application.Groups["Org3MSP"].Values, "AnchorPeers")
+		assert.Len(t, application.Groups["Org3MSP"].Policies, 3)
+	}
+
+	// A majority of the admins must sign
+	req := AddOrgRequest{ChannelID: "mychannel", Org: newTestOrgDefinition("Org3MSP"), SigningIdentities: []context.Identity{org1Admin}}
+	err = cc.AddOrg(req)
+	assert.NotNil(t, err, "expected error without a majority of admins")
+	assert.Empty(t, mockResource.ChannelRequests)
+
+	req.SigningIdentities = []context.Identity{org1Admin, org2Admin}
+	err = cc.AddOrg(req)
+	if err != nil {
+		t.Fatalf("Failed to add org: %s", err)
+	}
+	if assert.Len(t, mockResource.ChannelRequests, 1) {
+		assert.Len(t, mockResource.ChannelRequests[0].Signatures, 2)
+		application = unmarshalTestConfigUpdate(t, mockResource.ChannelRequests[0].Config).WriteSet.Groups["Application"]
+		assert.Contains(t, application.Groups, "Org3MSP")
+	}
+}
+
+func TestAddOrgErrors(t *testing.T) {
+	cc := setupDefaultResMgmtClient(t)
+	mockResource := fcmocks.NewMockResource()
+	mockResource.ConfigEnvelope = &common.ConfigEnvelope{Config: newTestChannelConfig()}
+	cc.resource = mockResource
+
+	_, err := cc.AddOrgConfigUpdate("", newTestOrgDefinition("Org3MSP"))
+	assert.NotNil(t, err, "expected error for empty channel ID")
+
+	_, err = cc.AddOrgConfigUpdate("mychannel", newTestOrgDefinition("Org1MSP"))
+	assert.NotNil(t, err, "expected error for existing org")
+
+	org := newTestOrgDefinition("Org3MSP")
+	org.AdminCerts = nil
+	_, err = cc.AddOrgConfigUpdate("mychannel", org)
+	assert.NotNil(t, err, "expected error for org without admins")
+
+	org = newTestOrgDefinition("Org3MSP")
+	org.AnchorPeers = []string{"peer0.org3.example.com"}
+	_, err = cc.AddOrgConfigUpdate("mychannel", org)
+	assert.NotNil(t, err, "expected error for invalid anchor peer")
+}
+
+func TestRemoveOrg(t *testing.T) {
+	cc := setupDefaultResMgmtClient(t)
+	mockResource := fcmocks.NewMockResource()
+	mockResource.ConfigEnvelope = &common.ConfigEnvelope{Config: newTestChannelConfig()}
+	cc.resource = mockResource
+
+	org1Admin := &testIdentity{mspID: "Org1MSP", cert: []byte("org1")}
+	org2Admin := &testIdentity{mspID: "Org2MSP", cert: []byte("org2")}
+
+	_, err := cc.RemoveOrgConfigUpdate("mychannel", "Org3MSP")
+	assert.NotNil(t, err, "expected error for org that is not a member")
+
+	_, err = cc.RemoveOrgConfigUpdate("mychannel", "")
+	assert.NotNil(t, err, "expected error for empty MSP ID")
+
+	req := RemoveOrgRequest{ChannelID: "mychannel", MSPID: "Org2MSP", SigningIdentities: []context.Identity{org1Admin, org2Admin}}
+	err = cc.RemoveOrg(req)
+	if err != nil {
+		t.Fatalf("Failed to remove org: %s", err)
+	}
+	if assert.Len(t, mockResource.ChannelRequests, 1) {
+		application := unmarshalTestConfigUpdate(t, mockResource.ChannelRequests[0].Config).WriteSet.Groups["Application"]
+		assert.Equal(t, uint64(2), application.Version)
+		assert.Contains(t, application.Groups, "Org1MSP")
+		assert.NotContains(t, application.Groups, "Org2MSP")
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+//CollectionConfigDefinition is the definition of a private data collection, as in the collections
+//JSON file of the peer CLI
+type CollectionConfigDefinition struct {
+	//Name of the collection
+	Name string `json:"name"`
+	//Policy of the member orgs of the collection, e.g. OR('Org1MSP.member', 'Org2MSP.member')
+	Policy string `json:"policy"`
+	//RequiredPeerCount is the minimum number of peers the private data is sent to on endorsement
+	RequiredPeerCount int32 `json:"requiredPeerCount"`
+	//MaxPeerCount is the maximum number of peers the private data is sent to on endorsement
+	MaxPeerCount int32 `json:"maxPeerCount"`
+	//BlockToLive is the number of blocks after which the private data is purged (0 to never purge)
+	BlockToLive uint64 `json:"blockToLive"`
+}
+
+//NewCCPolicy parses a signature policy string into the policy envelope of a chaincode endorsement policy,
+//e.g. OR('Org1MSP.member', AND('Org2MSP.peer', 'Org3MSP.peer')). The roles are member, admin, client and peer
+func NewCCPolicy(policy string) (*common.SignaturePolicyEnvelope, error) {
+	if strings.TrimSpace(policy) == "" {
+		return nil, errors.New("policy is required")
+	}
+
+	envelope, err := cauthdsl.FromString(policy)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid policy [%s]", policy)
+	}
+	return envelope, nil
+}
+
+//NewCollectionConfig creates the configuration of a private data collection from its definition
+func NewCollectionConfig(def CollectionConfigDefinition) (*common.CollectionConfig, error) {
+	if def.Name == "" {
+		return nil, errors.New("collection name is required")
+	}
+	if def.RequiredPeerCount < 0 {
+		return nil, errors.Errorf("collection [%s]: required peer count (%d) must not be negative", def.Name, def.RequiredPeerCount)
+	}
+	if def.MaxPeerCount < def.RequiredPeerCount {
+		return nil, errors.Errorf("collection [%s]: maximum peer count (%d) must not be less than the required peer count (%d)", def.Name, def.MaxPeerCount, def.RequiredPeerCount)
+	}
+
+	policy, err := NewCCPolicy(def.Policy)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("collection [%s]", def.Name))
+	}
+
+	return &common.CollectionConfig{
+		Payload: &common.CollectionConfig_StaticCollectionConfig{
+			StaticCollectionConfig: &common.StaticCollectionConfig{
+				Name: def.Name,
+				MemberOrgsPolicy: &common.CollectionPolicyConfig{
+					Payload: &common.CollectionPolicyConfig_SignaturePolicy{
+						SignaturePolicy: policy,
+					},
+				},
+				RequiredPeerCount: def.RequiredPeerCount,
+				MaximumPeerCount:  def.MaxPeerCount,
+				BlockToLive:       def.BlockToLive,
+			},
+		},
+	}, nil
+}
+
+//ParseCollectionConfig parses the JSON array of collection definitions, as in the collections
+//JSON file of the peer CLI, into the collection configuration of a chaincode
+func ParseCollectionConfig(collectionsJSON []byte) ([]*common.CollectionConfig, error) {
+	var defs []CollectionConfigDefinition
+	if err := json.Unmarshal(collectionsJSON, &defs); err != nil {
+		return nil, errors.Wrap(err, "invalid collections JSON")
+	}
+	if len(defs) == 0 {
+		return nil, errors.New("no collections defined")
+	}
+
+	names := make(map[string]bool)
+	var collConfig []*common.CollectionConfig
+	for i, def := range defs {
+		if names[def.Name] {
+			return nil, errors.Errorf("collection [%s] is defined more than once", def.Name)
+		}
+		names[def.Name] = true
+
+		config, err := NewCollectionConfig(def)
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("invalid collection at index %d", i))
+		}
+		collConfig = append(collConfig, config)
+	}
+	return collConfig, nil
+}
+
+//LoadCollectionConfig loads the collection configuration of a chaincode from a collections JSON file
+func LoadCollectionConfig(path string) ([]*common.CollectionConfig, error) {
+	collectionsJSON, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading collections file failed")
+	}
+	return ParseCollectionConfig(collectionsJSON)
+}
@@ -0,0 +1,142 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+)
+
+func TestNewCCPolicy(t *testing.T) {
+	policy, err := NewCCPolicy("OR('Org1MSP.member', AND('Org2MSP.peer', 'Org3MSP.peer'))")
+	if err != nil {
+		t.Fatalf("Failed to parse policy: %s", err)
+	}
+
+	var principals []string
+	for _, identity := range policy.Identities {
+		role := &msp.MSPRole{}
+		if err := proto.Unmarshal(identity.Principal, role); err != nil {
+			t.Fatalf("Failed to unmarshal principal: %s", err)
+		}
+		principals = append(principals, role.MspIdentifier+"."+strings.ToLower(role.Role.String()))
+	}
+	// cauthdsl adds the principals of the nested expressions first
+	assert.Equal(t, []string{"Org2MSP.peer", "Org3MSP.peer", "Org1MSP.member"}, principals)
+
+	// 1 out of (Org1MSP.member, 2 out of (Org2MSP.peer, Org3MSP.peer)), by the indexes of the principals
+	rule := cauthdsl.Or(cauthdsl.SignedBy(2), cauthdsl.And(cauthdsl.SignedBy(0), cauthdsl.SignedBy(1)))
+	assert.True(t, proto.Equal(rule, policy.Rule), "unexpected rule %v", policy.Rule)
+
+	// Single principals are equivalent to the cauthdsl builders
+	policy, err = NewCCPolicy("OR('Org1MSP.member')")
+	if err != nil {
+		t.Fatalf("Failed to parse policy: %s", err)
+	}
+	assert.True(t, proto.Equal(cauthdsl.SignedByAnyMember([]string{"Org1MSP"}), policy), "unexpected policy %v", policy)
+
+	for _, invalid := range []string{"", "OR('Org1MSP.owner')", "OR('Org1MSP.member'", "OR('Org1MSP')"} {
+		_, err = NewCCPolicy(invalid)
+		assert.Error(t, err, "expected error for invalid policy [%s]", invalid)
+	}
+}
+
+func TestParseCollectionConfig(t *testing.T) {
+	collectionsJSON := `[
+		{
+			"name": "collectionMarbles",
+			"policy": "OR('Org1MSP.member', 'Org2MSP.member')",
+			"requiredPeerCount": 0,
+			"maxPeerCount": 3,
+			"blockToLive": 1000000
+		},
+		{
+			"name": "collectionMarblePrivateDetails",
+			"policy": "OR('Org1MSP.member')",
+			"requiredPeerCount": 1,
+			"maxPeerCount": 3
+		}
+	]`
+
+	collConfig, err := ParseCollectionConfig([]byte(collectionsJSON))
+	if err != nil {
+		t.Fatalf("Failed to parse collections JSON: %s", err)
+	}
+	if !assert.Len(t, collConfig, 2) {
+		return
+	}
+
+	marbles := collConfig[0].GetStaticCollectionConfig()
+	assert.Equal(t, "collectionMarbles", marbles.Name)
+	assert.Equal(t, int32(0), marbles.RequiredPeerCount)
+	assert.Equal(t, int32(3), marbles.MaximumPeerCount)
+	assert.Equal(t, uint64(1000000), marbles.BlockToLive)
+	assert.Len(t, marbles.MemberOrgsPolicy.GetSignaturePolicy().Identities, 2)
+
+	details := collConfig[1].GetStaticCollectionConfig()
+	assert.Equal(t, "collectionMarblePrivateDetails", details.Name)
+	assert.Equal(t, int32(1), details.RequiredPeerCount)
+	assert.Equal(t, uint64(0), details.BlockToLive)
+	assert.True(t, proto.Equal(cauthdsl.SignedByAnyMember([]string{"Org1MSP"}), details.MemberOrgsPolicy.GetSignaturePolicy()))
+}
+
+func TestParseCollectionConfigErrors(t *testing.T) {
+	invalid := map[string]string{
+		"not JSON":            `{"name":`,
+		"no collections":      `[]`,
+		"missing name":        `[{"policy": "OR('Org1MSP.member')", "maxPeerCount": 1}]`,
+		"missing policy":      `[{"name": "coll1", "maxPeerCount": 1}]`,
+		"invalid policy":      `[{"name": "coll1", "policy": "OR('Org1MSP.owner')", "maxPeerCount": 1}]`,
+		"negative peer count": `[{"name": "coll1", "policy": "OR('Org1MSP.member')", "requiredPeerCount": -1}]`,
+		"max below required":  `[{"name": "coll1", "policy": "OR('Org1MSP.member')", "requiredPeerCount": 2, "maxPeerCount": 1}]`,
+		"negative blocks":     `[{"name": "coll1", "policy": "OR('Org1MSP.member')", "maxPeerCount": 1, "blockToLive": -1}]`,
+		"duplicate name": `[{"name": "coll1", "policy": "OR('Org1MSP.member')", "maxPeerCount": 1},
+			{"name": "coll1", "policy": "OR('Org2MSP.member')", "maxPeerCount": 1}]`,
+	}
+	for desc, collectionsJSON := range invalid {
+		_, err := ParseCollectionConfig([]byte(collectionsJSON))
+		assert.Error(t, err, "expected error for %s", desc)
+	}
+
+	// Errors identify the invalid collection
+	_, err := ParseCollectionConfig([]byte(`[{"name": "coll1", "policy": "OR('Org1MSP.member')", "maxPeerCount": 1},
+		{"name": "coll2", "policy": "OR('Org1MSP.owner')", "maxPeerCount": 1}]`))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "index 1")
+		assert.Contains(t, err.Error(), "coll2")
+	}
+}
+
+func TestLoadCollectionConfig(t *testing.T) {
+	file, err := ioutil.TempFile("", "collections")
+	if err != nil {
+		t.Fatalf("Failed to create collections file: %s", err)
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.WriteString(`[{"name": "coll1", "policy": "OR('Org1MSP.member')", "maxPeerCount": 1}]`); err != nil {
+		t.Fatalf("Failed to write collections file: %s", err)
+	}
+	file.Close()
+
+	collConfig, err := LoadCollectionConfig(file.Name())
+	if err != nil {
+		t.Fatalf("Failed to load collections file: %s", err)
+	}
+	assert.Len(t, collConfig, 1)
+	assert.Equal(t, "coll1", collConfig[0].GetStaticCollectionConfig().Name)
+
+	_, err = LoadCollectionConfig("invalid/collections.json")
+	assert.Error(t, err, "expected error for missing collections file")
+}
@@ -0,0 +1,81 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package reconciler
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// ActionType is the type of a resource management call of a plan.
+type ActionType string
+
+const (
+	// CreateChannel creates the channel with SaveChannel
+	CreateChannel ActionType = "create channel"
+	// JoinChannel joins the peers to the channel
+	JoinChannel ActionType = "join channel"
+	// InstallCC installs the chaincode on the peers
+	InstallCC ActionType = "install chaincode"
+	// InstantiateCC instantiates the chaincode on the channel
+	InstantiateCC ActionType = "instantiate chaincode"
+	// UpgradeCC upgrades the chaincode instantiated on the channel
+	UpgradeCC ActionType = "upgrade chaincode"
+)
+
+// Action is a resource management call that brings the network closer to the desired state.
+type Action struct {
+	Type      ActionType
+	ChannelID string
+	// ConfigPath is the channel configuration transaction that creates the channel
+	ConfigPath string
+	// Peers are the names of the target peers
+	Peers []string
+	// Chaincode is the desired chaincode of chaincode actions
+	Chaincode *Chaincode
+	// CurrentVersion is the version of the chaincode that is upgraded
+	CurrentVersion string
+}
+
+// String describes the action.
+func (a *Action) String() string {
+	var desc bytes.Buffer
+	desc.WriteString(string(a.Type))
+	if a.Chaincode != nil {
+		fmt.Fprintf(&desc, " %s:%s", a.Chaincode.Name, a.Chaincode.Version)
+		if a.CurrentVersion != "" {
+			fmt.Fprintf(&desc, " (from %s)", a.CurrentVersion)
+		}
+	}
+	switch {
+	case a.Chaincode == nil:
+		fmt.Fprintf(&desc, " %s", a.ChannelID)
+	case a.Type != InstallCC:
+		fmt.Fprintf(&desc, " on channel %s", a.ChannelID)
+	}
+	if len(a.Peers) > 0 {
+		fmt.Fprintf(&desc, " [%s]", strings.Join(a.Peers, ", "))
+	}
+	return desc.String()
+}
+
+// Plan is the ordered list of actions that brings the network to the desired state.
+// An empty plan means the network is in the desired state.
+type Plan []*Action
+
+// String describes the actions of the plan, one per line.
+func (p Plan) String() string {
+	if len(p) == 0 {
+		return "no changes"
+	}
+	lines := make([]string, len(p))
+	for i, action := range p {
+		lines[i] = fmt.Sprintf("%d. %s", i+1, action)
+	}
+	return strings.Join(lines, "\n")
+}
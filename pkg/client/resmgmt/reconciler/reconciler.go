@@ -0,0 +1,351 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package reconciler brings the network to a desired state of channels, channel peers and chaincodes
+// with the minimal set of resource management calls, so that reconciling the same desired state again
+// makes no calls once the network is in that state.
+package reconciler
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/resmgmt"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/logging"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+var logger = logging.NewLogger("fabric_sdk_go")
+
+// ResourceMgmtClient queries and changes the resources of the network, as the resource management client does.
+type ResourceMgmtClient interface {
+	SaveChannel(req resmgmt.SaveChannelRequest, options ...resmgmt.RequestOption) error
+	JoinChannel(channelID string, options ...resmgmt.RequestOption) error
+	InstallCC(req resmgmt.InstallCCRequest, options ...resmgmt.RequestOption) ([]resmgmt.InstallCCResponse, error)
+	InstantiateCC(channelID string, req resmgmt.InstantiateCCRequest, options ...resmgmt.RequestOption) error
+	UpgradeCC(channelID string, req resmgmt.UpgradeCCRequest, options ...resmgmt.RequestOption) error
+	QueryConfig(channelID string, options ...resmgmt.RequestOption) (*common.Config, error)
+	QueryChannels(proposalProcessor fab.ProposalProcessor) (*pb.ChannelQueryResponse, error)
+	QueryInstalledChaincodes(proposalProcessor fab.ProposalProcessor) (*pb.ChaincodeQueryResponse, error)
+	QueryInstantiatedChaincodes(channelID string, options ...resmgmt.RequestOption) (*pb.ChaincodeQueryResponse, error)
+}
+
+// Reconciler compares the desired state of the network with the actual state reported by the peers
+// and makes the resource management calls that bring the network to the desired state.
+type Reconciler struct {
+	client ResourceMgmtClient
+	peers  map[string]fab.Peer
+}
+
+// New returns a reconciler of the network of the given peers, by the names used in the desired states.
+func New(client ResourceMgmtClient, peers map[string]fab.Peer) *Reconciler {
+	return &Reconciler{client: client, peers: peers}
+}
+
+// Reconcile brings the network to the desired state and returns the plan of the calls that were made.
+// With dry run, the plan is returned without making any changes.
+func (r *Reconciler) Reconcile(state *State, dryRun bool) (Plan, error) {
+	plan, err := r.Plan(state)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return plan, nil
+	}
+	return plan, r.Apply(plan)
+}
+
+// Plan compares the desired state with the actual state of the network and returns the ordered actions
+// that bring the network to the desired state:
+//   - channels that don't exist are created, and the peers that haven't joined a channel join it
+//   - chaincodes are installed on the peers of the channels that don't have the chaincode version installed
+//   - chaincodes that aren't instantiated on a channel are instantiated, and those instantiated
+//     with another version are upgraded
+//
+// The channels and installed chaincodes are queried from the peers, and the instantiated chaincodes
+// from a peer that joined the channel. A channel is created if no peer joined it and the orderer
+// doesn't return its configuration.
+func (r *Reconciler) Plan(state *State) (Plan, error) {
+	if state == nil {
+		return nil, errors.New("desired state is required")
+	}
+	if err := state.validate(); err != nil {
+		return nil, errors.WithMessage(err, "invalid desired state")
+	}
+
+	actual := newActualState(r)
+	var plan Plan
+	for _, ch := range state.Channels {
+		channelPlan, err := r.planChannel(ch, actual)
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("planning channel [%s] failed", ch.ID))
+		}
+		plan = append(plan, channelPlan...)
+	}
+	return plan, nil
+}
+
+func (r *Reconciler) planChannel(ch *Channel, actual *actualState) (Plan, error) {
+	var plan Plan
+
+	var joined, notJoined []string
+	for _, name := range ch.Peers {
+		channels, err := actual.channels(name)
+		if err != nil {
+			return nil, err
+		}
+		if channels[ch.ID] {
+			joined = append(joined, name)
+		} else {
+			notJoined = append(notJoined, name)
+		}
+	}
+
+	if len(joined) == 0 && !r.channelExists(ch.ID) {
+		if ch.ConfigPath == "" {
+			return nil, errors.New("channel doesn't exist and has no channel configuration")
+		}
+		plan = append(plan, &Action{Type: CreateChannel, ChannelID: ch.ID, ConfigPath: ch.ConfigPath})
+	}
+	if len(notJoined) > 0 {
+		plan = append(plan, &Action{Type: JoinChannel, ChannelID: ch.ID, Peers: notJoined})
+	}
+
+	instantiated := make(map[string]string)
+	if len(joined) > 0 {
+		var err error
+		if instantiated, err = actual.instantiated(ch.ID, joined[0]); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, cc := range ch.Chaincodes {
+		var installPeers []string
+		for _, name := range ch.Peers {
+			installed, err := actual.installed(name)
+			if err != nil {
+				return nil, err
+			}
+			if !installed[chaincodeKey(cc.Name, cc.Version)] {
+				installPeers = append(installPeers, name)
+				// Chaincodes of several channels are installed once
+				installed[chaincodeKey(cc.Name, cc.Version)] = true
+			}
+		}
+		if len(installPeers) > 0 {
+			plan = append(plan, &Action{Type: InstallCC, ChannelID: ch.ID, Peers: installPeers, Chaincode: cc})
+		}
+
+		version, ok := instantiated[cc.Name]
+		if !ok {
+			plan = append(plan, &Action{Type: InstantiateCC, ChannelID: ch.ID, Peers: ch.Peers, Chaincode: cc})
+		} else if version != cc.Version {
+			plan = append(plan, &Action{Type: UpgradeCC, ChannelID: ch.ID, Peers: ch.Peers, Chaincode: cc, CurrentVersion: version})
+		}
+	}
+	return plan, nil
+}
+
+// channelExists returns whether the orderer returns the configuration of the channel
+func (r *Reconciler) channelExists(channelID string) bool {
+	if _, err := r.client.QueryConfig(channelID); err != nil {
+		logger.Debugf("Configuration of channel [%s] not available: %s", channelID, err)
+		return false
+	}
+	return true
+}
+
+// Apply makes the resource management calls of the plan in order. It stops at the first failed call.
+func (r *Reconciler) Apply(plan Plan) error {
+	for _, action := range plan {
+		logger.Infof("Reconciling network: %s", action)
+		if err := r.apply(action); err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("failed to %s", action))
+		}
+	}
+	return nil
+}
+
+func (r *Reconciler) apply(action *Action) error {
+	targets, err := r.targets(action.Peers)
+	if err != nil {
+		return err
+	}
+
+	switch action.Type {
+	case CreateChannel:
+		return r.client.SaveChannel(resmgmt.SaveChannelRequest{ChannelID: action.ChannelID, ChannelConfig: action.ConfigPath})
+	case JoinChannel:
+		return r.client.JoinChannel(action.ChannelID, resmgmt.WithTargets(targets...))
+	case InstallCC:
+		cc := action.Chaincode
+		req := resmgmt.InstallCCRequest{Name: cc.Name, Path: cc.Path, Version: cc.Version, Package: cc.Package, PackageFile: cc.PackageFile}
+		if cc.PackageFile != "" {
+			req.Package = nil
+		}
+		_, err := r.client.InstallCC(req, resmgmt.WithTargets(targets...))
+		return err
+	case InstantiateCC, UpgradeCC:
+		req, err := instantiateRequest(action.Chaincode, targets)
+		if err != nil {
+			return err
+		}
+		if action.Type == UpgradeCC {
+			return r.client.UpgradeCC(action.ChannelID, resmgmt.UpgradeCCRequest(req), resmgmt.WithTargets(targets...))
+		}
+		return r.client.InstantiateCC(action.ChannelID, req, resmgmt.WithTargets(targets...))
+	default:
+		return errors.Errorf("unknown action type [%s]", action.Type)
+	}
+}
+
+func (r *Reconciler) targets(names []string) ([]fab.Peer, error) {
+	var targets []fab.Peer
+	for _, name := range names {
+		peer, ok := r.peers[name]
+		if !ok {
+			return nil, errors.Errorf("unknown peer [%s]", name)
+		}
+		targets = append(targets, peer)
+	}
+	return targets, nil
+}
+
+// instantiateRequest creates the request that instantiates or upgrades the chaincode. The endorsement
+// policy defaults to any member of the organizations of the target peers
+func instantiateRequest(cc *Chaincode, targets []fab.Peer) (resmgmt.InstantiateCCRequest, error) {
+	req := resmgmt.InstantiateCCRequest{Name: cc.Name, Path: cc.Path, Version: cc.Version}
+	for _, arg := range cc.Args {
+		req.Args = append(req.Args, []byte(arg))
+	}
+
+	if cc.Policy != "" {
+		policy, err := resmgmt.NewCCPolicy(cc.Policy)
+		if err != nil {
+			return req, err
+		}
+		req.Policy = policy
+	} else {
+		req.Policy = cauthdsl.SignedByAnyMember(mspIDs(targets))
+	}
+
+	for _, def := range cc.Collections {
+		collConfig, err := resmgmt.NewCollectionConfig(def)
+		if err != nil {
+			return req, err
+		}
+		req.CollConfig = append(req.CollConfig, collConfig)
+	}
+	return req, nil
+}
+
+func mspIDs(peers []fab.Peer) []string {
+	ids := make(map[string]bool)
+	var mspIDs []string
+	for _, peer := range peers {
+		if !ids[peer.MSPID()] {
+			ids[peer.MSPID()] = true
+			mspIDs = append(mspIDs, peer.MSPID())
+		}
+	}
+	sort.Strings(mspIDs)
+	return mspIDs
+}
+
+func chaincodeKey(name, version string) string {
+	return name + ":" + version
+}
+
+// actualState is the state of the network reported by the peers. Each peer and channel is queried once
+type actualState struct {
+	r                 *Reconciler
+	peerChannels      map[string]map[string]bool
+	peerChaincodes    map[string]map[string]bool
+	channelChaincodes map[string]map[string]string
+}
+
+func newActualState(r *Reconciler) *actualState {
+	return &actualState{
+		r:                 r,
+		peerChannels:      make(map[string]map[string]bool),
+		peerChaincodes:    make(map[string]map[string]bool),
+		channelChaincodes: make(map[string]map[string]string),
+	}
+}
+
+// channels returns the IDs of the channels the peer joined
+func (s *actualState) channels(name string) (map[string]bool, error) {
+	if channels, ok := s.peerChannels[name]; ok {
+		return channels, nil
+	}
+
+	peer, ok := s.r.peers[name]
+	if !ok {
+		return nil, errors.Errorf("unknown peer [%s]", name)
+	}
+	response, err := s.r.client.QueryChannels(peer)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("querying channels of peer [%s] failed", name))
+	}
+
+	channels := make(map[string]bool)
+	for _, channel := range response.Channels {
+		channels[channel.ChannelId] = true
+	}
+	s.peerChannels[name] = channels
+	return channels, nil
+}
+
+// installed returns the name:version of the chaincodes installed on the peer
+func (s *actualState) installed(name string) (map[string]bool, error) {
+	if chaincodes, ok := s.peerChaincodes[name]; ok {
+		return chaincodes, nil
+	}
+
+	peer, ok := s.r.peers[name]
+	if !ok {
+		return nil, errors.Errorf("unknown peer [%s]", name)
+	}
+	response, err := s.r.client.QueryInstalledChaincodes(peer)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("querying installed chaincodes of peer [%s] failed", name))
+	}
+
+	chaincodes := make(map[string]bool)
+	for _, chaincode := range response.Chaincodes {
+		chaincodes[chaincodeKey(chaincode.Name, chaincode.Version)] = true
+	}
+	s.peerChaincodes[name] = chaincodes
+	return chaincodes, nil
+}
+
+// instantiated returns the versions of the chaincodes instantiated on the channel, by name
+func (s *actualState) instantiated(channelID string, name string) (map[string]string, error) {
+	if chaincodes, ok := s.channelChaincodes[channelID]; ok {
+		return chaincodes, nil
+	}
+
+	peer, ok := s.r.peers[name]
+	if !ok {
+		return nil, errors.Errorf("unknown peer [%s]", name)
+	}
+	response, err := s.r.client.QueryInstantiatedChaincodes(channelID, resmgmt.WithTargets(peer))
+	if err != nil {
+		return nil, errors.WithMessage(err, "querying instantiated chaincodes failed")
+	}
+
+	chaincodes := make(map[string]string)
+	for _, chaincode := range response.Chaincodes {
+		chaincodes[chaincode.Name] = chaincode.Version
+	}
+	s.channelChaincodes[channelID] = chaincodes
+	return chaincodes, nil
+}
@@ -0,0 +1,448 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package reconciler
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/resmgmt"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource/api"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// fakeClient is a network of peers that records the resource management calls
+type fakeClient struct {
+	orderer      map[string]bool
+	channels     map[string]map[string]bool
+	installed    map[string]map[string]bool
+	instantiated map[string]map[string]string
+	calls        []string
+	failCall     string
+	instantiates []resmgmt.InstantiateCCRequest
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		orderer:      make(map[string]bool),
+		channels:     make(map[string]map[string]bool),
+		installed:    make(map[string]map[string]bool),
+		instantiated: make(map[string]map[string]string),
+	}
+}
+
+func (c *fakeClient) call(desc string, options ...resmgmt.RequestOption) ([]string, error) {
+	opts := resmgmt.Opts{}
+	for _, option := range options {
+		if err := option(&opts); err != nil {
+			return nil, err
+		}
+	}
+	var names []string
+	for _, target := range opts.Targets {
+		names = append(names, target.Name())
+	}
+	if len(names) > 0 {
+		desc += " " + strings.Join(names, ",")
+	}
+	c.calls = append(c.calls, desc)
+	if c.failCall != "" && strings.HasPrefix(desc, c.failCall) {
+		return nil, errors.New("call failed")
+	}
+	return names, nil
+}
+
+func (c *fakeClient) SaveChannel(req resmgmt.SaveChannelRequest, options ...resmgmt.RequestOption) error {
+	if _, err := c.call("save "+req.ChannelID, options...); err != nil {
+		return err
+	}
+	c.orderer[req.ChannelID] = true
+	return nil
+}
+
+func (c *fakeClient) JoinChannel(channelID string, options ...resmgmt.RequestOption) error {
+	names, err := c.call("join "+channelID, options...)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if c.channels[name] == nil {
+			c.channels[name] = make(map[string]bool)
+		}
+		c.channels[name][channelID] = true
+	}
+	return nil
+}
+
+func (c *fakeClient) InstallCC(req resmgmt.InstallCCRequest, options ...resmgmt.RequestOption) ([]resmgmt.InstallCCResponse, error) {
+	names, err := c.call("install "+chaincodeKey(req.Name, req.Version), options...)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		if c.installed[name] == nil {
+			c.installed[name] = make(map[string]bool)
+		}
+		c.installed[name][chaincodeKey(req.Name, req.Version)] = true
+	}
+	return nil, nil
+}
+
+func (c *fakeClient) InstantiateCC(channelID string, req resmgmt.InstantiateCCRequest, options ...resmgmt.RequestOption) error {
+	if _, err := c.call("instantiate "+chaincodeKey(req.Name, req.Version)+" "+channelID, options...); err != nil {
+		return err
+	}
+	c.instantiates = append(c.instantiates, req)
+	return c.setInstantiated(channelID, req)
+}
+
+func (c *fakeClient) UpgradeCC(channelID string, req resmgmt.UpgradeCCRequest, options ...resmgmt.RequestOption) error {
+	if _, err := c.call("upgrade "+chaincodeKey(req.Name, req.Version)+" "+channelID, options...); err != nil {
+		return err
+	}
+	return c.setInstantiated(channelID, resmgmt.InstantiateCCRequest(req))
+}
+
+func (c *fakeClient) setInstantiated(channelID string, req resmgmt.InstantiateCCRequest) error {
+	if c.instantiated[channelID] == nil {
+		c.instantiated[channelID] = make(map[string]string)
+	}
+	c.instantiated[channelID][req.Name] = req.Version
+	return nil
+}
+
+func (c *fakeClient) QueryConfig(channelID string, options ...resmgmt.RequestOption) (*common.Config, error) {
+	c.calls = append(c.calls, "query config "+channelID)
+	if !c.orderer[channelID] {
+		return nil, errors.New("channel not found")
+	}
+	return &common.Config{}, nil
+}
+
+func (c *fakeClient) QueryChannels(proposalProcessor fab.ProposalProcessor) (*pb.ChannelQueryResponse, error) {
+	name := proposalProcessor.(fab.Peer).Name()
+	c.calls = append(c.calls, "query channels "+name)
+	response := &pb.ChannelQueryResponse{}
+	for channelID := range c.channels[name] {
+		response.Channels = append(response.Channels, &pb.ChannelInfo{ChannelId: channelID})
+	}
+	return response, nil
+}
+
+func (c *fakeClient) QueryInstalledChaincodes(proposalProcessor fab.ProposalProcessor) (*pb.ChaincodeQueryResponse, error) {
+	name := proposalProcessor.(fab.Peer).Name()
+	c.calls = append(c.calls, "query installed "+name)
+	response := &pb.ChaincodeQueryResponse{}
+	for key := range c.installed[name] {
+		nameVersion := strings.Split(key, ":")
+		response.Chaincodes = append(response.Chaincodes, &pb.ChaincodeInfo{Name: nameVersion[0], Version: nameVersion[1]})
+	}
+	return response, nil
+}
+
+func (c *fakeClient) QueryInstantiatedChaincodes(channelID string, options ...resmgmt.RequestOption) (*pb.ChaincodeQueryResponse, error) {
+	if _, err := c.call("query instantiated "+channelID, options...); err != nil {
+		return nil, err
+	}
+	response := &pb.ChaincodeQueryResponse{}
+	for name, version := range c.instantiated[channelID] {
+		response.Chaincodes = append(response.Chaincodes, &pb.ChaincodeInfo{Name: name, Version: version})
+	}
+	return response, nil
+}
+
+// changes returns the calls that change the network
+func (c *fakeClient) changes() []string {
+	var changes []string
+	for _, call := range c.calls {
+		if !strings.HasPrefix(call, "query") {
+			changes = append(changes, call)
+		}
+	}
+	return changes
+}
+
+func newTestPeers() map[string]fab.Peer {
+	return map[string]fab.Peer{
+		"peer0.org1": &fcmocks.MockPeer{MockName: "peer0.org1", MockURL: "http://peer0.org1.com", MockMSP: "Org1MSP"},
+		"peer1.org1": &fcmocks.MockPeer{MockName: "peer1.org1", MockURL: "http://peer1.org1.com", MockMSP: "Org1MSP"},
+		"peer0.org2": &fcmocks.MockPeer{MockName: "peer0.org2", MockURL: "http://peer0.org2.com", MockMSP: "Org2MSP"},
+	}
+}
+
+func newTestState() *State {
+	pkg := &api.CCPackage{Type: pb.ChaincodeSpec_GOLANG, Code: []byte("code")}
+	return &State{
+		Channels: []*Channel{
+			{
+				ID:         "mychannel",
+				ConfigPath: "mychannel.tx",
+				Peers:      []string{"peer0.org1", "peer0.org2"},
+				Chaincodes: []*Chaincode{
+					{Name: "example", Path: "github.com/example_cc", Version: "v0", Args: []string{"init", "a", "100"}, Package: pkg},
+				},
+			},
+			{
+				ID:         "orgchannel",
+				ConfigPath: "orgchannel.tx",
+				Peers:      []string{"peer0.org1", "peer1.org1"},
+				Chaincodes: []*Chaincode{
+					{Name: "example", Path: "github.com/example_cc", Version: "v0", Policy: "OR('Org1MSP.peer')", Package: pkg},
+				},
+			},
+		},
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	client := newFakeClient()
+	r := New(client, newTestPeers())
+
+	plan, err := r.Reconcile(newTestState(), false)
+	if err != nil {
+		t.Fatalf("Failed to reconcile network: %s", err)
+	}
+	assert.Len(t, plan, 8)
+
+	// The chaincode is installed once on peer0.org1 for both channels
+	expected := []string{
+		"save mychannel",
+		"join mychannel peer0.org1,peer0.org2",
+		"install example:v0 peer0.org1,peer0.org2",
+		"instantiate example:v0 mychannel peer0.org1,peer0.org2",
+		"save orgchannel",
+		"join orgchannel peer0.org1,peer1.org1",
+		"install example:v0 peer1.org1",
+		"instantiate example:v0 orgchannel peer0.org1,peer1.org1",
+	}
+	assert.Equal(t, expected, client.changes())
+
+	// Default policy is any member of the orgs of the channel's peers
+	if assert.Len(t, client.instantiates, 2) {
+		req := client.instantiates[0]
+		assert.Equal(t, [][]byte{[]byte("init"), []byte("a"), []byte("100")}, req.Args)
+		assert.True(t, proto.Equal(cauthdsl.SignedByAnyMember([]string{"Org1MSP", "Org2MSP"}), req.Policy), "unexpected policy %v", req.Policy)
+		assert.True(t, proto.Equal(cauthdsl.SignedByAnyPeer([]string{"Org1MSP"}), client.instantiates[1].Policy), "unexpected policy %v", client.instantiates[1].Policy)
+	}
+
+	// Reconciling the reconciled network makes no changes
+	client.calls = nil
+	plan, err = r.Reconcile(newTestState(), false)
+	if err != nil {
+		t.Fatalf("Failed to reconcile network: %s", err)
+	}
+	assert.Empty(t, plan)
+	assert.Equal(t, "no changes", plan.String())
+	assert.Empty(t, client.changes())
+
+	// Each peer and channel is queried once
+	queries := make(map[string]int)
+	for _, call := range client.calls {
+		queries[call]++
+	}
+	for call, count := range queries {
+		assert.Equal(t, 1, count, "unexpected number of calls to %s", call)
+	}
+}
+
+func TestReconcileChanges(t *testing.T) {
+	client := newFakeClient()
+	r := New(client, newTestPeers())
+	if _, err := r.Reconcile(newTestState(), false); err != nil {
+		t.Fatalf("Failed to reconcile network: %s", err)
+	}
+
+	// New version and new peer
+	state := newTestState()
+	state.Channels[0].Chaincodes[0].Version = "v1"
+	state.Channels[0].Peers = append(state.Channels[0].Peers, "peer1.org1")
+	state.Channels[1].Chaincodes = nil
+
+	client.calls = nil
+	plan, err := r.Reconcile(state, false)
+	if err != nil {
+		t.Fatalf("Failed to reconcile network: %s", err)
+	}
+	expected := []string{
+		"join mychannel peer1.org1",
+		"install example:v1 peer0.org1,peer0.org2,peer1.org1",
+		"upgrade example:v1 mychannel peer0.org1,peer0.org2,peer1.org1",
+	}
+	assert.Equal(t, expected, client.changes())
+	if assert.Len(t, plan, 3) {
+		assert.Equal(t, UpgradeCC, plan[2].Type)
+		assert.Equal(t, "v0", plan[2].CurrentVersion)
+		assert.Equal(t, "upgrade chaincode example:v1 (from v0) on channel mychannel [peer0.org1, peer0.org2, peer1.org1]", plan[2].String())
+	}
+}
+
+func TestReconcileDryRun(t *testing.T) {
+	client := newFakeClient()
+	r := New(client, newTestPeers())
+
+	plan, err := r.Reconcile(newTestState(), true)
+	if err != nil {
+		t.Fatalf("Failed to plan network changes: %s", err)
+	}
+	assert.Len(t, plan, 8)
+	assert.Empty(t, client.changes())
+	assert.True(t, strings.HasPrefix(plan.String(), "1. create channel mychannel\n2. join channel mychannel [peer0.org1, peer0.org2]\n"), plan.String())
+
+	// The plan can be applied later
+	if err := r.Apply(plan); err != nil {
+		t.Fatalf("Failed to apply plan: %s", err)
+	}
+	assert.Len(t, client.changes(), 8)
+}
+
+func TestReconcileExistingChannel(t *testing.T) {
+	client := newFakeClient()
+	client.orderer["mychannel"] = true
+	r := New(client, newTestPeers())
+
+	state := newTestState()
+	state.Channels[0].ConfigPath = ""
+	state.Channels = state.Channels[:1]
+
+	plan, err := r.Plan(state)
+	if err != nil {
+		t.Fatalf("Failed to plan network changes: %s", err)
+	}
+	if assert.NotEmpty(t, plan) {
+		assert.Equal(t, JoinChannel, plan[0].Type)
+	}
+
+	// Channels that don't exist need a channel configuration
+	client.orderer["mychannel"] = false
+	_, err = r.Plan(state)
+	assert.Error(t, err, "expected error for channel without configuration")
+}
+
+func TestReconcileErrors(t *testing.T) {
+	client := newFakeClient()
+	client.failCall = "install"
+	r := New(client, newTestPeers())
+
+	_, err := r.Reconcile(newTestState(), false)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "install chaincode example:v0")
+	}
+	// Calls stop at the first failure
+	assert.Equal(t, []string{"save mychannel", "join mychannel peer0.org1,peer0.org2", "install example:v0 peer0.org1,peer0.org2"}, client.changes())
+
+	// Unknown peers fail before any change is made
+	client = newFakeClient()
+	r = New(client, newTestPeers())
+	state := newTestState()
+	state.Channels[1].Peers = append(state.Channels[1].Peers, "peer2.org1")
+	_, err = r.Reconcile(state, false)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "peer2.org1")
+	}
+	assert.Empty(t, client.changes())
+
+	_, err = r.Reconcile(nil, false)
+	assert.Error(t, err, "expected error for missing state")
+}
+
+func TestValidateState(t *testing.T) {
+	invalid := map[string]func(s *State){
+		"missing channel ID": func(s *State) { s.Channels[0].ID = "" },
+		"duplicate channel":  func(s *State) { s.Channels[1].ID = s.Channels[0].ID },
+		"no peers":           func(s *State) { s.Channels[0].Peers = nil },
+		"missing version":    func(s *State) { s.Channels[0].Chaincodes[0].Version = "" },
+		"no package":         func(s *State) { s.Channels[0].Chaincodes[0].Package = nil },
+		"invalid policy":     func(s *State) { s.Channels[0].Chaincodes[0].Policy = "OR('Org1MSP.owner')" },
+		"duplicate chaincode": func(s *State) {
+			s.Channels[0].Chaincodes = append(s.Channels[0].Chaincodes, s.Channels[0].Chaincodes[0])
+		},
+		"invalid collection": func(s *State) {
+			s.Channels[0].Chaincodes[0].Collections = []resmgmt.CollectionConfigDefinition{{Name: "coll1"}}
+		},
+		"negative peer counts": func(s *State) {
+			s.Channels[0].Chaincodes[0].Collections = []resmgmt.CollectionConfigDefinition{{Name: "coll1", Policy: "OR('Org1MSP.member')", RequiredPeerCount: -1}}
+		},
+	}
+
+	assert.NoError(t, newTestState().validate())
+	for desc, invalidate := range invalid {
+		state := newTestState()
+		invalidate(state)
+		assert.Error(t, state.validate(), "expected error for %s", desc)
+	}
+}
+
+func TestLoadState(t *testing.T) {
+	stateJSON := `{
+		"channels": [
+			{
+				"id": "mychannel",
+				"configPath": "mychannel.tx",
+				"peers": ["peer0.org1", "peer0.org2"],
+				"chaincodes": [
+					{
+						"name": "example",
+						"path": "github.com/example_cc",
+						"version": "v0",
+						"args": ["init"],
+						"policy": "OR('Org1MSP.member', 'Org2MSP.member')",
+						"packageFile": "example.cds",
+						"collections": [{"name": "coll1", "policy": "OR('Org1MSP.member')", "maxPeerCount": 1}]
+					}
+				]
+			}
+		]
+	}`
+
+	file, err := ioutil.TempFile("", "state")
+	if err != nil {
+		t.Fatalf("Failed to create state file: %s", err)
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.WriteString(stateJSON); err != nil {
+		t.Fatalf("Failed to write state file: %s", err)
+	}
+	file.Close()
+
+	state, err := LoadState(file.Name())
+	if err != nil {
+		t.Fatalf("Failed to load state: %s", err)
+	}
+	assert.NoError(t, state.validate())
+	if assert.Len(t, state.Channels, 1) && assert.Len(t, state.Channels[0].Chaincodes, 1) {
+		ch := state.Channels[0]
+		assert.Equal(t, "mychannel.tx", ch.ConfigPath)
+		assert.Equal(t, []string{"peer0.org1", "peer0.org2"}, ch.Peers)
+		cc := ch.Chaincodes[0]
+		assert.Equal(t, "example.cds", cc.PackageFile)
+		assert.Equal(t, []string{"init"}, cc.Args)
+		assert.Equal(t, int32(1), cc.Collections[0].MaxPeerCount)
+	}
+
+	_, err = LoadState("invalid/state.json")
+	assert.Error(t, err, "expected error for missing state file")
+}
+
+func TestPlanString(t *testing.T) {
+	cc := &Chaincode{Name: "example", Version: "v1"}
+	plan := Plan{
+		{Type: InstallCC, ChannelID: "mychannel", Peers: []string{"peer0"}, Chaincode: cc},
+		{Type: InstantiateCC, ChannelID: "mychannel", Peers: []string{"peer0"}, Chaincode: cc},
+	}
+	expected := "1. install chaincode example:v1 [peer0]\n2. instantiate chaincode example:v1 on channel mychannel [peer0]"
+	assert.Equal(t, expected, plan.String())
+}
@@ -0,0 +1,113 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package reconciler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/resmgmt"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource/api"
+)
+
+// State is the desired state of the network: the channels, the peers joined to each channel
+// and the chaincodes instantiated on each channel.
+type State struct {
+	Channels []*Channel `json:"channels"`
+}
+
+// Channel is the desired state of a channel.
+type Channel struct {
+	// ID of the channel
+	ID string `json:"id"`
+	// ConfigPath is the path of the channel configuration transaction that creates the channel
+	// (optional if the channel exists)
+	ConfigPath string `json:"configPath"`
+	// Peers are the names of the peers joined to the channel
+	Peers []string `json:"peers"`
+	// Chaincodes are the chaincodes instantiated on the channel. They are installed on the peers of the channel
+	Chaincodes []*Chaincode `json:"chaincodes"`
+}
+
+// Chaincode is the desired state of a chaincode on a channel.
+type Chaincode struct {
+	Name    string `json:"name"`
+	Path    string `json:"path"`
+	Version string `json:"version"`
+	// Args are the arguments of the instantiation or upgrade
+	Args []string `json:"args"`
+	// Policy is the endorsement policy, e.g. OR('Org1MSP.member', 'Org2MSP.member') (optional).
+	// Defaults to any member of the organizations of the channel's peers
+	Policy string `json:"policy"`
+	// Collections are the private data collections of the chaincode (optional)
+	Collections []resmgmt.CollectionConfigDefinition `json:"collections"`
+	// PackageFile is the prebuilt chaincode package file to install
+	PackageFile string `json:"packageFile"`
+	// Package is the chaincode package to install, if there's no package file
+	Package *api.CCPackage `json:"-"`
+}
+
+// LoadState loads the desired state of the network from a JSON document.
+func LoadState(path string) (*State, error) {
+	stateJSON, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading desired state failed")
+	}
+
+	state := &State{}
+	if err := json.Unmarshal(stateJSON, state); err != nil {
+		return nil, errors.Wrap(err, "invalid desired state JSON")
+	}
+	return state, nil
+}
+
+// validate validates the desired state, so that invalid states fail before any change is made
+func (s *State) validate() error {
+	channels := make(map[string]bool)
+	for _, ch := range s.Channels {
+		if ch.ID == "" {
+			return errors.New("channel ID is required")
+		}
+		if channels[ch.ID] {
+			return errors.Errorf("channel [%s] is defined more than once", ch.ID)
+		}
+		channels[ch.ID] = true
+
+		if len(ch.Peers) == 0 && len(ch.Chaincodes) > 0 {
+			return errors.Errorf("channel [%s] has chaincodes but no peers", ch.ID)
+		}
+
+		chaincodes := make(map[string]bool)
+		for _, cc := range ch.Chaincodes {
+			if cc.Name == "" || cc.Version == "" || cc.Path == "" {
+				return errors.Errorf("chaincode name, version and path are required on channel [%s]", ch.ID)
+			}
+			if chaincodes[cc.Name] {
+				return errors.Errorf("chaincode [%s] is defined more than once on channel [%s]", cc.Name, ch.ID)
+			}
+			chaincodes[cc.Name] = true
+
+			if cc.PackageFile == "" && cc.Package == nil {
+				return errors.Errorf("chaincode [%s] on channel [%s] has no package", cc.Name, ch.ID)
+			}
+			if cc.Policy != "" {
+				if _, err := resmgmt.NewCCPolicy(cc.Policy); err != nil {
+					return errors.WithMessage(err, fmt.Sprintf("chaincode [%s] on channel [%s]", cc.Name, ch.ID))
+				}
+			}
+			for _, def := range cc.Collections {
+				if _, err := resmgmt.NewCollectionConfig(def); err != nil {
+					return errors.WithMessage(err, fmt.Sprintf("chaincode [%s] on channel [%s]", cc.Name, ch.ID))
+				}
+			}
+		}
+	}
+	return nil
+}
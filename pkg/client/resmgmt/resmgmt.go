@@ -8,7 +8,7 @@ SPDX-License-Identifier: Apache-2.0
 package resmgmt
 
 import (
-	"io/ioutil"
+	reqContext "context"
 	"math/rand"
 	"time"
 
@@ -19,6 +19,7 @@ import (
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/ccpackager/metadata"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/orderer"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/peer"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
@@ -41,6 +42,13 @@ type InstallCCRequest struct {
 	Path    string
 	Version string
 	Package *api.CCPackage
+	// Directory containing the META-INF directory of the chaincode metadata, such as CouchDB indexes,
+	// to add to the package (optional)
+	MetadataPath string
+	// Prebuilt chaincode deployment spec or signed chaincode package file, as written by the peer CLI's
+	// package command, to install as is instead of the package (optional). The chaincode name, version
+	// and path are read from the file
+	PackageFile string
 }
 
 // InstallCCResponse contains install chaincode response status
@@ -72,10 +80,12 @@ type UpgradeCCRequest struct {
 
 //Opts contains options for operations performed by ResourceMgmtClient
 type Opts struct {
-	Targets      []fab.Peer    // target peers
-	TargetFilter TargetFilter  // target filter
-	Timeout      time.Duration //timeout options for instantiate and upgrade CC
-	OrdererID    string        // use specific orderer
+	Targets         []fab.Peer         // target peers
+	TargetFilter    TargetFilter       // target filter
+	Timeout         time.Duration      //timeout options for instantiate and upgrade CC
+	OrdererID       string             // use specific orderer
+	ParentContext   reqContext.Context //parent context for instantiate and upgrade CC
+	SystemChannelID string             // orderer system channel, to check the signatures of channel creations
 }
 
 //SaveChannelRequest used to save channel request
@@ -86,6 +96,9 @@ type SaveChannelRequest struct {
 	ChannelConfig string
 	// User that signs channel configuration
 	SigningIdentity context.Identity
+	// Signatures of the channel configuration collected offline (optional), see ExtractConfigUpdate.
+	// The context user only signs the configuration if no signatures are given
+	Signatures []*common.ConfigSignature
 }
 
 //RequestOption func for each Opts argument
@@ -320,11 +333,29 @@ func (rc *Client) InstallCC(req InstallCCRequest, options ...RequestOption) ([]I
 	// For each peer query if chaincode installed. If cc is installed treat as success with message 'already installed'.
 	// If cc is not installed try to install, and if that fails add to the list with error and peer name.
 
+	if req.PackageFile != "" {
+		return rc.installCCPackageFile(req, options...)
+	}
+
 	err := checkRequiredInstallCCParams(req)
 	if err != nil {
 		return nil, err
 	}
 
+	if req.MetadataPath != "" {
+		code, err := metadata.AddToPackage(req.Package.Code, req.MetadataPath)
+		if err != nil {
+			return nil, errors.WithMessage(err, "adding chaincode metadata to package failed")
+		}
+		req.Package = &api.CCPackage{Type: req.Package.Type, Code: code}
+	}
+
+	return rc.installCC(req, nil, nil, options...)
+}
+
+// installCC installs the chaincode package, or the prebuilt deployment spec or signed chaincode package
+// if provided, on the targets that don't have the chaincode installed already
+func (rc *Client) installCC(req InstallCCRequest, cds *pb.ChaincodeDeploymentSpec, signedPackage *common.Envelope, options ...RequestOption) ([]InstallCCResponse, error) {
 	opts, err := rc.prepareResmgmtOpts(options...)
 	if err != nil {
 		return nil, errors.WithMessage(err, "failed to get opts for InstallCC")
@@ -375,7 +406,7 @@ func (rc *Client) InstallCC(req InstallCCRequest, options ...RequestOption) ([]I
 		return responses, nil
 	}
 
-	icr := api.InstallChaincodeRequest{Name: req.Name, Path: req.Path, Version: req.Version, Package: req.Package, Targets: peer.PeersToTxnProcessors(newTargets)}
+	icr := api.InstallChaincodeRequest{Name: req.Name, Path: req.Path, Version: req.Version, Package: req.Package, DeploymentSpec: cds, SignedPackage: signedPackage, Targets: peer.PeersToTxnProcessors(newTargets)}
 	transactionProposalResponse, _, err := rc.resource.InstallChaincode(icr)
 	for _, v := range transactionProposalResponse {
 		logger.Debugf("Install chaincode '%s' endorser '%s' returned ProposalResponse status:%v", req.Name, v.Endorser, v.Status)
@@ -529,48 +560,63 @@ func (rc *Client) sendCCProposal(ccProposalType chaincodeProposalType, channelID
 		return errors.WithMessage(err, "creating chaincode deploy transaction proposal failed")
 	}
 
+	timeout := rc.provider.Config().TimeoutOrDefault(core.Execute)
+	if opts.Timeout != 0 {
+		timeout = opts.Timeout
+	}
+
+	parentCtx := opts.ParentContext
+	if parentCtx == nil {
+		parentCtx = reqContext.Background()
+	}
+	reqCtx, cancel := reqContext.WithTimeout(parentCtx, timeout)
+	defer cancel()
+
 	// Process and send transaction proposal
-	txProposalResponse, err := transactor.SendTransactionProposal(tp, peersToTxnProcessors(targets))
+	txProposalResponse, err := transactor.SendTransactionProposal(reqCtx, tp, peersToTxnProcessors(targets))
 	if err != nil {
 		return errors.WithMessage(err, "sending deploy transaction proposal failed")
 	}
 
-	eventHub, err := channelService.EventHub()
+	eventService, err := channelService.EventService()
 	if err != nil {
-		return errors.WithMessage(err, "Unable to get EventHub")
+		return errors.WithMessage(err, "Unable to get event service")
 	}
-	if eventHub.IsConnected() == false {
-		err := eventHub.Connect()
-		if err != nil {
-			return err
-		}
-		defer eventHub.Disconnect()
+	// Release the event service, which closes its connection unless it's shared
+	if closer, ok := eventService.(interface{ Close() }); ok {
+		defer closer.Close()
 	}
 
 	// Register for commit event
-	statusNotifier := txn.RegisterStatus(tp.TxnID, eventHub)
+	reg, statusNotifier, err := eventService.RegisterTxStatusEvent(string(tp.TxnID))
+	if err != nil {
+		return errors.WithMessage(err, "error registering for TxStatus event")
+	}
+	defer eventService.Unregister(reg)
 
 	transactionRequest := fab.TransactionRequest{
 		Proposal:          tp,
 		ProposalResponses: txProposalResponse,
 	}
-	if _, err = createAndSendTransaction(transactor, transactionRequest); err != nil {
+	if _, err = createAndSendTransaction(reqCtx, transactor, transactionRequest); err != nil {
 		return errors.WithMessage(err, "CreateAndSendTransaction failed")
 	}
 
-	timeout := rc.provider.Config().TimeoutOrDefault(core.Execute)
-	if opts.Timeout != 0 {
-		timeout = opts.Timeout
-	}
-
 	select {
-	case result := <-statusNotifier:
+	case event, ok := <-statusNotifier:
+		if !ok {
+			return errors.New("TxStatus event registration was closed")
+		}
+		result := txn.NewStatus(event)
 		if result.Error == nil {
 			return nil
 		}
 		return errors.WithMessage(result.Error, "instantiateOrUpgradeCC failed")
-	case <-time.After(timeout):
-		return errors.New("instantiateOrUpgradeCC timeout")
+	case <-reqCtx.Done():
+		if reqCtx.Err() == reqContext.DeadlineExceeded {
+			return errors.New("instantiateOrUpgradeCC timeout")
+		}
+		return errors.Wrap(reqCtx.Err(), "instantiateOrUpgradeCC cancelled")
 	}
 
 }
@@ -599,14 +645,14 @@ func (rc *Client) prepareResmgmtOpts(options ...RequestOption) (Opts, error) {
 	return resmgmtOpts, nil
 }
 
-func createAndSendTransaction(sender fab.Sender, request fab.TransactionRequest) (*fab.TransactionResponse, error) {
+func createAndSendTransaction(reqCtx reqContext.Context, sender fab.Sender, request fab.TransactionRequest) (*fab.TransactionResponse, error) {
 
 	tx, err := sender.CreateTransaction(request)
 	if err != nil {
 		return nil, errors.WithMessage(err, "CreateTransaction failed")
 	}
 
-	transactionResponse, err := sender.SendTransaction(tx)
+	transactionResponse, err := sender.SendTransaction(reqCtx, tx)
 	if err != nil {
 		return nil, errors.WithMessage(err, "SendTransaction failed")
 
@@ -647,34 +693,62 @@ func (rc *Client) SaveChannel(req SaveChannelRequest, options ...RequestOption)
 		signer = req.SigningIdentity
 	}
 
-	if signer == nil {
+	// Signatures collected offline replace the signature of the context user
+	if len(req.Signatures) > 0 && req.SigningIdentity == nil {
+		signer = nil
+	} else if signer == nil {
 		return errors.New("must provide signing user")
 	}
 
-	configTx, err := ioutil.ReadFile(req.ChannelConfig)
+	chConfig, err := ExtractConfigUpdate(req.ChannelConfig)
 	if err != nil {
-		return errors.WithMessage(err, "reading channel config file failed")
+		return err
+	}
+
+	var configSignatures []*common.ConfigSignature
+	if signer != nil {
+		sigCtx := Context{
+			Identity:  signer,
+			Providers: rc.provider,
+		}
+		configSignature, err := resource.CreateConfigSignature(&sigCtx, chConfig)
+		if err != nil {
+			return errors.WithMessage(err, "signing configuration failed")
+		}
+		configSignatures = append(configSignatures, configSignature)
 	}
+	configSignatures = MergeConfigSignatures(configSignatures, req.Signatures)
 
-	chConfig, err := resource.ExtractChannelConfig(configTx)
+	orderer, err := rc.requestOrderer(opts)
 	if err != nil {
-		return errors.WithMessage(err, "extracting channel config failed")
+		return err
 	}
 
-	sigCtx := Context{
-		Identity:  signer,
-		Providers: rc.provider,
+	if len(req.Signatures) > 0 || opts.SystemChannelID != "" {
+		if err := rc.checkConfigSignatures(req.ChannelID, chConfig, configSignatures, orderer, opts.SystemChannelID); err != nil {
+			return err
+		}
 	}
-	configSignature, err := resource.CreateConfigSignature(&sigCtx, chConfig)
+
+	request := api.CreateChannelRequest{
+		Name:       req.ChannelID,
+		Orderer:    orderer,
+		Config:     chConfig,
+		Signatures: configSignatures,
+	}
+
+	_, err = rc.resource.CreateChannel(request)
 	if err != nil {
-		return errors.WithMessage(err, "signing configuration failed")
+		return errors.WithMessage(err, "create channel failed")
 	}
 
-	var configSignatures []*common.ConfigSignature
-	configSignatures = append(configSignatures, configSignature)
+	return nil
+}
 
-	// Figure out orderer configuration
+//requestOrderer returns the orderer given in the request options or, by default, a random orderer from the configuration
+func (rc *Client) requestOrderer(opts Opts) (fab.Orderer, error) {
 	var ordererCfg *core.OrdererConfig
+	var err error
 	if opts.OrdererID != "" {
 		ordererCfg, err = rc.provider.Config().OrdererConfig(opts.OrdererID)
 	} else {
@@ -684,27 +758,14 @@ func (rc *Client) SaveChannel(req SaveChannelRequest, options ...RequestOption)
 
 	// Check if retrieving orderer configuration went ok
 	if err != nil || ordererCfg == nil {
-		return errors.Errorf("failed to retrieve orderer config: %s", err)
-	}
-
-	orderer, err := orderer.New(rc.provider.Config(), orderer.FromOrdererConfig(ordererCfg))
-	if err != nil {
-		return errors.WithMessage(err, "failed to create new orderer from config")
-	}
-
-	request := api.CreateChannelRequest{
-		Name:       req.ChannelID,
-		Orderer:    orderer,
-		Config:     chConfig,
-		Signatures: configSignatures,
+		return nil, errors.Errorf("failed to retrieve orderer config: %s", err)
 	}
 
-	_, err = rc.resource.CreateChannel(request)
+	o, err := orderer.New(rc.provider.Config(), orderer.FromOrdererConfig(ordererCfg))
 	if err != nil {
-		return errors.WithMessage(err, "create channel failed")
+		return nil, errors.WithMessage(err, "failed to create new orderer from config")
 	}
-
-	return nil
+	return o, nil
 }
 
 //prepareRequestOpts prepares rrequest options
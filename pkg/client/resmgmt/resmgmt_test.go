@@ -450,6 +450,13 @@ func TestInstallCC(t *testing.T) {
 	if err == nil {
 		t.Fatalf("Should have failed since install cc returns an error in the client")
 	}
+
+	// Chaincode metadata path without metadata
+	req = InstallCCRequest{Name: "ID", Version: "v0", Path: "path", Package: &api.CCPackage{Type: 1, Code: []byte("code")}, MetadataPath: "invalid"}
+	_, err = rc.InstallCC(req)
+	if err == nil {
+		t.Fatalf("Should have failed since metadata path has no chaincode metadata")
+	}
 }
 
 func TestInstallCCRequiredParameters(t *testing.T) {
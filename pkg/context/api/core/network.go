@@ -37,6 +37,7 @@ type ClientConfig struct {
 	TLS             TLSType
 	TLSCerts        MutualTLSConfig
 	CredentialStore CredentialStoreType
+	EventService    EventServiceConfig
 }
 
 // LoggingType defines the level of logging
@@ -54,6 +55,24 @@ type TLSType struct {
 	Enabled bool
 }
 
+// EventServiceType specifies the type of event service that is used by channel clients
+type EventServiceType string
+
+const (
+	// DeliverEventServiceType uses the Deliver service of the peers (default)
+	DeliverEventServiceType EventServiceType = "deliver"
+	// EventHubEventServiceType uses the event hub of the peers
+	EventHubEventServiceType EventServiceType = "eventhub"
+)
+
+// EventServiceConfig defines the event service used by channel clients.
+// BlockEvents requests full blocks rather than filtered blocks, which is required for
+// block events and chaincode event payloads but only permitted to authorized users
+type EventServiceConfig struct {
+	Type        EventServiceType
+	BlockEvents bool
+}
+
 // CredentialStoreType defines pluggable KV store properties
 type CredentialStoreType struct {
 	Path        string
@@ -72,6 +91,32 @@ type ChannelConfig struct {
 	Peers map[string]PeerChannelConfig
 	// Chaincodes list of services
 	Chaincodes []string
+	// Policies of the channel
+	Policies ChannelPolicies
+}
+
+// ChannelPolicies defines the policies of a channel
+type ChannelPolicies struct {
+	// OrdererSelection selects the orderer transactions are broadcast to
+	OrdererSelection OrdererSelectionPolicy
+}
+
+// OrdererSelectionStrategy specifies how the orderer a transaction is broadcast to is selected
+type OrdererSelectionStrategy string
+
+const (
+	// RandomOrdererSelection tries the orderers in random order (default)
+	RandomOrdererSelection OrdererSelectionStrategy = "random"
+	// RoundRobinOrdererSelection starts with the next orderer on every broadcast
+	RoundRobinOrdererSelection OrdererSelectionStrategy = "roundrobin"
+	// PreferredOrdererSelection tries the orderers of the channel's Orderers list first, in that order
+	PreferredOrdererSelection OrdererSelectionStrategy = "preferred"
+)
+
+// OrdererSelectionPolicy defines how orderers are selected for broadcasts on a channel.
+// Orderers that failed are skipped for the OrdererGreylistExpiry period.
+type OrdererSelectionPolicy struct {
+	Strategy OrdererSelectionStrategy
 }
 
 // PeerChannelConfig defines the peer capabilities
@@ -76,6 +76,8 @@ const (
 	OrdererResponse
 	// DiscoveryGreylistExpiry discovery Greylist expiration period
 	DiscoveryGreylistExpiry
+	// OrdererGreylistExpiry orderer Greylist expiration period
+	OrdererGreylistExpiry
 )
 
 // Providers represents the SDK configured core providers context.
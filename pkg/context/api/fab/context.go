@@ -22,7 +22,8 @@ type ChannelService interface {
 	Config() (ChannelConfig, error)
 	Ledger() (ChannelLedger, error)
 	Transactor() (Transactor, error)
-	EventHub() (EventHub, error) // TODO support new event delivery
+	EventHub() (EventHub, error)
+	EventService() (EventService, error)
 	Membership() (ChannelMembership, error)
 }
 
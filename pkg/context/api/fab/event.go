@@ -23,6 +23,7 @@ type EventHub interface {
 	RegisterChaincodeEvent(ccid string, eventname string, callback func(*ChaincodeEvent)) *ChainCodeCBE
 	UnregisterChaincodeEvent(cbe *ChainCodeCBE)
 	RegisterTxEvent(txnID TransactionID, callback func(TransactionID, pb.TxValidationCode, error))
+	RegisterTxBlockEvent(txnID TransactionID, callback func(TransactionID, pb.TxValidationCode, uint64, error))
 	UnregisterTxEvent(txnID TransactionID)
 	RegisterBlockEvent(callback func(*common.Block))
 	UnregisterBlockEvent(callback func(*common.Block))
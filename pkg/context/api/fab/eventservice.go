@@ -25,13 +25,17 @@ type FilteredBlockEvent struct {
 type TxStatusEvent struct {
 	TxID             string
 	TxValidationCode pb.TxValidationCode
+	BlockNumber      uint64
 }
 
 // CCEvent contains the data for a chaincode event
+// Note that the payload is only available if the event service receives full blocks.
 type CCEvent struct {
 	TxID        string
 	ChaincodeID string
 	EventName   string
+	Payload     []byte
+	BlockNumber uint64
 }
 
 // Registration is a handle that is returned from a successful RegisterXXXEvent.
@@ -104,5 +108,5 @@ type EventClient interface {
 	// RegisterConnectionEvent registers a connection event. The returned
 	// ConnectionEvent channel is called whenever the client clients to
 	// or disconnects from the event server
-	RegisterConnectionEvent() (Registration, chan ConnectionEvent, error)
+	RegisterConnectionEvent() (Registration, chan *ConnectionEvent, error)
 }
@@ -16,8 +16,8 @@ import (
 // HFC sends a block of transactions of endorsed proposals requiring ordering.
 type Orderer interface {
 	URL() string
-	SendBroadcast(envelope *SignedEnvelope) (*common.Status, error)
-	SendDeliver(envelope *SignedEnvelope) (chan *common.Block, chan error, context.CancelFunc)
+	SendBroadcast(ctx context.Context, envelope *SignedEnvelope) (*common.Status, error)
+	SendDeliver(ctx context.Context, envelope *SignedEnvelope) (chan *common.Block, chan error, context.CancelFunc)
 }
 
 // A SignedEnvelope can can be sent to an orderer for broadcasting
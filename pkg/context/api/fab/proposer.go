@@ -7,18 +7,37 @@ SPDX-License-Identifier: Apache-2.0
 package fab
 
 import (
+	"context"
+
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 )
 
 // ProposalProcessor simulates transaction proposal, so that a client can submit the result for ordering.
+// Processing is aborted when the given context is cancelled or its deadline expires.
 type ProposalProcessor interface {
-	ProcessTransactionProposal(ProcessProposalRequest) (*TransactionProposalResponse, error)
+	ProcessTransactionProposal(reqCtx context.Context, request ProcessProposalRequest) (*TransactionProposalResponse, error)
 }
 
 // ProposalSender provides the ability for a transaction proposal to be created and sent.
 type ProposalSender interface {
-	CreateTransactionHeader() (TransactionHeader, error)
-	SendTransactionProposal(*TransactionProposal, []ProposalProcessor) ([]*TransactionProposalResponse, error)
+	CreateTransactionHeader(opts ...TxnHeaderOpt) (TransactionHeader, error)
+	SendTransactionProposal(reqCtx context.Context, proposal *TransactionProposal, targets []ProposalProcessor) ([]*TransactionProposalResponse, error)
+}
+
+// TxnHeaderOptions contains options for creating a Transaction Header.
+type TxnHeaderOptions struct {
+	// Identity creates and signs the transaction instead of the identity of the context.
+	Identity IdentityContext
+}
+
+// TxnHeaderOpt is a Transaction Header option.
+type TxnHeaderOpt func(opts *TxnHeaderOptions)
+
+// WithIdentity sets the identity that creates and signs the transaction.
+func WithIdentity(identity IdentityContext) TxnHeaderOpt {
+	return func(opts *TxnHeaderOptions) {
+		opts.Identity = identity
+	}
 }
 
 // TransactionID provides the identifier of a Fabric transaction proposal.
@@ -48,9 +67,11 @@ type ChaincodeInvokeRequest struct {
 }
 
 // TransactionProposal contains a marashalled transaction proposal.
+// Signer, if set, signs the proposal and the transaction created from it instead of the identity of the context.
 type TransactionProposal struct {
 	TxnID TransactionID
 	*pb.Proposal
+	Signer IdentityContext
 }
 
 // ProcessProposalRequest requests simulation of a proposed transaction from transaction processors.
@@ -17,6 +17,7 @@ type InfraProvider interface {
 	CreateChannelTransactor(ic IdentityContext, cfg ChannelCfg) (Transactor, error)
 	CreateChannelMembership(cfg ChannelCfg) (ChannelMembership, error)
 	CreateEventHub(ic IdentityContext, name string) (EventHub, error)
+	CreateEventService(ic IdentityContext, name string) (EventClient, error)
 	CreatePeerFromConfig(peerCfg *core.NetworkPeer) (Peer, error)
 	CreateOrdererFromConfig(cfg *core.OrdererConfig) (Orderer, error)
 }
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package fab
 
 import (
+	"context"
+
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 )
 
@@ -21,7 +23,7 @@ type TransactionRequest struct {
 // TODO: CreateTransaction should be refactored as it is actually a factory method.
 type Sender interface {
 	CreateTransaction(request TransactionRequest) (*Transaction, error)
-	SendTransaction(tx *Transaction) (*TransactionResponse, error)
+	SendTransaction(reqCtx context.Context, tx *Transaction) (*TransactionResponse, error)
 }
 
 // The Transaction object created from an endorsed proposal.
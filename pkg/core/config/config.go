@@ -458,6 +458,8 @@ func (c *Config) TimeoutOrDefault(conn core.TimeoutType) time.Duration {
 		timeout = c.configViper.GetDuration("client.orderer.timeout.connection")
 	case core.OrdererResponse:
 		timeout = c.configViper.GetDuration("client.orderer.timeout.response")
+	case core.OrdererGreylistExpiry:
+		timeout = c.configViper.GetDuration("client.orderer.timeout.greylistExpiry")
 
 	}
 	if timeout == 0 {
@@ -173,6 +173,20 @@ func TestCAConfig(t *testing.T) {
 	if c == nil {
 		t.Fatal("Received empty client when fetching Client info")
 	}
+	if c.EventService.Type != api.DeliverEventServiceType {
+		t.Fatalf("Incorrect event service type: %s", c.EventService.Type)
+	}
+	if c.EventService.BlockEvents {
+		t.Fatal("Expected filtered block events by default")
+	}
+
+	chConfig, err := configImpl.ChannelConfig("mychannel")
+	if err != nil || chConfig == nil {
+		t.Fatalf("Received error when fetching channel config, error is %s", err)
+	}
+	if chConfig.Policies.OrdererSelection.Strategy != api.RandomOrdererSelection {
+		t.Fatalf("Incorrect orderer selection strategy: %s", chConfig.Policies.OrdererSelection.Strategy)
+	}
 
 	// testing empty OrgMSP
 	mspID, err = configImpl.MspID("dummyorg1")
@@ -379,6 +393,7 @@ func TestTimeouts(t *testing.T) {
 	configImpl.configViper.Set("client.peer.timeout.queryResponse", "7h")
 	configImpl.configViper.Set("client.peer.timeout.executeTxResponse", "8h")
 	configImpl.configViper.Set("client.orderer.timeout.response", "6s")
+	configImpl.configViper.Set("client.orderer.timeout.greylistExpiry", "3m")
 
 	t1 := configImpl.TimeoutOrDefault(api.Endorser)
 	if t1 != time.Second*2 {
@@ -408,6 +423,10 @@ func TestTimeouts(t *testing.T) {
 	if t1 != time.Second*6 {
 		t.Fatalf("Timeout not read correctly. Got: %s", t1)
 	}
+	t1 = configImpl.TimeoutOrDefault(api.OrdererGreylistExpiry)
+	if t1 != time.Minute*3 {
+		t.Fatalf("Timeout not read correctly. Got: %s", t1)
+	}
 
 	// Test default
 	configImpl.configViper.Set("client.orderer.timeout.connection", "")
@@ -62,6 +62,13 @@ var DefaultRetryableCodes = map[status.Group][]status.Code{
 	},
 }
 
+// ConflictTxValidationCodes are the transaction validation codes caused by concurrent
+// transactions updating the same keys. Re-endorsing the transaction usually resolves these.
+var ConflictTxValidationCodes = []pb.TxValidationCode{
+	pb.TxValidationCode_MVCC_READ_CONFLICT,
+	pb.TxValidationCode_PHANTOM_READ_CONFLICT,
+}
+
 // ChannelClientRetryableCodes are the suggested codes that should be treated as
 // transient by fabric-sdk-go/api/apitxn.ChannelClient
 var ChannelClientRetryableCodes = map[status.Group][]status.Code{
@@ -8,9 +8,11 @@ SPDX-License-Identifier: Apache-2.0
 package retry
 
 import (
+	reqContext "context"
 	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/errors/status"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 )
 
 // Opts defines the retry parameters
@@ -29,6 +31,13 @@ type Opts struct {
 	// RetryableCodes defines the status codes, mapped by group, returned by fabric-sdk-go
 	// that warrant a retry. This will default to retry.DefaultRetryableCodes.
 	RetryableCodes map[status.Group][]status.Code
+	// RetryableTxValidationCodes defines the transaction validation codes, received when the
+	// transaction is committed, that warrant re-endorsing and re-submitting the transaction
+	// with a new transaction ID. If set, these replace the status.EventServerStatus codes
+	// in RetryableCodes, which by default are DUPLICATE_TXID, ENDORSEMENT_POLICY_FAILURE,
+	// MVCC_READ_CONFLICT and PHANTOM_READ_CONFLICT. Use it to narrow the default codes,
+	// e.g. to retry.ConflictTxValidationCodes, or to retry other validation codes.
+	RetryableTxValidationCodes []pb.TxValidationCode
 }
 
 // Handler retry handler interface decides whether a retry is required for the given
@@ -37,6 +46,13 @@ type Handler interface {
 	Required(err error) bool
 }
 
+// ContextHandler is a retry Handler whose backoff is cut short, with no retry required,
+// once the context of the request is done. The handlers of this package implement it.
+type ContextHandler interface {
+	Handler
+	RequiredWithContext(ctx reqContext.Context, err error) bool
+}
+
 // impl retry Handler implementation
 type impl struct {
 	opts    Opts
@@ -48,9 +64,29 @@ func New(opts Opts) Handler {
 	if len(opts.RetryableCodes) == 0 {
 		opts.RetryableCodes = DefaultRetryableCodes
 	}
+	if len(opts.RetryableTxValidationCodes) > 0 {
+		opts.RetryableCodes = withTxValidationCodes(opts.RetryableCodes, opts.RetryableTxValidationCodes)
+	}
 	return &impl{opts: opts}
 }
 
+// withTxValidationCodes returns a copy of the retryable codes with the event server
+// codes replaced by the given transaction validation codes
+func withTxValidationCodes(retryableCodes map[status.Group][]status.Code, txValidationCodes []pb.TxValidationCode) map[status.Group][]status.Code {
+	codes := make(map[status.Group][]status.Code, len(retryableCodes)+1)
+	for group, c := range retryableCodes {
+		codes[group] = c
+	}
+
+	eventCodes := make([]status.Code, len(txValidationCodes))
+	for i, code := range txValidationCodes {
+		eventCodes[i] = status.Code(code)
+	}
+	codes[status.EventServerStatus] = eventCodes
+
+	return codes
+}
+
 // WithDefaults new retry Handler with default opts
 func WithDefaults() Handler {
 	return &impl{opts: DefaultOpts}
@@ -66,18 +102,30 @@ func WithAttempts(attempts int) Handler {
 // Required determines if retry is required for the given error
 // Note: backoffs are implemented behind this interface
 func (i *impl) Required(err error) bool {
+	return i.RequiredWithContext(reqContext.Background(), err)
+}
+
+// RequiredWithContext determines if retry is required for the given error,
+// unless the context is done before the end of the backoff
+func (i *impl) RequiredWithContext(ctx reqContext.Context, err error) bool {
 	if i.retries == i.opts.Attempts {
 		return false
 	}
 
 	s, ok := status.FromError(err)
-	if ok && i.isRetryable(s.Group, s.Code) {
-		time.Sleep(i.backoffPeriod())
-		i.retries++
-		return true
+	if !ok || !i.isRetryable(s.Group, s.Code) {
+		return false
 	}
 
-	return false
+	backoff := time.NewTimer(i.backoffPeriod())
+	defer backoff.Stop()
+	select {
+	case <-backoff.C:
+	case <-ctx.Done():
+		return false
+	}
+	i.retries++
+	return true
 }
 
 // backoffPeriod calculates the backoff duration based on the provided opts
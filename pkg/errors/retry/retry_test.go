@@ -7,12 +7,14 @@ SPDX-License-Identifier: Apache-2.0
 package retry
 
 import (
+	reqContext "context"
 	"fmt"
 	"testing"
 	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/errors/status"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -63,3 +65,40 @@ func TestBackoffPeriod(t *testing.T) {
 	i.retries = 3
 	assert.Equal(t, testMaxBackoff, i.backoffPeriod(), "Expected max backoff")
 }
+
+func TestRetryTxValidationCodes(t *testing.T) {
+	mvccErr := status.New(status.EventServerStatus, int32(pb.TxValidationCode_MVCC_READ_CONFLICT), "", nil)
+	policyErr := status.New(status.EventServerStatus, int32(pb.TxValidationCode_ENDORSEMENT_POLICY_FAILURE), "", nil)
+	endorserErr := status.New(status.EndorserClientStatus, status.EndorsementMismatch.ToInt32(), "", nil)
+
+	r := New(Opts{
+		Attempts:                   3,
+		BackoffFactor:              1,
+		InitialBackoff:             1 * time.Millisecond,
+		MaxBackoff:                 1 * time.Second,
+		RetryableTxValidationCodes: ConflictTxValidationCodes,
+	})
+	assert.True(t, r.Required(mvccErr), "Expected retry to be required on MVCC read conflict")
+	assert.False(t, r.Required(policyErr), "Expected retry to not be required on validation code that wasn't selected")
+	assert.True(t, r.Required(endorserErr), "Expected default retryable codes of other groups to be kept")
+	assert.Len(t, DefaultRetryableCodes[status.EventServerStatus], 4, "Expected default retryable codes to be unchanged")
+}
+
+func TestRetryWithContext(t *testing.T) {
+	transientErr := status.New(status.EndorserClientStatus, status.EndorsementMismatch.ToInt32(), "", nil)
+
+	r := New(Opts{
+		Attempts:       3,
+		BackoffFactor:  1,
+		InitialBackoff: 1 * time.Minute,
+		MaxBackoff:     1 * time.Minute,
+	})
+
+	// The backoff ends with the context deadline, without a retry
+	ctx, cancel := reqContext.WithTimeout(reqContext.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	assert.False(t, r.(ContextHandler).RequiredWithContext(ctx, transientErr), "Expected retry to not be required once the context is done")
+	assert.True(t, time.Since(start) < time.Minute, "Expected backoff to end with the context")
+	assert.Equal(t, 0, r.(*impl).retries, "Expected no retry to be counted")
+}
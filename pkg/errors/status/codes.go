@@ -41,6 +41,9 @@ const (
 
 	// MultipleErrors multiple errors occurred
 	MultipleErrors Code = 7
+
+	// EndorsementPolicyFailure is returned when the endorsements received by the SDK do not satisfy the endorsement policy
+	EndorsementPolicyFailure Code = 8
 )
 
 // CodeName maps the codes in this packages to human-readable strings
@@ -53,6 +56,7 @@ var CodeName = map[int32]string{
 	5: "TIMEOUT",
 	6: "NO_PEERS_FOUND",
 	7: "MULTIPLE_ERRORS",
+	8: "ENDORSEMENT_POLICY_FAILURE",
 }
 
 // ToInt32 cast to int32
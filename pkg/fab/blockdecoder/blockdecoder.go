@@ -0,0 +1,258 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package blockdecoder decodes the blocks of a channel ledger into a typed model of the block's
+// transactions: their headers, creators, chaincode invocations, endorsers, read/write sets,
+// chaincode events and validation codes, and the channel configuration of config blocks.
+package blockdecoder
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/chconfig"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
+	ledgerutil "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	protos_utils "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+// Block is a decoded block.
+type Block struct {
+	Number       uint64
+	DataHash     []byte
+	PreviousHash []byte
+	Transactions []*Transaction
+	// Config is the channel configuration of a config block, nil for other blocks
+	Config fab.ChannelCfg
+}
+
+// Transaction is a decoded transaction of a block.
+type Transaction struct {
+	TxID      string
+	ChannelID string
+	Type      common.HeaderType
+	Timestamp time.Time
+	Creator   *Identity
+	// Actions are the chaincode actions of an endorser transaction
+	Actions []*ChaincodeAction
+	// Validated is false if the block has no validation flags, as do the blocks delivered by the orderer
+	Validated      bool
+	ValidationCode pb.TxValidationCode
+	// DecodeErr is the error of decoding a malformed transaction, such as a NIL_ENVELOPE or BAD_PAYLOAD
+	// transaction, in which case only the fields decoded before the error and the validation code are set
+	DecodeErr error
+}
+
+// IsValid returns whether the transaction was validated by the committing peer and found valid.
+func (tx *Transaction) IsValid() bool {
+	return tx.Validated && tx.ValidationCode == pb.TxValidationCode_VALID
+}
+
+// ChaincodeAction is a decoded chaincode action of an endorser transaction.
+type ChaincodeAction struct {
+	// ChaincodeSpec is the chaincode invocation of the proposal
+	ChaincodeSpec *pb.ChaincodeInvocationSpec
+	Endorsers     []*Identity
+	// RWSet is the read/write set of the invocation, which includes its chaincode event
+	RWSet          *txn.TxRWSet
+	ChaincodeEvent *pb.ChaincodeEvent
+}
+
+// Identity is a decoded serialized identity.
+type Identity struct {
+	MSPID string
+	// Cert is the PEM encoded certificate of the identity
+	Cert []byte
+}
+
+// Decode decodes the block. Transactions that the committing peer flagged as invalid because they are
+// malformed are decoded with their DecodeErr, whereas the decoding fails for other malformed transactions.
+func Decode(block *common.Block) (*Block, error) {
+	if block == nil || block.Header == nil || block.Data == nil {
+		return nil, errors.New("block, block header and block data are required")
+	}
+
+	decoded := &Block{
+		Number:       block.Header.Number,
+		DataHash:     block.Header.DataHash,
+		PreviousHash: block.Header.PreviousHash,
+	}
+
+	var txFilter ledgerutil.TxValidationFlags
+	if block.Metadata != nil && len(block.Metadata.Metadata) > int(common.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		txFilter = ledgerutil.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	}
+
+	for i, data := range block.Data.Data {
+		tx, config, err := decodeData(data)
+		if tx == nil {
+			tx = &Transaction{}
+		}
+		tx.DecodeErr = err
+		if i < len(txFilter) {
+			tx.Validated = true
+			tx.ValidationCode = txFilter.Flag(i)
+		}
+		// Committed blocks may have malformed transactions, which the committing peer flags as invalid,
+		// whereas a valid transaction, or a transaction of a block without validation flags, must be decodable
+		if err != nil && (!tx.Validated || tx.IsValid()) {
+			return nil, errors.WithMessage(err, fmt.Sprintf("decoding transaction %d of block %d failed", i, block.Header.Number))
+		}
+		if config != nil {
+			decoded.Config = config
+		}
+		decoded.Transactions = append(decoded.Transactions, tx)
+	}
+
+	return decoded, nil
+}
+
+// DecodeProcessedTransaction decodes a transaction returned by the ledger with its validation code.
+func DecodeProcessedTransaction(processedTx *pb.ProcessedTransaction) (*Transaction, error) {
+	if processedTx == nil || processedTx.TransactionEnvelope == nil {
+		return nil, errors.New("transaction envelope is required")
+	}
+
+	tx, _, err := decodeEnvelope(processedTx.TransactionEnvelope)
+	if err != nil {
+		return nil, err
+	}
+	tx.Validated = true
+	tx.ValidationCode = pb.TxValidationCode(processedTx.ValidationCode)
+	return tx, nil
+}
+
+// decodeData decodes the transaction of the envelope bytes of the block data
+func decodeData(data []byte) (*Transaction, fab.ChannelCfg, error) {
+	env, err := protos_utils.GetEnvelopeFromBlock(data)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "unmarshal of envelope failed")
+	}
+	return decodeEnvelope(env)
+}
+
+// decodeEnvelope decodes the transaction of the envelope and the channel configuration of config transactions.
+// The transaction is returned with the error if its header was decoded
+func decodeEnvelope(env *common.Envelope) (*Transaction, fab.ChannelCfg, error) {
+	payload, err := protos_utils.GetPayload(env)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "unmarshal of payload failed")
+	}
+	if payload.Header == nil {
+		return nil, nil, errors.New("payload header is missing")
+	}
+
+	channelHeader, err := protos_utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "unmarshal of channel header failed")
+	}
+
+	tx := &Transaction{
+		TxID:      channelHeader.TxId,
+		ChannelID: channelHeader.ChannelId,
+		Type:      common.HeaderType(channelHeader.Type),
+	}
+	if channelHeader.Timestamp != nil {
+		tx.Timestamp = time.Unix(channelHeader.Timestamp.Seconds, int64(channelHeader.Timestamp.Nanos)).UTC()
+	}
+
+	if len(payload.Header.SignatureHeader) > 0 {
+		signatureHeader, err := protos_utils.GetSignatureHeader(payload.Header.SignatureHeader)
+		if err != nil {
+			return tx, nil, errors.Wrap(err, "unmarshal of signature header failed")
+		}
+		if tx.Creator, err = decodeIdentity(signatureHeader.Creator); err != nil {
+			return tx, nil, errors.WithMessage(err, "decoding creator failed")
+		}
+	}
+
+	switch tx.Type {
+	case common.HeaderType_ENDORSER_TRANSACTION:
+		if tx.Actions, err = decodeActions(payload.Data); err != nil {
+			return tx, nil, err
+		}
+	case common.HeaderType_CONFIG:
+		configEnvelope := &common.ConfigEnvelope{}
+		if err := proto.Unmarshal(payload.Data, configEnvelope); err != nil {
+			return tx, nil, errors.Wrap(err, "unmarshal of config envelope failed")
+		}
+		config, err := chconfig.ExtractChannelCfg(tx.ChannelID, configEnvelope.Config)
+		if err != nil {
+			return tx, nil, errors.WithMessage(err, "extracting channel config failed")
+		}
+		return tx, config, nil
+	}
+
+	return tx, nil, nil
+}
+
+func decodeActions(data []byte) ([]*ChaincodeAction, error) {
+	transaction, err := protos_utils.GetTransaction(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal of transaction failed")
+	}
+
+	var actions []*ChaincodeAction
+	for i, action := range transaction.Actions {
+		decoded, err := decodeAction(action)
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("decoding action %d failed", i))
+		}
+		actions = append(actions, decoded)
+	}
+	return actions, nil
+}
+
+func decodeAction(action *pb.TransactionAction) (*ChaincodeAction, error) {
+	actionPayload, err := protos_utils.GetChaincodeActionPayload(action.Payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal of chaincode action payload failed")
+	}
+	if actionPayload.Action == nil {
+		return nil, errors.New("chaincode endorsed action is missing")
+	}
+
+	decoded := &ChaincodeAction{}
+
+	proposalPayload, err := protos_utils.GetChaincodeProposalPayload(actionPayload.ChaincodeProposalPayload)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal of chaincode proposal payload failed")
+	}
+	decoded.ChaincodeSpec = &pb.ChaincodeInvocationSpec{}
+	if err := proto.Unmarshal(proposalPayload.Input, decoded.ChaincodeSpec); err != nil {
+		return nil, errors.Wrap(err, "unmarshal of chaincode invocation spec failed")
+	}
+
+	for _, endorsement := range actionPayload.Action.Endorsements {
+		endorser, err := decodeIdentity(endorsement.Endorser)
+		if err != nil {
+			return nil, errors.WithMessage(err, "decoding endorser failed")
+		}
+		decoded.Endorsers = append(decoded.Endorsers, endorser)
+	}
+
+	if decoded.RWSet, err = txn.DecodeProposalResponsePayload(actionPayload.Action.ProposalResponsePayload); err != nil {
+		return nil, err
+	}
+	decoded.ChaincodeEvent = decoded.RWSet.ChaincodeEvent
+
+	return decoded, nil
+}
+
+func decodeIdentity(serializedID []byte) (*Identity, error) {
+	identity := &msp.SerializedIdentity{}
+	if err := proto.Unmarshal(serializedID, identity); err != nil {
+		return nil, errors.Wrap(err, "unmarshal of serialized identity failed")
+	}
+	return &Identity{MSPID: identity.Mspid, Cert: identity.IdBytes}, nil
+}
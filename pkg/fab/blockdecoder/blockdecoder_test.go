@@ -0,0 +1,272 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blockdecoder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/chconfig"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	ledgerutil "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+var testTime = time.Date(2018, 3, 1, 10, 30, 0, 500, time.UTC)
+
+func marshalOrPanic(msg proto.Message) []byte {
+	bytes, err := proto.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return bytes
+}
+
+func serializedIdentity(mspID string, cert string) []byte {
+	return marshalOrPanic(&msp.SerializedIdentity{Mspid: mspID, IdBytes: []byte(cert)})
+}
+
+// newTestEnvelope creates the envelope of an endorser transaction that writes key to value
+func newTestEnvelope(txID string, key string, value string) []byte {
+	txRwSet := &rwsetutil.TxRwSet{
+		NsRwSets: []*rwsetutil.NsRwSet{
+			{NameSpace: "example_cc", KvRwSet: &kvrwset.KVRWSet{
+				Reads:  []*kvrwset.KVRead{{Key: key, Version: &kvrwset.Version{BlockNum: 1}}},
+				Writes: []*kvrwset.KVWrite{{Key: key, Value: []byte(value)}},
+			}},
+		},
+	}
+	results, err := txRwSet.ToProtoBytes()
+	if err != nil {
+		panic(err)
+	}
+
+	action := &pb.ChaincodeAction{
+		Results: results,
+		Events:  marshalOrPanic(&pb.ChaincodeEvent{ChaincodeId: "example_cc", TxId: txID, EventName: "moved"}),
+	}
+	spec := &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			ChaincodeId: &pb.ChaincodeID{Name: "example_cc"},
+			Input:       &pb.ChaincodeInput{Args: [][]byte{[]byte("move"), []byte(key), []byte(value)}},
+		},
+	}
+	actionPayload := &pb.ChaincodeActionPayload{
+		ChaincodeProposalPayload: marshalOrPanic(&pb.ChaincodeProposalPayload{Input: marshalOrPanic(spec)}),
+		Action: &pb.ChaincodeEndorsedAction{
+			ProposalResponsePayload: marshalOrPanic(&pb.ProposalResponsePayload{Extension: marshalOrPanic(action)}),
+			Endorsements: []*pb.Endorsement{
+				{Endorser: serializedIdentity("Org1MSP", "peer0.org1"), Signature: []byte("signature")},
+				{Endorser: serializedIdentity("Org2MSP", "peer0.org2"), Signature: []byte("signature")},
+			},
+		},
+	}
+	tx := &pb.Transaction{Actions: []*pb.TransactionAction{{Payload: marshalOrPanic(actionPayload)}}}
+
+	channelHeader := &common.ChannelHeader{
+		Type:      int32(common.HeaderType_ENDORSER_TRANSACTION),
+		ChannelId: "mychannel",
+		TxId:      txID,
+		Timestamp: &timestamp.Timestamp{Seconds: testTime.Unix(), Nanos: int32(testTime.Nanosecond())},
+	}
+	payload := &common.Payload{
+		Header: &common.Header{
+			ChannelHeader:   marshalOrPanic(channelHeader),
+			SignatureHeader: marshalOrPanic(&common.SignatureHeader{Creator: serializedIdentity("Org1MSP", "user1")}),
+		},
+		Data: marshalOrPanic(tx),
+	}
+	return marshalOrPanic(&common.Envelope{Payload: marshalOrPanic(payload), Signature: []byte("signature")})
+}
+
+func TestDecode(t *testing.T) {
+	block := &common.Block{
+		Header: &common.BlockHeader{Number: 5, PreviousHash: []byte("previous"), DataHash: []byte("data")},
+		Data: &common.BlockData{
+			Data: [][]byte{newTestEnvelope("tx1", "a", "10"), newTestEnvelope("tx2", "b", "20")},
+		},
+		Metadata: &common.BlockMetadata{
+			Metadata: [][]byte{{}, {}, ledgerutil.TxValidationFlags{uint8(pb.TxValidationCode_VALID), uint8(pb.TxValidationCode_MVCC_READ_CONFLICT)}, {}},
+		},
+	}
+
+	decoded, err := Decode(block)
+	if err != nil {
+		t.Fatalf("Failed to decode block: %s", err)
+	}
+	assert.Equal(t, uint64(5), decoded.Number)
+	assert.Equal(t, []byte("previous"), decoded.PreviousHash)
+	assert.Equal(t, []byte("data"), decoded.DataHash)
+	assert.Nil(t, decoded.Config)
+	if !assert.Len(t, decoded.Transactions, 2) {
+		return
+	}
+
+	tx := decoded.Transactions[0]
+	assert.Equal(t, "tx1", tx.TxID)
+	assert.Equal(t, "mychannel", tx.ChannelID)
+	assert.Equal(t, common.HeaderType_ENDORSER_TRANSACTION, tx.Type)
+	assert.True(t, testTime.Equal(tx.Timestamp), "unexpected timestamp %s", tx.Timestamp)
+	assert.Equal(t, &Identity{MSPID: "Org1MSP", Cert: []byte("user1")}, tx.Creator)
+	assert.True(t, tx.IsValid())
+	assert.Equal(t, pb.TxValidationCode_MVCC_READ_CONFLICT, decoded.Transactions[1].ValidationCode)
+	assert.False(t, decoded.Transactions[1].IsValid())
+
+	if !assert.Len(t, tx.Actions, 1) {
+		return
+	}
+	action := tx.Actions[0]
+	assert.Equal(t, "example_cc", action.ChaincodeSpec.ChaincodeSpec.ChaincodeId.Name)
+	assert.Equal(t, [][]byte{[]byte("move"), []byte("a"), []byte("10")}, action.ChaincodeSpec.ChaincodeSpec.Input.Args)
+	assert.Equal(t, []*Identity{{MSPID: "Org1MSP", Cert: []byte("peer0.org1")}, {MSPID: "Org2MSP", Cert: []byte("peer0.org2")}}, action.Endorsers)
+	if assert.NotNil(t, action.ChaincodeEvent) {
+		assert.Equal(t, "moved", action.ChaincodeEvent.EventName)
+	}
+	ns := action.RWSet.NsRWSet("example_cc")
+	if assert.NotNil(t, ns) && assert.Len(t, ns.Writes, 1) {
+		assert.Equal(t, "a", ns.Writes[0].Key)
+		assert.Equal(t, []byte("10"), ns.Writes[0].Value)
+	}
+}
+
+func TestDecodeWithoutValidationFlags(t *testing.T) {
+	// Blocks delivered by the orderer are not validated
+	block := &common.Block{
+		Header: &common.BlockHeader{Number: 1},
+		Data:   &common.BlockData{Data: [][]byte{newTestEnvelope("tx1", "a", "10")}},
+	}
+
+	decoded, err := Decode(block)
+	if err != nil {
+		t.Fatalf("Failed to decode block: %s", err)
+	}
+	if assert.Len(t, decoded.Transactions, 1) {
+		assert.False(t, decoded.Transactions[0].Validated)
+		assert.False(t, decoded.Transactions[0].IsValid())
+	}
+}
+
+func TestDecodeConfigBlock(t *testing.T) {
+	builder := &mocks.MockConfigBlockBuilder{
+		MockConfigGroupBuilder: mocks.MockConfigGroupBuilder{
+			ModPolicy:      "Admins",
+			MSPNames:       []string{"Org1MSP", "Org2MSP"},
+			OrdererAddress: "localhost:7054",
+			RootCA:         "root",
+		},
+		Index: 3,
+	}
+
+	decoded, err := Decode(builder.Build())
+	if err != nil {
+		t.Fatalf("Failed to decode config block: %s", err)
+	}
+	assert.Equal(t, uint64(3), decoded.Number)
+	if assert.Len(t, decoded.Transactions, 1) {
+		assert.Equal(t, common.HeaderType_CONFIG, decoded.Transactions[0].Type)
+		assert.Empty(t, decoded.Transactions[0].Actions)
+	}
+	if decoded.Config == nil {
+		t.Fatal("expected channel config of config block")
+	}
+	assert.Equal(t, []string{"localhost:7054"}, decoded.Config.Orderers())
+	mspIDs, err := chconfig.MSPIDs(decoded.Config)
+	if err != nil {
+		t.Fatalf("Failed to get MSP IDs: %s", err)
+	}
+	assert.Contains(t, mspIDs, "Org1MSP")
+	assert.Contains(t, mspIDs, "Org2MSP")
+}
+
+func TestDecodeProcessedTransaction(t *testing.T) {
+	env := &common.Envelope{}
+	if err := proto.Unmarshal(newTestEnvelope("tx1", "a", "10"), env); err != nil {
+		t.Fatalf("Failed to unmarshal envelope: %s", err)
+	}
+
+	tx, err := DecodeProcessedTransaction(&pb.ProcessedTransaction{TransactionEnvelope: env, ValidationCode: int32(pb.TxValidationCode_ENDORSEMENT_POLICY_FAILURE)})
+	if err != nil {
+		t.Fatalf("Failed to decode transaction: %s", err)
+	}
+	assert.Equal(t, "tx1", tx.TxID)
+	assert.True(t, tx.Validated)
+	assert.Equal(t, pb.TxValidationCode_ENDORSEMENT_POLICY_FAILURE, tx.ValidationCode)
+	assert.Len(t, tx.Actions, 1)
+
+	_, err = DecodeProcessedTransaction(&pb.ProcessedTransaction{})
+	assert.Error(t, err, "expected error for missing envelope")
+}
+
+func TestDecodeErrors(t *testing.T) {
+	_, err := Decode(nil)
+	assert.Error(t, err, "expected error for nil block")
+
+	_, err = Decode(&common.Block{Header: &common.BlockHeader{}, Data: &common.BlockData{Data: [][]byte{[]byte("invalid")}}})
+	assert.Error(t, err, "expected error for invalid envelope")
+
+	// Invalid transaction of a valid envelope
+	payload := &common.Payload{
+		Header: &common.Header{ChannelHeader: marshalOrPanic(&common.ChannelHeader{Type: int32(common.HeaderType_ENDORSER_TRANSACTION), TxId: "tx1"})},
+		Data:   []byte("invalid"),
+	}
+	env := marshalOrPanic(&common.Envelope{Payload: marshalOrPanic(payload)})
+	_, err = Decode(&common.Block{Header: &common.BlockHeader{Number: 7}, Data: &common.BlockData{Data: [][]byte{env}}})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "transaction 0 of block 7")
+	}
+}
+
+func TestDecodeMalformedTransactions(t *testing.T) {
+	// A payload whose header can be decoded and an envelope that can't
+	payload := &common.Payload{
+		Header: &common.Header{ChannelHeader: marshalOrPanic(&common.ChannelHeader{Type: int32(common.HeaderType_ENDORSER_TRANSACTION), TxId: "tx2"})},
+		Data:   []byte("invalid"),
+	}
+	block := &common.Block{
+		Header: &common.BlockHeader{Number: 7},
+		Data: &common.BlockData{Data: [][]byte{
+			newTestEnvelope("tx1", "a", "10"),
+			marshalOrPanic(&common.Envelope{Payload: marshalOrPanic(payload)}),
+			[]byte("invalid"),
+		}},
+		Metadata: &common.BlockMetadata{
+			Metadata: [][]byte{{}, {}, ledgerutil.TxValidationFlags{uint8(pb.TxValidationCode_VALID), uint8(pb.TxValidationCode_BAD_PAYLOAD), uint8(pb.TxValidationCode_NIL_ENVELOPE)}, {}},
+		},
+	}
+
+	decoded, err := Decode(block)
+	if err != nil {
+		t.Fatalf("Failed to decode block: %s", err)
+	}
+	if !assert.Len(t, decoded.Transactions, 3) {
+		return
+	}
+	assert.Nil(t, decoded.Transactions[0].DecodeErr)
+	assert.True(t, decoded.Transactions[0].IsValid())
+
+	assert.Error(t, decoded.Transactions[1].DecodeErr, "expected decode error for bad payload")
+	assert.Equal(t, "tx2", decoded.Transactions[1].TxID)
+	assert.Equal(t, pb.TxValidationCode_BAD_PAYLOAD, decoded.Transactions[1].ValidationCode)
+
+	assert.Error(t, decoded.Transactions[2].DecodeErr, "expected decode error for nil envelope")
+	assert.Equal(t, pb.TxValidationCode_NIL_ENVELOPE, decoded.Transactions[2].ValidationCode)
+
+	// Malformed transactions flagged as valid fail the decoding
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = ledgerutil.TxValidationFlags{uint8(pb.TxValidationCode_VALID), uint8(pb.TxValidationCode_VALID), uint8(pb.TxValidationCode_NIL_ENVELOPE)}
+	_, err = Decode(block)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "transaction 1 of block 7")
+	}
+}
@@ -7,16 +7,13 @@ SPDX-License-Identifier: Apache-2.0
 package gopackager
 
 import (
-	"archive/tar"
-	"bytes"
-	"compress/gzip"
 	"go/build"
-	"io"
 	"os"
 	"path"
 	"path/filepath"
-	"time"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/ccpackager/metadata"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/ccpackager/tarball"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource/api"
 	"github.com/hyperledger/fabric-sdk-go/pkg/logging"
 	"github.com/pkg/errors"
@@ -24,12 +21,6 @@ import (
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 )
 
-// Descriptor ...
-type Descriptor struct {
-	name string
-	fqp  string
-}
-
 // A list of file extensions that should be packaged into the .tar.gz.
 // Files with all other file extenstions will be excluded to minimize the size
 // of the install payload.
@@ -37,7 +28,8 @@ var keep = []string{".go", ".c", ".h"}
 
 var logger = logging.NewLogger("fabric_sdk_go")
 
-// NewCCPackage creates new go lang chaincode package
+// NewCCPackage creates new go lang chaincode package. The chaincode metadata in the
+// META-INF directory of the chaincode, such as CouchDB indexes, is packaged too
 func NewCCPackage(chaincodePath string, goPath string) (*api.CCPackage, error) {
 
 	if chaincodePath == "" {
@@ -66,7 +58,11 @@ func NewCCPackage(chaincodePath string, goPath string) (*api.CCPackage, error) {
 	if err != nil {
 		return nil, err
 	}
-	tarBytes, err := generateTarGz(descriptors)
+	metadataFiles, err := metadata.Find(projDir)
+	if err != nil {
+		return nil, err
+	}
+	tarBytes, err := tarball.GenerateTarGz(descriptors, metadataFiles)
 	if err != nil {
 		return nil, err
 	}
@@ -84,8 +80,8 @@ func NewCCPackage(chaincodePath string, goPath string) (*api.CCPackage, error) {
 // As a convenience, we also formulate a tar-friendly "name" for each file
 // based on relative position to 'goPath'.
 // -------------------------------------------------------------------------
-func findSource(goPath string, filePath string) ([]*Descriptor, error) {
-	var descriptors []*Descriptor
+func findSource(goPath string, filePath string) ([]*tarball.Descriptor, error) {
+	var descriptors []*tarball.Descriptor
 	err := filepath.Walk(filePath,
 		func(path string, fileInfo os.FileInfo, err error) error {
 			if err != nil {
@@ -96,7 +92,7 @@ func findSource(goPath string, filePath string) ([]*Descriptor, error) {
 				if err != nil {
 					return err
 				}
-				descriptors = append(descriptors, &Descriptor{name: relPath, fqp: path})
+				descriptors = append(descriptors, &tarball.Descriptor{Name: relPath, Fqp: path})
 			}
 			return nil
 
@@ -124,68 +120,6 @@ func isSource(filePath string) bool {
 	return false
 }
 
-// -------------------------------------------------------------------------
-// generateTarGz(descriptors)
-// -------------------------------------------------------------------------
-// creates an .tar.gz stream from the provided descriptor entries
-// -------------------------------------------------------------------------
-func generateTarGz(descriptors []*Descriptor) ([]byte, error) {
-	// set up the gzip writer
-	var codePackage bytes.Buffer
-	gw := gzip.NewWriter(&codePackage)
-	tw := tar.NewWriter(gw)
-	for _, v := range descriptors {
-		logger.Debugf("generateTarGz for %s", v.fqp)
-		err := packEntry(tw, gw, v)
-		if err != nil {
-			closeStream(tw, gw)
-			return nil, errors.Wrap(err, "packEntry failed")
-		}
-	}
-	closeStream(tw, gw)
-	return codePackage.Bytes(), nil
-
-}
-
-func closeStream(tw *tar.Writer, gw *gzip.Writer) {
-	tw.Close()
-	gw.Close()
-}
-
-func packEntry(tw *tar.Writer, gw *gzip.Writer, descriptor *Descriptor) error {
-	file, err := os.Open(descriptor.fqp)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-	if stat, err := file.Stat(); err == nil {
-
-		// now lets create the header as needed for this file within the tarball
-		header := new(tar.Header)
-		header.Name = descriptor.name
-		header.Size = stat.Size()
-		header.Mode = int64(stat.Mode())
-		// Use a deterministic "zero-time" for all date fields
-		header.ModTime = time.Time{}
-		header.AccessTime = time.Time{}
-		header.ChangeTime = time.Time{}
-		// write the header to the tarball archive
-		if err := tw.WriteHeader(header); err != nil {
-			return err
-		}
-
-		// copy the file data to the tarball
-
-		if _, err := io.Copy(tw, file); err != nil {
-			return err
-		}
-		tw.Flush()
-		gw.Flush()
-
-	}
-	return nil
-}
-
 // defaultGoPath returns the system's default GOPATH. If the system
 // has multiple GOPATHs then the first is used.
 func defaultGoPath() string {
@@ -11,8 +11,10 @@ import (
 	"bytes"
 	"compress/gzip"
 	"io"
+	"io/ioutil"
 	"os"
 	"path"
+	"path/filepath"
 	"testing"
 )
 
@@ -59,6 +61,56 @@ func TestNewCCPackage(t *testing.T) {
 
 }
 
+// Test golang ChainCode packaging with CouchDB index metadata
+func TestNewCCPackageWithMetadata(t *testing.T) {
+	goPath, err := ioutil.TempDir("", "gocc")
+	if err != nil {
+		t.Fatalf("error from ioutil.TempDir %v", err)
+	}
+	defer os.RemoveAll(goPath)
+
+	files := map[string]string{
+		"src/example/cc/cc.go": "package main",
+		"src/example/cc/META-INF/statedb/couchdb/indexes/indexOwner.json": `{"index":{"fields":["owner"]},"type":"json"}`,
+	}
+	for name, contents := range files {
+		fqp := filepath.Join(goPath, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(fqp), 0755); err != nil {
+			t.Fatalf("error from os.MkdirAll %v", err)
+		}
+		if err := ioutil.WriteFile(fqp, []byte(contents), 0644); err != nil {
+			t.Fatalf("error from ioutil.WriteFile %v", err)
+		}
+	}
+
+	ccPackage, err := NewCCPackage("example/cc", goPath)
+	if err != nil {
+		t.Fatalf("error from Create %v", err)
+	}
+
+	gzf, err := gzip.NewReader(bytes.NewReader(ccPackage.Code))
+	if err != nil {
+		t.Fatalf("error from gzip.NewReader %v", err)
+	}
+	tarReader := tar.NewReader(gzf)
+	var entries []string
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("error from tarReader.Next() %v", err)
+		}
+		entries = append(entries, header.Name)
+	}
+
+	expected := []string{"src/example/cc/cc.go", "META-INF/statedb/couchdb/indexes/indexOwner.json"}
+	if len(entries) != len(expected) || entries[0] != expected[0] || entries[1] != expected[1] {
+		t.Fatalf("expected package entries %v, got %v", expected, entries)
+	}
+}
+
 // Test Package Go ChainCode
 func TestEmptyCreate(t *testing.T) {
 
@@ -93,18 +145,3 @@ func TestIsSourcePath(t *testing.T) {
 	// reset keep
 	keep = []string{".go", ".c", ".h"}
 }
-
-// Test packEntry and generateTarGz with empty file Descriptor
-func TestEmptyPackEntry(t *testing.T) {
-	emptyDescriptor := &Descriptor{"NewFile", ""}
-	err := packEntry(nil, nil, emptyDescriptor)
-	if err == nil {
-		t.Fatal("packEntry call with empty descriptor info must throw an error")
-	}
-
-	_, err = generateTarGz([]*Descriptor{emptyDescriptor})
-	if err == nil {
-		t.Fatal("generateTarGz call with empty descriptor info must throw an error")
-	}
-
-}
@@ -0,0 +1,75 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package javapackager
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/ccpackager/metadata"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/ccpackager/tarball"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource/api"
+	"github.com/hyperledger/fabric-sdk-go/pkg/logging"
+	"github.com/pkg/errors"
+
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// The peer builds the chaincode, so the build outputs are excluded
+// to minimize the size of the install payload.
+var excludedDirs = []string{"target", "build", "out", ".gradle", ".git"}
+
+var excludedFileTypes = []string{".class"}
+
+// The peer builds the chaincode with Gradle or Maven
+var buildFiles = []string{"build.gradle", "pom.xml"}
+
+// The peer expects the chaincode's files in the src directory of the package
+const srcDir = "src"
+
+var logger = logging.NewLogger("fabric_sdk_go")
+
+// NewCCPackage creates new Java chaincode package from the chaincode project directory,
+// including the chaincode metadata in its META-INF directory
+func NewCCPackage(chaincodePath string) (*api.CCPackage, error) {
+
+	if chaincodePath == "" {
+		return nil, errors.New("chaincode path must be provided")
+	}
+
+	if !hasBuildFile(chaincodePath) {
+		return nil, errors.Errorf("chaincode must have one of the build files %v", buildFiles)
+	}
+
+	logger.Debugf("projDir variable=%s", chaincodePath)
+
+	descriptors, err := tarball.FindSource(chaincodePath, srcDir, excludedDirs, excludedFileTypes)
+	if err != nil {
+		return nil, err
+	}
+	metadataFiles, err := metadata.Find(chaincodePath)
+	if err != nil {
+		return nil, err
+	}
+	tarBytes, err := tarball.GenerateTarGz(descriptors, metadataFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	ccPkg := &api.CCPackage{Type: pb.ChaincodeSpec_JAVA, Code: tarBytes}
+
+	return ccPkg, nil
+}
+
+func hasBuildFile(chaincodePath string) bool {
+	for _, buildFile := range buildFiles {
+		if _, err := os.Stat(filepath.Join(chaincodePath, buildFile)); err == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package javapackager
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// Test Java ChainCode packaging, which requires a Gradle or Maven build file
+func TestNewCCPackage(t *testing.T) {
+	_, err := NewCCPackage("")
+	if err == nil {
+		t.Fatalf("Package Empty Java CC must return an error.")
+	}
+
+	dir, err := ioutil.TempDir("", "javacc")
+	if err != nil {
+		t.Fatalf("error from ioutil.TempDir %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	_, err = NewCCPackage(dir)
+	if err == nil {
+		t.Fatalf("Package Java CC without build file must return an error.")
+	}
+
+	for _, buildFile := range buildFiles {
+		if err := ioutil.WriteFile(filepath.Join(dir, buildFile), []byte(buildFile), 0644); err != nil {
+			t.Fatalf("error from ioutil.WriteFile %v", err)
+		}
+		ccPackage, err := NewCCPackage(dir)
+		if err != nil {
+			t.Fatalf("error from Create %v", err)
+		}
+		assert.Equal(t, pb.ChaincodeSpec_JAVA, ccPackage.Type)
+		assert.NotEmpty(t, ccPackage.Code)
+		os.Remove(filepath.Join(dir, buildFile))
+	}
+}
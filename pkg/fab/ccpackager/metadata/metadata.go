@@ -0,0 +1,199 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package metadata packages the metadata of chaincodes, such as the CouchDB indexes
+// of the chaincode's state database, that the peer expects in the META-INF directory
+// of the chaincode package.
+package metadata
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Dir is the name of the metadata directory of a chaincode
+const Dir = "META-INF"
+
+// CouchDB index definitions are in the indexes directory of the state database,
+// or of a private data collection
+var couchdbIndexDir = regexp.MustCompile("^" + Dir + "/statedb/couchdb/(collections/[^/]+/)?indexes$")
+
+// File is a metadata file of a chaincode
+type File struct {
+	// Name of the file in the chaincode package, relative to the root of the package
+	Name string
+	// Contents of the file
+	Contents []byte
+}
+
+// Find returns the metadata files in the META-INF directory of the given directory.
+// No files are returned if the directory has no META-INF directory. Each file is validated
+func Find(dir string) ([]*File, error) {
+	metadataDir := filepath.Join(dir, Dir)
+	if _, err := os.Stat(metadataDir); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	var files []*File
+	err := filepath.Walk(metadataDir,
+		func(fqp string, fileInfo os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !fileInfo.Mode().IsRegular() {
+				return nil
+			}
+
+			relPath, err := filepath.Rel(dir, fqp)
+			if err != nil {
+				return err
+			}
+			contents, err := ioutil.ReadFile(fqp)
+			if err != nil {
+				return err
+			}
+
+			name := filepath.ToSlash(relPath)
+			if err := Validate(name, contents); err != nil {
+				return err
+			}
+			files = append(files, &File{Name: name, Contents: contents})
+			return nil
+		})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read chaincode metadata")
+	}
+	return files, nil
+}
+
+// Validate validates the metadata file with the given name, relative to the root of the chaincode package.
+// CouchDB index definitions are the only metadata supported by the peer
+func Validate(name string, contents []byte) error {
+	dir := path.Dir(name)
+	if !couchdbIndexDir.MatchString(dir) {
+		return errors.Errorf("metadata file [%s] is not in a supported directory, expected %s/statedb/couchdb/indexes", name, Dir)
+	}
+	if strings.ToLower(path.Ext(name)) != ".json" {
+		return errors.Errorf("index definition [%s] must be a JSON file", name)
+	}
+	return validateIndex(name, contents)
+}
+
+// validateIndex validates the CouchDB index definition, which has the fields to index
+// and optionally the name and the design document of the index
+func validateIndex(name string, contents []byte) error {
+	var index map[string]interface{}
+	if err := json.Unmarshal(contents, &index); err != nil {
+		return errors.Wrapf(err, "index definition [%s] is not valid JSON", name)
+	}
+
+	indexFields, ok := index["index"].(map[string]interface{})
+	if !ok {
+		return errors.Errorf("index definition [%s] must have an index object", name)
+	}
+	fields, ok := indexFields["fields"].([]interface{})
+	if !ok || len(fields) == 0 {
+		return errors.Errorf("index definition [%s] must have index fields", name)
+	}
+
+	for _, key := range []string{"ddoc", "name", "type"} {
+		if value, ok := index[key]; ok {
+			if _, ok := value.(string); !ok {
+				return errors.Errorf("%s of index definition [%s] must be a string", key, name)
+			}
+		}
+	}
+	if indexType, ok := index["type"]; ok && indexType != "json" {
+		return errors.Errorf("type of index definition [%s] must be json", name)
+	}
+	return nil
+}
+
+// WriteFiles writes the metadata files to the chaincode package's tar stream
+func WriteFiles(tw *tar.Writer, files []*File) error {
+	for _, file := range files {
+		header := &tar.Header{
+			Name: file.Name,
+			Size: int64(len(file.Contents)),
+			Mode: 0644,
+			// Use a deterministic "zero-time" for all date fields
+			ModTime:    time.Time{},
+			AccessTime: time.Time{},
+			ChangeTime: time.Time{},
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := tw.Write(file.Contents); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddToPackage adds the metadata files of the given directory to the .tar.gz code of a chaincode package.
+// Metadata files already in the code package are replaced
+func AddToPackage(code []byte, dir string) ([]byte, error) {
+	files, err := Find(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, errors.Errorf("no chaincode metadata found in [%s]", dir)
+	}
+
+	gzr, err := gzip.NewReader(bytes.NewReader(code))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read chaincode package")
+	}
+	tr := tar.NewReader(gzr)
+
+	var codePackage bytes.Buffer
+	gw := gzip.NewWriter(&codePackage)
+	tw := tar.NewWriter(gw)
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read chaincode package")
+		}
+		if strings.HasPrefix(header.Name, Dir+"/") {
+			continue
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return nil, errors.Wrap(err, "failed to write chaincode package")
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return nil, errors.Wrap(err, "failed to write chaincode package")
+		}
+	}
+
+	if err := WriteFiles(tw, files); err != nil {
+		return nil, errors.Wrap(err, "failed to write chaincode metadata")
+	}
+	if err := tw.Close(); err != nil {
+		return nil, errors.Wrap(err, "failed to write chaincode package")
+	}
+	if err := gw.Close(); err != nil {
+		return nil, errors.Wrap(err, "failed to write chaincode package")
+	}
+	return codePackage.Bytes(), nil
+}
@@ -0,0 +1,178 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metadata
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testIndex = `{"index":{"fields":["docType","owner"]},"ddoc":"indexOwnerDoc","name":"indexOwner","type":"json"}`
+
+func TestValidate(t *testing.T) {
+	valid := []string{
+		"META-INF/statedb/couchdb/indexes/indexOwner.json",
+		"META-INF/statedb/couchdb/collections/coll1/indexes/indexOwner.json",
+	}
+	for _, name := range valid {
+		assert.NoError(t, Validate(name, []byte(testIndex)), "expected valid metadata file %s", name)
+	}
+
+	invalid := []struct {
+		name     string
+		contents string
+	}{
+		{"META-INF/statedb/indexes/indexOwner.json", testIndex},
+		{"META-INF/statedb/couchdb/collections/indexes/indexOwner.json", testIndex},
+		{"META-INF/statedb/couchdb/indexes/indexOwner.txt", testIndex},
+		{"META-INF/statedb/couchdb/indexes/indexOwner.json", `{"index":`},
+		{"META-INF/statedb/couchdb/indexes/indexOwner.json", `{"name":"indexOwner"}`},
+		{"META-INF/statedb/couchdb/indexes/indexOwner.json", `{"index":{"fields":[]}}`},
+		{"META-INF/statedb/couchdb/indexes/indexOwner.json", `{"index":{"fields":["owner"]},"name":1}`},
+		{"META-INF/statedb/couchdb/indexes/indexOwner.json", `{"index":{"fields":["owner"]},"type":"text"}`},
+	}
+	for _, file := range invalid {
+		assert.Error(t, Validate(file.name, []byte(file.contents)), "expected invalid metadata file %s: %s", file.name, file.contents)
+	}
+}
+
+func TestFind(t *testing.T) {
+	dir := writeTestMetadata(t, map[string]string{
+		"META-INF/statedb/couchdb/indexes/indexOwner.json":                   testIndex,
+		"META-INF/statedb/couchdb/collections/coll1/indexes/indexOwner.json": testIndex,
+	})
+	defer os.RemoveAll(dir)
+
+	files, err := Find(dir)
+	if err != nil {
+		t.Fatalf("error from Find %v", err)
+	}
+	names := make(map[string]string)
+	for _, file := range files {
+		names[file.Name] = string(file.Contents)
+	}
+	assert.Equal(t, map[string]string{
+		"META-INF/statedb/couchdb/indexes/indexOwner.json":                   testIndex,
+		"META-INF/statedb/couchdb/collections/coll1/indexes/indexOwner.json": testIndex,
+	}, names)
+
+	// No META-INF directory
+	files, err = Find(filepath.Join(dir, "META-INF", "statedb"))
+	assert.NoError(t, err)
+	assert.Empty(t, files)
+
+	// Invalid metadata file
+	invalidDir := writeTestMetadata(t, map[string]string{"META-INF/README.md": "indexes"})
+	defer os.RemoveAll(invalidDir)
+	_, err = Find(invalidDir)
+	assert.Error(t, err, "expected error for unsupported metadata file")
+}
+
+func TestAddToPackage(t *testing.T) {
+	dir := writeTestMetadata(t, map[string]string{
+		"META-INF/statedb/couchdb/indexes/indexOwner.json": testIndex,
+	})
+	defer os.RemoveAll(dir)
+
+	code := newTestPackage(t, map[string]string{
+		"src/chaincode/main.go":                          "package main",
+		"META-INF/statedb/couchdb/indexes/indexOld.json": testIndex,
+	})
+
+	updated, err := AddToPackage(code, dir)
+	if err != nil {
+		t.Fatalf("error from AddToPackage %v", err)
+	}
+	assert.Equal(t, map[string]string{
+		"src/chaincode/main.go":                            "package main",
+		"META-INF/statedb/couchdb/indexes/indexOwner.json": testIndex,
+	}, packageEntries(t, updated))
+
+	// Directory without metadata
+	emptyDir, err := ioutil.TempDir("", "metadata")
+	if err != nil {
+		t.Fatalf("error from ioutil.TempDir %v", err)
+	}
+	defer os.RemoveAll(emptyDir)
+	_, err = AddToPackage(code, emptyDir)
+	assert.Error(t, err, "expected error for directory without metadata")
+
+	// Invalid code package
+	_, err = AddToPackage([]byte("code"), dir)
+	assert.Error(t, err, "expected error for code that is not a .tar.gz")
+}
+
+func writeTestMetadata(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "metadata")
+	if err != nil {
+		t.Fatalf("error from ioutil.TempDir %v", err)
+	}
+	for name, contents := range files {
+		fqp := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(fqp), 0755); err != nil {
+			t.Fatalf("error from os.MkdirAll %v", err)
+		}
+		if err := ioutil.WriteFile(fqp, []byte(contents), 0644); err != nil {
+			t.Fatalf("error from ioutil.WriteFile %v", err)
+		}
+	}
+	return dir
+}
+
+func newTestPackage(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for name, contents := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Size: int64(len(contents)), Mode: 0644}); err != nil {
+			t.Fatalf("error from tw.WriteHeader %v", err)
+		}
+		if _, err := tw.Write([]byte(contents)); err != nil {
+			t.Fatalf("error from tw.Write %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("error from tw.Close %v", err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatalf("error from gw.Close %v", err)
+	}
+	return buf.Bytes()
+}
+
+func packageEntries(t *testing.T, code []byte) map[string]string {
+	gzr, err := gzip.NewReader(bytes.NewReader(code))
+	if err != nil {
+		t.Fatalf("error from gzip.NewReader %v", err)
+	}
+	tr := tar.NewReader(gzr)
+
+	entries := make(map[string]string)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("error from tr.Next() %v", err)
+		}
+		contents, err := ioutil.ReadAll(tr)
+		if err != nil {
+			t.Fatalf("error from ioutil.ReadAll %v", err)
+		}
+		entries[header.Name] = string(contents)
+	}
+	return entries
+}
@@ -0,0 +1,65 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package nodepackager
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/ccpackager/metadata"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/ccpackager/tarball"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource/api"
+	"github.com/hyperledger/fabric-sdk-go/pkg/logging"
+	"github.com/pkg/errors"
+
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// The peer installs the chaincode's dependencies with npm, so the installed
+// modules are excluded to minimize the size of the install payload.
+var excludedDirs = []string{"node_modules", ".git"}
+
+// The peer builds the chaincode from package.json
+const packageFile = "package.json"
+
+// The peer expects the chaincode's files in the src directory of the package
+const srcDir = "src"
+
+var logger = logging.NewLogger("fabric_sdk_go")
+
+// NewCCPackage creates new Node.js chaincode package from the chaincode directory,
+// including the chaincode metadata in its META-INF directory
+func NewCCPackage(chaincodePath string) (*api.CCPackage, error) {
+
+	if chaincodePath == "" {
+		return nil, errors.New("chaincode path must be provided")
+	}
+
+	if _, err := os.Stat(filepath.Join(chaincodePath, packageFile)); err != nil {
+		return nil, errors.Wrapf(err, "chaincode must have a %s", packageFile)
+	}
+
+	logger.Debugf("projDir variable=%s", chaincodePath)
+
+	descriptors, err := tarball.FindSource(chaincodePath, srcDir, excludedDirs, nil)
+	if err != nil {
+		return nil, err
+	}
+	metadataFiles, err := metadata.Find(chaincodePath)
+	if err != nil {
+		return nil, err
+	}
+	tarBytes, err := tarball.GenerateTarGz(descriptors, metadataFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	ccPkg := &api.CCPackage{Type: pb.ChaincodeSpec_NODE, Code: tarBytes}
+
+	return ccPkg, nil
+}
+
@@ -0,0 +1,47 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package nodepackager
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// Test Node.js ChainCode packaging, which requires a package.json
+func TestNewCCPackage(t *testing.T) {
+	_, err := NewCCPackage("")
+	if err == nil {
+		t.Fatalf("Package Empty Node.js CC must return an error.")
+	}
+
+	dir, err := ioutil.TempDir("", "nodecc")
+	if err != nil {
+		t.Fatalf("error from ioutil.TempDir %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	_, err = NewCCPackage(dir)
+	if err == nil {
+		t.Fatalf("Package Node.js CC without package.json must return an error.")
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, packageFile), []byte("{}"), 0644); err != nil {
+		t.Fatalf("error from ioutil.WriteFile %v", err)
+	}
+	ccPackage, err := NewCCPackage(dir)
+	if err != nil {
+		t.Fatalf("error from Create %v", err)
+	}
+	assert.Equal(t, pb.ChaincodeSpec_NODE, ccPackage.Type)
+	assert.NotEmpty(t, ccPackage.Code)
+}
@@ -0,0 +1,149 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package tarball writes the .tar.gz code of chaincode packages, from the files
+// selected by the packager of the chaincode's language.
+package tarball
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/ccpackager/metadata"
+	"github.com/hyperledger/fabric-sdk-go/pkg/logging"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabric_sdk_go")
+
+// Descriptor is a file of the chaincode to package
+type Descriptor struct {
+	// Name of the file in the chaincode package
+	Name string
+	// Fqp is the fully qualified path of the file
+	Fqp string
+}
+
+// -------------------------------------------------------------------------
+// FindSource(dir, srcDir, excludedDirs, excludedFileTypes)
+// -------------------------------------------------------------------------
+// Given an input 'dir', recursively parse the filesystem for the files
+// of the chaincode, skipping the excluded directories and file types, and
+// the chaincode metadata, which is packaged separately. The tar-friendly
+// "name" of each file is its position relative to 'dir' in the 'srcDir'
+// directory of the package.
+// -------------------------------------------------------------------------
+func FindSource(dir string, srcDir string, excludedDirs []string, excludedFileTypes []string) ([]*Descriptor, error) {
+	var descriptors []*Descriptor
+	err := filepath.Walk(dir,
+		func(fqp string, fileInfo os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if fileInfo.IsDir() && contains(excludedDirs, fileInfo.Name()) {
+				return filepath.SkipDir
+			}
+			// The chaincode metadata is packaged at the root of the package
+			if fileInfo.IsDir() && fqp == filepath.Join(dir, metadata.Dir) {
+				return filepath.SkipDir
+			}
+			if fileInfo.Mode().IsRegular() && !contains(excludedFileTypes, filepath.Ext(fqp)) {
+				relPath, err := filepath.Rel(dir, fqp)
+				if err != nil {
+					return err
+				}
+				descriptors = append(descriptors, &Descriptor{Name: path.Join(srcDir, filepath.ToSlash(relPath)), Fqp: fqp})
+			}
+			return nil
+
+		})
+	if err != nil {
+		return descriptors, err
+	}
+	return descriptors, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// -------------------------------------------------------------------------
+// GenerateTarGz(descriptors, metadataFiles)
+// -------------------------------------------------------------------------
+// creates an .tar.gz stream from the provided descriptor entries and
+// chaincode metadata files
+// -------------------------------------------------------------------------
+func GenerateTarGz(descriptors []*Descriptor, metadataFiles []*metadata.File) ([]byte, error) {
+	// set up the gzip writer
+	var codePackage bytes.Buffer
+	gw := gzip.NewWriter(&codePackage)
+	tw := tar.NewWriter(gw)
+	for _, v := range descriptors {
+		logger.Debugf("generateTarGz for %s", v.Fqp)
+		err := packEntry(tw, gw, v)
+		if err != nil {
+			closeStream(tw, gw)
+			return nil, errors.Wrap(err, "packEntry failed")
+		}
+	}
+	if err := metadata.WriteFiles(tw, metadataFiles); err != nil {
+		closeStream(tw, gw)
+		return nil, errors.Wrap(err, "writing chaincode metadata failed")
+	}
+	closeStream(tw, gw)
+	return codePackage.Bytes(), nil
+
+}
+
+func closeStream(tw *tar.Writer, gw *gzip.Writer) {
+	tw.Close()
+	gw.Close()
+}
+
+func packEntry(tw *tar.Writer, gw *gzip.Writer, descriptor *Descriptor) error {
+	file, err := os.Open(descriptor.Fqp)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if stat, err := file.Stat(); err == nil {
+
+		// now lets create the header as needed for this file within the tarball
+		header := new(tar.Header)
+		header.Name = descriptor.Name
+		header.Size = stat.Size()
+		header.Mode = int64(stat.Mode())
+		// Use a deterministic "zero-time" for all date fields
+		header.ModTime = time.Time{}
+		header.AccessTime = time.Time{}
+		header.ChangeTime = time.Time{}
+		// write the header to the tarball archive
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+
+		// copy the file data to the tarball
+
+		if _, err := io.Copy(tw, file); err != nil {
+			return err
+		}
+		tw.Flush()
+		gw.Flush()
+
+	}
+	return nil
+}
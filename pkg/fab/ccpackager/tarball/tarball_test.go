@@ -0,0 +1,106 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tarball
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/ccpackager/metadata"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateTarGz(t *testing.T) {
+	file, err := ioutil.TempFile("", "chaincode")
+	if err != nil {
+		t.Fatalf("Failed to create file: %s", err)
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.Write([]byte("package main")); err != nil {
+		t.Fatalf("Failed to write file: %s", err)
+	}
+	file.Close()
+
+	code, err := GenerateTarGz(
+		[]*Descriptor{{Name: "src/chaincode.go", Fqp: file.Name()}},
+		[]*metadata.File{{Name: "META-INF/statedb/couchdb/indexes/index.json", Contents: []byte("{}")}},
+	)
+	if err != nil {
+		t.Fatalf("Failed to generate tar: %s", err)
+	}
+
+	gzr, err := gzip.NewReader(bytes.NewReader(code))
+	if err != nil {
+		t.Fatalf("error from gzip.NewReader %v", err)
+	}
+	tr := tar.NewReader(gzr)
+	var names []string
+	for {
+		header, err := tr.Next()
+		if err != nil {
+			break
+		}
+		names = append(names, header.Name)
+	}
+	assert.Equal(t, []string{"src/chaincode.go", "META-INF/statedb/couchdb/indexes/index.json"}, names)
+}
+
+func TestFindSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "chaincode")
+	if err != nil {
+		t.Fatalf("Failed to create dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := []string{"build.gradle", "lib/shim.jar", "build/classes/ExampleCC.class", "src/main/ExampleCC.java",
+		"src/main/Stale.class", "src/main/resources/META-INF/services/example", "META-INF/statedb/couchdb/indexes/index.json"}
+	for _, file := range files {
+		fqp := filepath.Join(dir, file)
+		if err := os.MkdirAll(filepath.Dir(fqp), 0755); err != nil {
+			t.Fatalf("Failed to create dir: %s", err)
+		}
+		if err := ioutil.WriteFile(fqp, []byte(file), 0644); err != nil {
+			t.Fatalf("Failed to write file: %s", err)
+		}
+	}
+
+	descriptors, err := FindSource(dir, "src", []string{"build"}, []string{".class"})
+	if err != nil {
+		t.Fatalf("Failed to find source: %s", err)
+	}
+	var names []string
+	for _, descriptor := range descriptors {
+		names = append(names, descriptor.Name)
+		assert.Equal(t, filepath.Join(dir, descriptor.Name[len("src/"):]), descriptor.Fqp)
+	}
+	// The excluded directories and file types and the chaincode metadata are skipped
+	assert.Equal(t, []string{"src/build.gradle", "src/lib/shim.jar", "src/src/main/ExampleCC.java",
+		"src/src/main/resources/META-INF/services/example"}, names)
+
+	_, err = FindSource(filepath.Join(dir, "missing"), "src", nil, nil)
+	assert.Error(t, err)
+}
+
+// Test packEntry and GenerateTarGz with empty file Descriptor
+func TestEmptyPackEntry(t *testing.T) {
+	emptyDescriptor := &Descriptor{"NewFile", ""}
+	err := packEntry(nil, nil, emptyDescriptor)
+	if err == nil {
+		t.Fatal("packEntry call with empty descriptor info must throw an error")
+	}
+
+	_, err = GenerateTarGz([]*Descriptor{emptyDescriptor}, nil)
+	if err == nil {
+		t.Fatal("GenerateTarGz call with empty descriptor info must throw an error")
+	}
+
+}
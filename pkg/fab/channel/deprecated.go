@@ -9,6 +9,7 @@ SPDX-License-Identifier: Apache-2.0
 package channel
 
 import (
+	reqContext "context"
 	"crypto/x509"
 	"encoding/pem"
 	"strings"
@@ -459,7 +460,7 @@ func (c *Channel) QueryByChaincode(request fab.ChaincodeInvokeRequest) ([][]byte
 		return nil, err
 	}
 
-	resps, err := queryChaincode(c.clientContext, c.name, request, targets)
+	resps, err := queryChaincode(reqContext.Background(), c.clientContext, c.name, request, targets)
 	return collectProposalResponses(resps), err
 }
 
@@ -472,7 +473,7 @@ func (c *Channel) QueryBySystemChaincode(request fab.ChaincodeInvokeRequest) ([]
 		return nil, err
 	}
 
-	resps, err := queryChaincode(c.clientContext, fab.SystemChannel, request, targets)
+	resps, err := queryChaincode(reqContext.Background(), c.clientContext, fab.SystemChannel, request, targets)
 	return collectProposalResponses(resps), err
 }
 
@@ -517,7 +518,7 @@ func (c *Channel) SendInstantiateProposal(chaincodeName string,
 		return nil, fab.EmptyTransactionID, errors.WithMessage(err, "creation of chaincode proposal failed")
 	}
 
-	tpr, err := txn.SendProposal(c.clientContext, tp, targets)
+	tpr, err := txn.SendProposal(reqContext.Background(), c.clientContext, tp, targets)
 	return tpr, tp.TxnID, err
 }
 
@@ -560,7 +561,7 @@ func (c *Channel) SendUpgradeProposal(chaincodeName string,
 		return nil, fab.EmptyTransactionID, errors.WithMessage(err, "creation of chaincode proposal failed")
 	}
 
-	tpr, err := txn.SendProposal(c.clientContext, tp, targets)
+	tpr, err := txn.SendProposal(reqContext.Background(), c.clientContext, tp, targets)
 	return tpr, tp.TxnID, err
 }
 
@@ -641,7 +642,7 @@ func (c *Channel) block(pos *ab.SeekPosition) (*common.Block, error) {
 		Data:   seekInfoBytes,
 	}
 
-	return txn.SendPayload(c.clientContext, &payload, c.Orderers())
+	return txn.SendPayload(reqContext.Background(), c.clientContext, &payload, c.Orderers())
 }
 
 // newNewestSeekPosition returns a SeekPosition that requests the newest block
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package channel
 
 import (
+	reqContext "context"
 	"net/http"
 
 	"github.com/golang/protobuf/proto"
@@ -46,10 +47,16 @@ func NewLedger(ctx context.Client, chName string) (*Ledger, error) {
 // QueryInfo queries for various useful information on the state of the channel
 // (height, known peers).
 func (c *Ledger) QueryInfo(targets []fab.ProposalProcessor) ([]*fab.BlockchainInfoResponse, error) {
+	return c.QueryInfoWithContext(reqContext.Background(), targets)
+}
+
+// QueryInfoWithContext queries for the information on the state of the channel, as QueryInfo does.
+// The query is cancelled once the request context is done.
+func (c *Ledger) QueryInfoWithContext(reqCtx reqContext.Context, targets []fab.ProposalProcessor) ([]*fab.BlockchainInfoResponse, error) {
 	logger.Debug("queryInfo - start")
 
 	cir := createChannelInfoInvokeRequest(c.chName)
-	tprs, errs := queryChaincode(c.ctx, c.chName, cir, targets)
+	tprs, errs := queryChaincode(reqCtx, c.ctx, c.chName, cir, targets)
 
 	responses := []*fab.BlockchainInfoResponse{}
 	for _, tpr := range tprs {
@@ -82,7 +89,7 @@ func (c *Ledger) QueryBlockByHash(blockHash []byte, targets []fab.ProposalProces
 	}
 
 	cir := createBlockByHashInvokeRequest(c.chName, blockHash)
-	tprs, errs := queryChaincode(c.ctx, c.chName, cir, targets)
+	tprs, errs := queryChaincode(reqContext.Background(), c.ctx, c.chName, cir, targets)
 
 	responses := []*common.Block{}
 	for _, tpr := range tprs {
@@ -101,13 +108,19 @@ func (c *Ledger) QueryBlockByHash(blockHash []byte, targets []fab.ProposalProces
 // blockNumber: The number which is the ID of the Block.
 // It returns the block.
 func (c *Ledger) QueryBlock(blockNumber int, targets []fab.ProposalProcessor) ([]*common.Block, error) {
+	return c.QueryBlockWithContext(reqContext.Background(), blockNumber, targets)
+}
+
+// QueryBlockWithContext queries the ledger for Block by block number, as QueryBlock does.
+// The query is cancelled once the request context is done.
+func (c *Ledger) QueryBlockWithContext(reqCtx reqContext.Context, blockNumber int, targets []fab.ProposalProcessor) ([]*common.Block, error) {
 
 	if blockNumber < 0 {
 		return nil, errors.New("blockNumber must be a positive integer")
 	}
 
 	cir := createBlockByNumberInvokeRequest(c.chName, blockNumber)
-	tprs, errs := queryChaincode(c.ctx, c.chName, cir, targets)
+	tprs, errs := queryChaincode(reqCtx, c.ctx, c.chName, cir, targets)
 
 	responses := []*common.Block{}
 	for _, tpr := range tprs {
@@ -136,7 +149,7 @@ func createCommonBlock(tpr *fab.TransactionProposalResponse) (*common.Block, err
 func (c *Ledger) QueryTransaction(transactionID fab.TransactionID, targets []fab.ProposalProcessor) ([]*pb.ProcessedTransaction, error) {
 
 	cir := createTransactionByIDInvokeRequest(c.chName, transactionID)
-	tprs, errs := queryChaincode(c.ctx, c.chName, cir, targets)
+	tprs, errs := queryChaincode(reqContext.Background(), c.ctx, c.chName, cir, targets)
 
 	responses := []*pb.ProcessedTransaction{}
 	for _, tpr := range tprs {
@@ -164,7 +177,7 @@ func createProcessedTransaction(tpr *fab.TransactionProposalResponse) (*pb.Proce
 // This query will be made to specified targets.
 func (c *Ledger) QueryInstantiatedChaincodes(targets []fab.ProposalProcessor) ([]*pb.ChaincodeQueryResponse, error) {
 	cir := createChaincodeInvokeRequest()
-	tprs, errs := queryChaincode(c.ctx, c.chName, cir, targets)
+	tprs, errs := queryChaincode(reqContext.Background(), c.ctx, c.chName, cir, targets)
 
 	responses := []*pb.ChaincodeQueryResponse{}
 	for _, tpr := range tprs {
@@ -200,7 +213,7 @@ func (c *Ledger) QueryConfigBlock(targets []fab.ProposalProcessor, minResponses
 	}
 
 	cir := createConfigBlockInvokeRequest(c.chName)
-	tprs, err := queryChaincode(c.ctx, c.chName, cir, targets)
+	tprs, err := queryChaincode(reqContext.Background(), c.ctx, c.chName, cir, targets)
 	if err != nil && len(tprs) == 0 {
 		return nil, errors.WithMessage(err, "queryChaincode failed")
 	}
@@ -247,7 +260,7 @@ func collectProposalResponses(tprs []*fab.TransactionProposalResponse) [][]byte
 	return responses
 }
 
-func queryChaincode(ctx context.Client, channelID string, request fab.ChaincodeInvokeRequest, targets []fab.ProposalProcessor) ([]*fab.TransactionProposalResponse, error) {
+func queryChaincode(reqCtx reqContext.Context, ctx context.Client, channelID string, request fab.ChaincodeInvokeRequest, targets []fab.ProposalProcessor) ([]*fab.TransactionProposalResponse, error) {
 	txh, err := txn.NewHeader(ctx, channelID)
 	if err != nil {
 		return nil, errors.WithMessage(err, "creation of transaction ID failed")
@@ -257,7 +270,7 @@ func queryChaincode(ctx context.Client, channelID string, request fab.ChaincodeI
 	if err != nil {
 		return nil, errors.WithMessage(err, "NewProposal failed")
 	}
-	tprs, errs := txn.SendProposal(ctx, tp, targets)
+	tprs, errs := txn.SendProposal(reqCtx, ctx, tp, targets)
 
 	return filterResponses(tprs, errs)
 }
@@ -57,6 +57,16 @@ func (i *identityImpl) Verify(serializedID []byte, msg []byte, sig []byte) error
 	return id.Verify(msg, sig)
 }
 
+// SatisfiesPrincipal checks whether the identity satisfies the principal, such as an MSP role
+func (i *identityImpl) SatisfiesPrincipal(serializedID []byte, principal *mb.MSPPrincipal) error {
+	id, err := i.mspManager.DeserializeIdentity(serializedID)
+	if err != nil {
+		return err
+	}
+
+	return id.SatisfiesPrincipal(principal)
+}
+
 func createMSPManager(ctx Context, cfg fab.ChannelCfg) (msp.MSPManager, error) {
 	mspManager := msp.NewMSPManager()
 	if len(cfg.Msps()) > 0 {
@@ -48,6 +48,25 @@ func TestNewMembership(t *testing.T) {
 
 	assert.Nil(t, m.Verify(goodEndorser, []byte("test"), []byte("test1")))
 	assert.NotNil(t, m.Verify(badEndorser, []byte("test"), []byte("test1")))
+
+	// The identity is a member but not an admin of the MSP
+	validator, ok := m.(interface {
+		SatisfiesPrincipal(serializedID []byte, principal *mb.MSPPrincipal) error
+	})
+	if !ok {
+		t.Fatalf("Expected membership to check principals")
+	}
+	member := &mb.MSPPrincipal{
+		PrincipalClassification: mb.MSPPrincipal_ROLE,
+		Principal:               marshalOrPanic(&mb.MSPRole{MspIdentifier: goodMSPID, Role: mb.MSPRole_MEMBER}),
+	}
+	admin := &mb.MSPPrincipal{
+		PrincipalClassification: mb.MSPPrincipal_ROLE,
+		Principal:               marshalOrPanic(&mb.MSPRole{MspIdentifier: goodMSPID, Role: mb.MSPRole_ADMIN}),
+	}
+	assert.Nil(t, validator.SatisfiesPrincipal(goodEndorser, member))
+	assert.NotNil(t, validator.SatisfiesPrincipal(goodEndorser, admin))
+	assert.NotNil(t, validator.SatisfiesPrincipal(badEndorser, member))
 }
 
 func buildMSPConfig(name string, root []byte) *mb.MSPConfig {
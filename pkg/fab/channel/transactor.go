@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package channel
 
 import (
+	reqContext "context"
+
 	"github.com/pkg/errors"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
@@ -15,6 +17,8 @@ import (
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/urlutil"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/orderer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/orderer/greylist"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/orderer/lbp"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
 )
 
@@ -23,10 +27,59 @@ type Transactor struct {
 	ctx       context.Client
 	ChannelID string
 	orderers  []fab.Orderer
+	selection *OrdererSelection
+}
+
+// TransactorOption describes a functional parameter for the NewTransactor constructor
+type TransactorOption func(*Transactor) error
+
+// WithOrdererSelection sets the orderer selection of the transactor. Transactors of the
+// same channel should share the orderer selection so that its state persists across transactions.
+func WithOrdererSelection(selection *OrdererSelection) TransactorOption {
+	return func(t *Transactor) error {
+		t.selection = selection
+		return nil
+	}
+}
+
+// OrdererSelection holds the load-balance policy and greylist used to select the
+// orderer that transactions are broadcast to.
+type OrdererSelection struct {
+	Policy   lbp.LoadBalancePolicy
+	Greylist *greylist.Filter
+}
+
+// NewOrdererSelection returns the orderer selection configured for the named channel.
+func NewOrdererSelection(config core.Config, channelID string) (*OrdererSelection, error) {
+	chConfig, err := config.ChannelConfig(channelID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "loading channel config failed")
+	}
+
+	var strategy core.OrdererSelectionStrategy
+	var preferred []string
+	if chConfig != nil {
+		strategy = chConfig.Policies.OrdererSelection.Strategy
+		for _, name := range chConfig.Orderers {
+			oCfg, err := config.OrdererConfig(name)
+			if err != nil {
+				return nil, errors.WithMessage(err, "loading orderer config failed")
+			}
+			if oCfg != nil {
+				preferred = append(preferred, oCfg.URL)
+			}
+		}
+	}
+
+	selection := OrdererSelection{
+		Policy:   lbp.New(strategy, preferred),
+		Greylist: greylist.New(config.TimeoutOrDefault(core.OrdererGreylistExpiry)),
+	}
+	return &selection, nil
 }
 
 // NewTransactor returns a Transactor for the current context and channel config.
-func NewTransactor(ctx context.Client, cfg fab.ChannelCfg) (*Transactor, error) {
+func NewTransactor(ctx context.Client, cfg fab.ChannelCfg, opts ...TransactorOption) (*Transactor, error) {
 	orderers, err := orderersFromChannelCfg(ctx, cfg)
 	if err != nil {
 		return nil, errors.WithMessage(err, "reading orderers from channel config failed")
@@ -41,6 +94,18 @@ func NewTransactor(ctx context.Client, cfg fab.ChannelCfg) (*Transactor, error)
 		ChannelID: cfg.Name(),
 		orderers:  orderers,
 	}
+	for _, opt := range opts {
+		if err := opt(&t); err != nil {
+			return nil, err
+		}
+	}
+
+	if t.selection == nil {
+		t.selection, err = NewOrdererSelection(ctx.Config(), cfg.Name())
+		if err != nil {
+			return nil, errors.WithMessage(err, "creating orderer selection failed")
+		}
+	}
 	return &t, nil
 }
 
@@ -93,9 +158,10 @@ func orderersByTarget(ctx context.Client) (map[string]core.OrdererConfig, error)
 }
 
 // CreateTransactionHeader creates a Transaction Header based on the current context.
-func (t *Transactor) CreateTransactionHeader() (fab.TransactionHeader, error) {
+// The transaction is created and signed by the identity of the context unless another one is given with fab.WithIdentity.
+func (t *Transactor) CreateTransactionHeader(opts ...fab.TxnHeaderOpt) (fab.TransactionHeader, error) {
 
-	txh, err := txn.NewHeader(t.ctx, t.ChannelID)
+	txh, err := txn.NewHeader(t.ctx, t.ChannelID, opts...)
 	if err != nil {
 		return nil, errors.WithMessage(err, "new transaction ID failed")
 	}
@@ -104,8 +170,8 @@ func (t *Transactor) CreateTransactionHeader() (fab.TransactionHeader, error) {
 }
 
 // SendTransactionProposal sends a TransactionProposal to the target peers.
-func (t *Transactor) SendTransactionProposal(proposal *fab.TransactionProposal, targets []fab.ProposalProcessor) ([]*fab.TransactionProposalResponse, error) {
-	return txn.SendProposal(t.ctx, proposal, targets)
+func (t *Transactor) SendTransactionProposal(reqCtx reqContext.Context, proposal *fab.TransactionProposal, targets []fab.ProposalProcessor) ([]*fab.TransactionProposalResponse, error) {
+	return txn.SendProposal(reqCtx, t.ctx, proposal, targets)
 }
 
 // CreateTransaction create a transaction with proposal response.
@@ -115,6 +181,8 @@ func (t *Transactor) CreateTransaction(request fab.TransactionRequest) (*fab.Tra
 }
 
 // SendTransaction send a transaction to the chain’s orderer service (one or more orderer endpoints) for consensus and committing to the ledger.
-func (t *Transactor) SendTransaction(tx *fab.Transaction) (*fab.TransactionResponse, error) {
-	return txn.Send(t.ctx, tx, t.orderers)
+// The orderers are tried in the order of the channel's orderer selection policy, skipping greylisted orderers;
+// the response reports the orderer that accepted the transaction.
+func (t *Transactor) SendTransaction(reqCtx reqContext.Context, tx *fab.Transaction) (*fab.TransactionResponse, error) {
+	return txn.Send(reqCtx, t.ctx, tx, t.orderers, txn.WithOrdererPolicy(t.selection.Policy), txn.WithOrdererGreylist(t.selection.Greylist))
 }
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package channel
 
 import (
+	"context"
 	"testing"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
@@ -38,7 +39,7 @@ func TestTransaction(t *testing.T) {
 	tx, err := txn.New(request)
 	assert.Nil(t, err)
 
-	_, err = transactor.SendTransaction(tx)
+	_, err = transactor.SendTransaction(context.Background(), tx)
 	assert.Nil(t, err)
 }
 
@@ -93,7 +94,7 @@ func createTransactionProposal(t *testing.T, transactor *Transactor) *fab.Transa
 func createTransactionProposalResponse(t *testing.T, transactor fab.Transactor, tp *fab.TransactionProposal) []*fab.TransactionProposalResponse {
 
 	peer := mocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockRoles: []string{}, Status: 200}
-	tpr, err := transactor.SendTransactionProposal(tp, []fab.ProposalProcessor{&peer})
+	tpr, err := transactor.SendTransactionProposal(context.Background(), tp, []fab.ProposalProcessor{&peer})
 	assert.Nil(t, err)
 
 	return tpr
@@ -102,7 +103,7 @@ func createTransactionProposalResponse(t *testing.T, transactor fab.Transactor,
 func createTransactionProposalResponseBadStatus(t *testing.T, transactor fab.Transactor, tp *fab.TransactionProposal) []*fab.TransactionProposalResponse {
 
 	peer := mocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockRoles: []string{}, Status: 500}
-	tpr, err := transactor.SendTransactionProposal(tp, []fab.ProposalProcessor{&peer})
+	tpr, err := transactor.SendTransactionProposal(context.Background(), tp, []fab.ProposalProcessor{&peer})
 	assert.Nil(t, err)
 
 	return tpr
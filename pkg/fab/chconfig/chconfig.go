@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package chconfig
 
 import (
+	"strings"
+
 	"github.com/golang/protobuf/proto"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/core"
@@ -60,6 +62,7 @@ type ChannelConfig struct {
 type ChannelCfg struct {
 	name        string
 	msps        []*msp.MSPConfig
+	ordererMSPs []*msp.MSPConfig
 	anchorPeers []*fab.OrgAnchorPeer
 	orderers    []string
 	versions    *fab.Versions
@@ -81,6 +84,11 @@ func (cfg *ChannelCfg) Msps() []*msp.MSPConfig {
 	return cfg.msps
 }
 
+// OrdererMSPs returns the msps of the orderer organizations
+func (cfg *ChannelCfg) OrdererMSPs() []*msp.MSPConfig {
+	return cfg.ordererMSPs
+}
+
 // AnchorPeers returns anchor peers
 func (cfg *ChannelCfg) AnchorPeers() []*fab.OrgAnchorPeer {
 	return cfg.anchorPeers
@@ -371,6 +379,9 @@ func loadConfigValue(configItems *ChannelCfg, key string, versionsValue *common.
 		}
 
 		configItems.msps = append(configItems.msps, mspConfig)
+		if strings.HasPrefix(groupName, "base."+channelConfig.OrdererGroupKey+".") {
+			configItems.ordererMSPs = append(configItems.ordererMSPs, mspConfig)
+		}
 		break
 
 	case channelConfig.ConsensusTypeKey:
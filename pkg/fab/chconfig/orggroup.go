@@ -0,0 +1,153 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chconfig
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	channelConfig "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/channelconfig"
+	imsp "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	msp "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+const (
+	defaultHashFamily   = "SHA2"
+	defaultHashFunction = "SHA256"
+)
+
+// OrgMSP holds the MSP definition of an organization
+type OrgMSP struct {
+	MSPID        string
+	RootCerts    [][]byte
+	TLSRootCerts [][]byte
+	AdminCerts   [][]byte
+}
+
+// ExtractChannelCfg loads the channel configuration items of the given channel config
+func ExtractChannelCfg(channelID string, config *common.Config) (fab.ChannelCfg, error) {
+	if config == nil {
+		return nil, errors.New("channel config is required")
+	}
+	return extractConfig(channelID, &common.ConfigEnvelope{Config: config})
+}
+
+// ExtractOrdererCfg loads the configuration items of the orderer group of the given channel config,
+// so that the MSPs of the configuration are those of the orderer organizations
+func ExtractOrdererCfg(channelID string, config *common.Config) (fab.ChannelCfg, error) {
+	if config == nil || config.ChannelGroup == nil {
+		return nil, errors.New("channel config is required")
+	}
+	ordererGroup, ok := config.ChannelGroup.Groups[channelConfig.OrdererGroupKey]
+	if !ok {
+		return nil, errors.New("channel config has no orderer group")
+	}
+
+	ordererConfig := &common.Config{
+		ChannelGroup: &common.ConfigGroup{
+			Groups: map[string]*common.ConfigGroup{channelConfig.OrdererGroupKey: ordererGroup},
+		},
+	}
+	return extractConfig(channelID, &common.ConfigEnvelope{Config: ordererConfig})
+}
+
+// OrdererMSPs returns the MSPs of the orderer organizations of the channel configuration
+func OrdererMSPs(cfg fab.ChannelCfg) ([]*msp.MSPConfig, error) {
+	ordererCfg, ok := cfg.(interface {
+		OrdererMSPs() []*msp.MSPConfig
+	})
+	if !ok {
+		return nil, errors.Errorf("channel configuration %T has no orderer MSPs", cfg)
+	}
+	return ordererCfg.OrdererMSPs(), nil
+}
+
+// MSPIDs returns the IDs of the MSPs of the channel configuration
+func MSPIDs(cfg fab.ChannelCfg) ([]string, error) {
+	var mspIDs []string
+	for _, mspConfig := range cfg.Msps() {
+		fabricConfig := &msp.FabricMSPConfig{}
+		if err := proto.Unmarshal(mspConfig.Config, fabricConfig); err != nil {
+			return nil, errors.Wrap(err, "unmarshal fabric MSP config failed")
+		}
+		mspIDs = append(mspIDs, fabricConfig.Name)
+	}
+	return mspIDs, nil
+}
+
+// NewApplicationOrgGroup builds the config group of an application organization, with the organization's
+// MSP and anchor peers and its Readers, Writers and Admins policies. The group and its elements
+// are modified by the admins of the organization.
+func NewApplicationOrgGroup(org OrgMSP, anchorPeers []*pb.AnchorPeer) (*common.ConfigGroup, error) {
+	if org.MSPID == "" {
+		return nil, errors.New("MSP ID is required")
+	}
+	if len(org.RootCerts) == 0 {
+		return nil, errors.New("root certificates are required")
+	}
+	if len(org.AdminCerts) == 0 {
+		return nil, errors.New("admin certificates are required")
+	}
+
+	fabricConfig := &msp.FabricMSPConfig{
+		Name:         org.MSPID,
+		RootCerts:    org.RootCerts,
+		TlsRootCerts: org.TLSRootCerts,
+		Admins:       org.AdminCerts,
+		CryptoConfig: &msp.FabricCryptoConfig{
+			SignatureHashFamily:            defaultHashFamily,
+			IdentityIdentifierHashFunction: defaultHashFunction,
+		},
+	}
+	fabricConfigBytes, err := proto.Marshal(fabricConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal fabric MSP config failed")
+	}
+	mspConfigBytes, err := proto.Marshal(&msp.MSPConfig{Type: int32(imsp.FABRIC), Config: fabricConfigBytes})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal MSP config failed")
+	}
+
+	group := &common.ConfigGroup{
+		ModPolicy: channelConfig.AdminsPolicyKey,
+		Groups:    make(map[string]*common.ConfigGroup),
+		Values: map[string]*common.ConfigValue{
+			channelConfig.MSPKey: {ModPolicy: channelConfig.AdminsPolicyKey, Value: mspConfigBytes},
+		},
+		Policies: make(map[string]*common.ConfigPolicy),
+	}
+
+	if len(anchorPeers) > 0 {
+		anchorPeersBytes, err := proto.Marshal(&pb.AnchorPeers{AnchorPeers: anchorPeers})
+		if err != nil {
+			return nil, errors.Wrap(err, "marshal anchor peers failed")
+		}
+		group.Values[channelConfig.AnchorPeersKey] = &common.ConfigValue{ModPolicy: channelConfig.AdminsPolicyKey, Value: anchorPeersBytes}
+	}
+
+	policies := map[string]*common.SignaturePolicyEnvelope{
+		channelConfig.ReadersPolicyKey: cauthdsl.SignedByMspMember(org.MSPID),
+		channelConfig.WritersPolicyKey: cauthdsl.SignedByMspMember(org.MSPID),
+		channelConfig.AdminsPolicyKey:  cauthdsl.SignedByMspAdmin(org.MSPID),
+	}
+	for key, policy := range policies {
+		policyBytes, err := proto.Marshal(policy)
+		if err != nil {
+			return nil, errors.Wrap(err, "marshal signature policy failed")
+		}
+		group.Policies[key] = &common.ConfigPolicy{
+			ModPolicy: channelConfig.AdminsPolicyKey,
+			Policy:    &common.Policy{Type: int32(common.Policy_SIGNATURE), Value: policyBytes},
+		}
+	}
+
+	return group, nil
+}
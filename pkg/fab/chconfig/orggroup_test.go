@@ -0,0 +1,146 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+func TestNewApplicationOrgGroup(t *testing.T) {
+	org := OrgMSP{
+		MSPID:        "Org3MSP",
+		RootCerts:    [][]byte{[]byte("root")},
+		TLSRootCerts: [][]byte{[]byte("tlsroot")},
+		AdminCerts:   [][]byte{[]byte("admin")},
+	}
+	anchorPeers := []*pb.AnchorPeer{{Host: "peer0.org3.example.com", Port: 7051}}
+
+	group, err := NewApplicationOrgGroup(org, anchorPeers)
+	if err != nil {
+		t.Fatalf("Failed to build org group: %s", err)
+	}
+	assert.Equal(t, "Admins", group.ModPolicy)
+	assert.Len(t, group.Values, 2)
+	for _, key := range []string{"Readers", "Writers", "Admins"} {
+		if assert.Contains(t, group.Policies, key) {
+			assert.Equal(t, int32(common.Policy_SIGNATURE), group.Policies[key].Policy.Type)
+		}
+	}
+
+	// The org group is parsed with the channel config
+	config := &common.Config{
+		ChannelGroup: &common.ConfigGroup{
+			Groups: map[string]*common.ConfigGroup{
+				"Application": {Groups: map[string]*common.ConfigGroup{"Org3MSP": group}},
+			},
+		},
+	}
+	channelCfg, err := ExtractChannelCfg("mychannel", config)
+	if err != nil {
+		t.Fatalf("Failed to extract channel config: %s", err)
+	}
+	mspIDs, err := MSPIDs(channelCfg)
+	if err != nil {
+		t.Fatalf("Failed to get MSP IDs: %s", err)
+	}
+	assert.Equal(t, []string{"Org3MSP"}, mspIDs)
+	if assert.Len(t, channelCfg.AnchorPeers(), 1) {
+		assert.Equal(t, "Org3MSP", channelCfg.AnchorPeers()[0].Org)
+		assert.Equal(t, "peer0.org3.example.com", channelCfg.AnchorPeers()[0].Host)
+	}
+
+	// Anchor peers are optional
+	group, err = NewApplicationOrgGroup(org, nil)
+	if err != nil {
+		t.Fatalf("Failed to build org group: %s", err)
+	}
+	assert.NotContains(t, group.Values, "AnchorPeers")
+}
+
+func TestExtractOrdererCfg(t *testing.T) {
+	builder := &mocks.MockConfigBlockBuilder{
+		MockConfigGroupBuilder: mocks.MockConfigGroupBuilder{
+			ModPolicy:      "Admins",
+			MSPNames:       []string{"Org1MSP", "Org2MSP"},
+			OrdererAddress: "localhost:7054",
+			RootCA:         "root",
+		},
+	}
+	configEnvelope, err := resource.CreateConfigEnvelope(builder.Build().Data.Data[0])
+	if err != nil {
+		t.Fatalf("Failed to get config envelope: %s", err)
+	}
+
+	ordererCfg, err := ExtractOrdererCfg("mychannel", configEnvelope.Config)
+	if err != nil {
+		t.Fatalf("Failed to extract orderer config: %s", err)
+	}
+	mspIDs, err := MSPIDs(ordererCfg)
+	if err != nil {
+		t.Fatalf("Failed to get MSP IDs: %s", err)
+	}
+	assert.Equal(t, []string{"OrdererMSP"}, mspIDs)
+
+	_, err = ExtractOrdererCfg("mychannel", &common.Config{ChannelGroup: &common.ConfigGroup{}})
+	assert.NotNil(t, err, "expected error for missing orderer group")
+	_, err = ExtractOrdererCfg("mychannel", nil)
+	assert.NotNil(t, err, "expected error for nil config")
+}
+
+func TestOrdererMSPs(t *testing.T) {
+	builder := &mocks.MockConfigBlockBuilder{
+		MockConfigGroupBuilder: mocks.MockConfigGroupBuilder{
+			ModPolicy:      "Admins",
+			MSPNames:       []string{"Org1MSP", "Org2MSP"},
+			OrdererAddress: "localhost:7054",
+			RootCA:         "root",
+		},
+	}
+	configEnvelope, err := resource.CreateConfigEnvelope(builder.Build().Data.Data[0])
+	if err != nil {
+		t.Fatalf("Failed to get config envelope: %s", err)
+	}
+	channelCfg, err := ExtractChannelCfg("mychannel", configEnvelope.Config)
+	if err != nil {
+		t.Fatalf("Failed to extract channel config: %s", err)
+	}
+
+	// The MSPs of the application organizations aren't orderer MSPs
+	msps, err := OrdererMSPs(channelCfg)
+	if err != nil {
+		t.Fatalf("Failed to get orderer MSPs: %s", err)
+	}
+	mspIDs, err := MSPIDs(&ChannelCfg{msps: msps})
+	if err != nil {
+		t.Fatalf("Failed to get MSP IDs: %s", err)
+	}
+	assert.Equal(t, []string{"OrdererMSP"}, mspIDs)
+
+	_, err = OrdererMSPs(mocks.NewMockChannelCfg("mychannel"))
+	assert.NotNil(t, err, "expected error for channel config without orderer MSPs")
+}
+
+func TestNewApplicationOrgGroupErrors(t *testing.T) {
+	_, err := NewApplicationOrgGroup(OrgMSP{RootCerts: [][]byte{[]byte("root")}, AdminCerts: [][]byte{[]byte("admin")}}, nil)
+	assert.NotNil(t, err, "expected error for missing MSP ID")
+
+	_, err = NewApplicationOrgGroup(OrgMSP{MSPID: "Org3MSP", AdminCerts: [][]byte{[]byte("admin")}}, nil)
+	assert.NotNil(t, err, "expected error for missing root certs")
+
+	_, err = NewApplicationOrgGroup(OrgMSP{MSPID: "Org3MSP", RootCerts: [][]byte{[]byte("root")}}, nil)
+	assert.NotNil(t, err, "expected error for missing admin certs")
+
+	_, err = ExtractChannelCfg("mychannel", nil)
+	assert.NotNil(t, err, "expected error for nil config")
+}
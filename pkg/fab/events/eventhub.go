@@ -445,6 +445,16 @@ func (eventHub *EventHub) UnregisterChaincodeEvent(cbe *fab.ChainCodeCBE) {
 // callback: Function that takes a single parameter which
 // is a json object representation of type "message Transaction"
 func (eventHub *EventHub) RegisterTxEvent(txnID fab.TransactionID, callback func(fab.TransactionID, pb.TxValidationCode, error)) {
+	eventHub.RegisterTxBlockEvent(txnID, func(txID fab.TransactionID, code pb.TxValidationCode, blockNum uint64, err error) {
+		callback(txID, code, err)
+	})
+}
+
+// RegisterTxBlockEvent registers a callback function to receive transactional events
+// along with the number of the block in which the transaction was committed.
+// txid: transaction id
+// callback: Function that is invoked with the validation code and block number of the transaction
+func (eventHub *EventHub) RegisterTxBlockEvent(txnID fab.TransactionID, callback func(fab.TransactionID, pb.TxValidationCode, uint64, error)) {
 	logger.Debugf("reg txid %s\n", txnID)
 	eventHub.txRegistrants.Store(txnID, callback)
 }
@@ -488,10 +498,10 @@ func (eventHub *EventHub) txCallback(block *common.Block) {
 			callback := eventHub.getTXRegistrant(txnID)
 			if callback != nil {
 				if txFilter.IsInvalid(i) {
-					callback(fab.TransactionID(txnID), txFilter.Flag(i),
+					callback(fab.TransactionID(txnID), txFilter.Flag(i), block.Header.Number,
 						status.New(status.EventServerStatus, int32(txFilter.Flag(i)), "received invalid transaction", nil))
 				} else {
-					callback(fab.TransactionID(txnID), txFilter.Flag(i), nil)
+					callback(fab.TransactionID(txnID), txFilter.Flag(i), block.Header.Number, nil)
 				}
 			} else {
 				logger.Debugf("No callback registered for TxID: %s\n", txnID)
@@ -524,12 +534,12 @@ func (eventHub *EventHub) getChaincodeRegistrants(chaincodeID string) []*fab.Cha
 	return clone
 }
 
-func (eventHub *EventHub) getTXRegistrant(txID fab.TransactionID) func(fab.TransactionID, pb.TxValidationCode, error) {
+func (eventHub *EventHub) getTXRegistrant(txID fab.TransactionID) func(fab.TransactionID, pb.TxValidationCode, uint64, error) {
 	v, ok := eventHub.txRegistrants.Load(txID)
 	if !ok {
 		return nil
 	}
-	return v.(func(fab.TransactionID, pb.TxValidationCode, error))
+	return v.(func(fab.TransactionID, pb.TxValidationCode, uint64, error))
 }
 
 // getChainCodeEvents parses block events for chaincode events associated with individual transactions
@@ -405,7 +405,7 @@ func (ed *Dispatcher) publishFilteredBlockEvents(fblock *pb.FilteredBlock) {
 	}
 
 	for _, tx := range fblock.FilteredTransactions {
-		ed.publishTxStatusEvents(tx)
+		ed.publishTxStatusEvents(tx, fblock.Number)
 
 		// Only send a chaincode event if the transaction has committed
 		if tx.TxValidationCode == pb.TxValidationCode_VALID {
@@ -415,29 +415,29 @@ func (ed *Dispatcher) publishFilteredBlockEvents(fblock *pb.FilteredBlock) {
 			}
 			for _, action := range txActions.ChaincodeActions {
 				if action.ChaincodeEvent != nil {
-					ed.publishCCEvents(action.ChaincodeEvent)
+					ed.publishCCEvents(action.ChaincodeEvent, fblock.Number)
 				}
 			}
 		}
 	}
 }
 
-func (ed *Dispatcher) publishTxStatusEvents(tx *pb.FilteredTransaction) {
+func (ed *Dispatcher) publishTxStatusEvents(tx *pb.FilteredTransaction, blockNum uint64) {
 	logger.Debugf("Publishing Tx Status event for TxID [%s]...", tx.Txid)
 	if reg, ok := ed.txRegistrations[tx.Txid]; ok {
 		logger.Debugf("Sending Tx Status event for TxID [%s] to registrant...", tx.Txid)
 
 		if ed.eventConsumerTimeout < 0 {
 			select {
-			case reg.Eventch <- NewTxStatusEvent(tx.Txid, tx.TxValidationCode):
+			case reg.Eventch <- NewTxStatusEvent(tx.Txid, tx.TxValidationCode, blockNum):
 			default:
 				logger.Warnf("Unable to send to Tx Status event channel.")
 			}
 		} else if ed.eventConsumerTimeout == 0 {
-			reg.Eventch <- NewTxStatusEvent(tx.Txid, tx.TxValidationCode)
+			reg.Eventch <- NewTxStatusEvent(tx.Txid, tx.TxValidationCode, blockNum)
 		} else {
 			select {
-			case reg.Eventch <- NewTxStatusEvent(tx.Txid, tx.TxValidationCode):
+			case reg.Eventch <- NewTxStatusEvent(tx.Txid, tx.TxValidationCode, blockNum):
 			case <-time.After(ed.eventConsumerTimeout):
 				logger.Warnf("Timed out sending Tx Status event.")
 			}
@@ -445,7 +445,7 @@ func (ed *Dispatcher) publishTxStatusEvents(tx *pb.FilteredTransaction) {
 	}
 }
 
-func (ed *Dispatcher) publishCCEvents(ccEvent *pb.ChaincodeEvent) {
+func (ed *Dispatcher) publishCCEvents(ccEvent *pb.ChaincodeEvent, blockNum uint64) {
 	for _, reg := range ed.ccRegistrations {
 		logger.Debugf("Matching CCEvent[%s,%s] against Reg[%s,%s] ...", ccEvent.ChaincodeId, ccEvent.EventName, reg.ChaincodeID, reg.EventFilter)
 		if reg.ChaincodeID == ccEvent.ChaincodeId && reg.EventRegExp.MatchString(ccEvent.EventName) {
@@ -453,15 +453,15 @@ func (ed *Dispatcher) publishCCEvents(ccEvent *pb.ChaincodeEvent) {
 
 			if ed.eventConsumerTimeout < 0 {
 				select {
-				case reg.Eventch <- NewChaincodeEvent(ccEvent.ChaincodeId, ccEvent.EventName, ccEvent.TxId):
+				case reg.Eventch <- NewChaincodeEvent(ccEvent.ChaincodeId, ccEvent.EventName, ccEvent.TxId, ccEvent.Payload, blockNum):
 				default:
 					logger.Warnf("Unable to send to CC event channel.")
 				}
 			} else if ed.eventConsumerTimeout == 0 {
-				reg.Eventch <- NewChaincodeEvent(ccEvent.ChaincodeId, ccEvent.EventName, ccEvent.TxId)
+				reg.Eventch <- NewChaincodeEvent(ccEvent.ChaincodeId, ccEvent.EventName, ccEvent.TxId, ccEvent.Payload, blockNum)
 			} else {
 				select {
-				case reg.Eventch <- NewChaincodeEvent(ccEvent.ChaincodeId, ccEvent.EventName, ccEvent.TxId):
+				case reg.Eventch <- NewChaincodeEvent(ccEvent.ChaincodeId, ccEvent.EventName, ccEvent.TxId, ccEvent.Payload, blockNum):
 				case <-time.After(ed.eventConsumerTimeout):
 					logger.Warnf("Timed out sending CC event.")
 				}
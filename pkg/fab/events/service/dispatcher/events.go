@@ -107,19 +107,22 @@ func NewRegisterEvent(respch chan<- fab.Registration, errCh chan<- error) Regist
 }
 
 // NewChaincodeEvent creates a new ChaincodeEvent
-func NewChaincodeEvent(chaincodeID, eventName, txID string) *fab.CCEvent {
+func NewChaincodeEvent(chaincodeID, eventName, txID string, payload []byte, blockNum uint64) *fab.CCEvent {
 	return &fab.CCEvent{
 		ChaincodeID: chaincodeID,
 		EventName:   eventName,
 		TxID:        txID,
+		Payload:     payload,
+		BlockNumber: blockNum,
 	}
 }
 
 // NewTxStatusEvent creates a new TxStatusEvent
-func NewTxStatusEvent(txID string, txValidationCode pb.TxValidationCode) *fab.TxStatusEvent {
+func NewTxStatusEvent(txID string, txValidationCode pb.TxValidationCode, blockNum uint64) *fab.TxStatusEvent {
 	return &fab.TxStatusEvent{
 		TxID:             txID,
 		TxValidationCode: txValidationCode,
+		BlockNumber:      blockNum,
 	}
 }
 
@@ -294,11 +294,14 @@ func TestTxStatusEvents(t *testing.T) {
 	}
 	defer eventService.Unregister(reg2)
 
+	// The transactions are committed in the second block
+	eventProducer.Ledger().NewFilteredBlock(channelID)
 	eventProducer.Ledger().NewFilteredBlock(
 		channelID,
 		servicemocks.NewFilteredTx(txID1, txCode1),
 		servicemocks.NewFilteredTx(txID2, txCode2),
 	)
+	expectedBlockNum := uint64(1)
 
 	numExpected := 2
 	numReceived := 0
@@ -310,6 +313,9 @@ func TestTxStatusEvents(t *testing.T) {
 				t.Fatalf("unexpected closed channel")
 			} else {
 				checkTxStatusEvent(t, event, txID1, txCode1)
+				if event.BlockNumber != expectedBlockNum {
+					t.Fatalf("expecting block number [%d] but received [%d]", expectedBlockNum, event.BlockNumber)
+				}
 				numReceived++
 			}
 		case event, ok := <-eventch2:
@@ -317,6 +323,9 @@ func TestTxStatusEvents(t *testing.T) {
 				t.Fatalf("unexpected closed channel")
 			} else {
 				checkTxStatusEvent(t, event, txID2, txCode2)
+				if event.BlockNumber != expectedBlockNum {
+					t.Fatalf("expecting block number [%d] but received [%d]", expectedBlockNum, event.BlockNumber)
+				}
 				numReceived++
 			}
 		case <-time.After(5 * time.Second):
@@ -14,15 +14,17 @@ import (
 
 // MockChannelProvider holds a mock channel provider.
 type MockChannelProvider struct {
-	ctx        core.Providers
-	transactor fab.Transactor
+	ctx          core.Providers
+	transactor   fab.Transactor
+	eventService fab.EventService
 }
 
 // MockChannelService holds a mock channel service.
 type MockChannelService struct {
-	provider   *MockChannelProvider
-	channelID  string
-	transactor fab.Transactor
+	provider     *MockChannelProvider
+	channelID    string
+	transactor   fab.Transactor
+	eventService fab.EventService
 }
 
 // NewMockChannelProvider returns a mock ChannelProvider
@@ -39,12 +41,22 @@ func (cp *MockChannelProvider) SetTransactor(transactor fab.Transactor) {
 	cp.transactor = transactor
 }
 
+// SetEventService sets the event service for all mock channel services
+func (cp *MockChannelProvider) SetEventService(eventService fab.EventService) {
+	cp.eventService = eventService
+}
+
 // ChannelService returns a mock ChannelService
 func (cp *MockChannelProvider) ChannelService(ic fab.IdentityContext, channelID string) (fab.ChannelService, error) {
+	eventService := cp.eventService
+	if eventService == nil {
+		eventService = NewMockEventService()
+	}
 	cs := MockChannelService{
-		provider:   cp,
-		channelID:  channelID,
-		transactor: cp.transactor,
+		provider:     cp,
+		channelID:    channelID,
+		transactor:   cp.transactor,
+		eventService: eventService,
 	}
 	return &cs, nil
 }
@@ -54,6 +66,16 @@ func (cs *MockChannelService) EventHub() (fab.EventHub, error) {
 	return NewMockEventHub(), nil
 }
 
+// EventService returns the mock event service
+func (cs *MockChannelService) EventService() (fab.EventService, error) {
+	return cs.eventService, nil
+}
+
+// SetEventService changes the return value of EventService
+func (cs *MockChannelService) SetEventService(eventService fab.EventService) {
+	cs.eventService = eventService
+}
+
 // Transactor ...
 func (cs *MockChannelService) Transactor() (fab.Transactor, error) {
 	return cs.transactor, nil
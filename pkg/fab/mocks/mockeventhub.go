@@ -61,6 +61,13 @@ func (m *MockEventHub) RegisterTxEvent(txnID fab.TransactionID, callback func(fa
 	return
 }
 
+// RegisterTxBlockEvent registers the callback with a block number of zero
+func (m *MockEventHub) RegisterTxBlockEvent(txnID fab.TransactionID, callback func(fab.TransactionID, pb.TxValidationCode, uint64, error)) {
+	m.RegisterTxEvent(txnID, func(txID fab.TransactionID, code pb.TxValidationCode, err error) {
+		callback(txID, code, 0, err)
+	})
+}
+
 // UnregisterTxEvent not implemented
 func (m *MockEventHub) UnregisterTxEvent(txnID fab.TransactionID) {
 	return
@@ -0,0 +1,98 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mocks
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+)
+
+// TxStatusReg is a transaction status registration of the mock event service
+type TxStatusReg struct {
+	TxID    string
+	Eventch chan *fab.TxStatusEvent
+}
+
+// CCReg is a chaincode event registration of the mock event service
+type CCReg struct {
+	ChaincodeID string
+	EventFilter string
+	Eventch     chan *fab.CCEvent
+}
+
+// MockEventService is a mock event service. Registrations are published on the
+// registration channels so that tests can send events to the registrants.
+// Unregister does not close the event channels.
+type MockEventService struct {
+	TxStatusRegCh chan *TxStatusReg
+	CCRegCh       chan *CCReg
+	Unregistered  chan fab.Registration
+	Closed        bool
+}
+
+// NewMockEventService returns a new mock event service
+func NewMockEventService() *MockEventService {
+	return &MockEventService{
+		TxStatusRegCh: make(chan *TxStatusReg),
+		CCRegCh:       make(chan *CCReg),
+		Unregistered:  make(chan fab.Registration, 100),
+	}
+}
+
+// RegisterBlockEvent is not supported
+func (m *MockEventService) RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockEvent, error) {
+	return nil, nil, errors.New("block events are not permitted")
+}
+
+// RegisterFilteredBlockEvent not implemented
+func (m *MockEventService) RegisterFilteredBlockEvent() (fab.Registration, <-chan *fab.FilteredBlockEvent, error) {
+	return nil, make(chan *fab.FilteredBlockEvent), nil
+}
+
+// RegisterChaincodeEvent publishes the registration on CCRegCh
+func (m *MockEventService) RegisterChaincodeEvent(ccID, eventFilter string) (fab.Registration, <-chan *fab.CCEvent, error) {
+	if ccID == "" {
+		return nil, nil, errors.New("chaincode ID is required")
+	}
+	reg := &CCReg{ChaincodeID: ccID, EventFilter: eventFilter, Eventch: make(chan *fab.CCEvent, 10)}
+	go func() { m.CCRegCh <- reg }()
+	return reg, reg.Eventch, nil
+}
+
+// RegisterTxStatusEvent publishes the registration on TxStatusRegCh
+func (m *MockEventService) RegisterTxStatusEvent(txID string) (fab.Registration, <-chan *fab.TxStatusEvent, error) {
+	if txID == "" {
+		return nil, nil, errors.New("txID must be provided")
+	}
+	reg := &TxStatusReg{TxID: txID, Eventch: make(chan *fab.TxStatusEvent, 1)}
+	go func() { m.TxStatusRegCh <- reg }()
+	return reg, reg.Eventch, nil
+}
+
+// Unregister publishes the registration on Unregistered
+func (m *MockEventService) Unregister(reg fab.Registration) {
+	select {
+	case m.Unregistered <- reg:
+	default:
+	}
+}
+
+// Connect not implemented
+func (m *MockEventService) Connect() error {
+	return nil
+}
+
+// Close marks the mock event service as closed
+func (m *MockEventService) Close() {
+	m.Closed = true
+}
+
+// RegisterConnectionEvent not implemented
+func (m *MockEventService) RegisterConnectionEvent() (fab.Registration, chan *fab.ConnectionEvent, error) {
+	return nil, make(chan *fab.ConnectionEvent), nil
+}
@@ -81,7 +81,7 @@ func (o *mockOrderer) URL() string {
 
 // SendBroadcast accepts client broadcast calls and reports them to the listener channel
 // Returns the first enqueued error, or nil if there are no enqueued errors
-func (o *mockOrderer) SendBroadcast(envelope *fab.SignedEnvelope) (*common.Status, error) {
+func (o *mockOrderer) SendBroadcast(ctx context.Context, envelope *fab.SignedEnvelope) (*common.Status, error) {
 	// Report this call to the listener
 	if o.BroadcastListener != nil {
 		o.BroadcastQueue <- envelope
@@ -95,7 +95,7 @@ func (o *mockOrderer) SendBroadcast(envelope *fab.SignedEnvelope) (*common.Statu
 }
 
 // SendDeliver returns the channels for delivery of prepared mock values and errors (if any)
-func (o *mockOrderer) SendDeliver(envelope *fab.SignedEnvelope) (chan *common.Block, chan error, context.CancelFunc) {
+func (o *mockOrderer) SendDeliver(ctx context.Context, envelope *fab.SignedEnvelope) (chan *common.Block, chan error, context.CancelFunc) {
 	return o.Deliveries, o.DeliveryErrors, func() {}
 }
 
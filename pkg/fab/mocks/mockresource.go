@@ -8,6 +8,7 @@ package mocks
 
 import (
 	"bytes"
+	reqContext "context"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
@@ -20,6 +21,10 @@ import (
 // MockResource ...
 type MockResource struct {
 	errorScenario bool
+	// ConfigEnvelope is returned by LastConfigFromOrderer
+	ConfigEnvelope *common.ConfigEnvelope
+	// ChannelRequests records the requests passed to CreateChannel
+	ChannelRequests []api.CreateChannelRequest
 }
 
 // NewMockInvalidResource ...
@@ -47,6 +52,7 @@ func (c *MockResource) CreateChannel(request api.CreateChannelRequest) (fab.Tran
 		return "", errors.New("Create Channel Error")
 	}
 
+	c.ChannelRequests = append(c.ChannelRequests, request)
 	return "", nil
 }
 
@@ -66,9 +72,30 @@ func (c *MockResource) GenesisBlockFromOrderer(channelName string, orderer fab.O
 	return NewSimpleMockBlock(), nil
 }
 
-// LastConfigFromOrderer not implemented
+// BlockFromOrderer returns a mock block with the given number
+func (c *MockResource) BlockFromOrderer(reqCtx reqContext.Context, channelName string, orderer fab.Orderer, blockNumber uint64) (*common.Block, error) {
+	if c.errorScenario {
+		return nil, errors.New("Block Error")
+	}
+	block := NewSimpleMockBlock()
+	block.Header.Number = blockNumber
+	return block, nil
+}
+
+// NewestBlockFromOrderer returns a mock block
+func (c *MockResource) NewestBlockFromOrderer(reqCtx reqContext.Context, channelName string, orderer fab.Orderer) (*common.Block, error) {
+	if c.errorScenario {
+		return nil, errors.New("Block Error")
+	}
+	return NewSimpleMockBlock(), nil
+}
+
+// LastConfigFromOrderer returns the mock config envelope
 func (c *MockResource) LastConfigFromOrderer(channelName string, orderer fab.Orderer) (*common.ConfigEnvelope, error) {
-	return nil, nil
+	if c.errorScenario {
+		return nil, errors.New("Last Config Error")
+	}
+	return c.ConfigEnvelope, nil
 }
 
 // JoinChannel sends a join channel proposal to the target peer.
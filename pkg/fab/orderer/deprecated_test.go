@@ -9,6 +9,7 @@ SPDX-License-Identifier: Apache-2.0
 package orderer
 
 import (
+	"context"
 	"strings"
 	"testing"
 	"time"
@@ -34,7 +35,7 @@ func TestDeprecatedSendDeliverHappy(t *testing.T) {
 	_, addr := startMockServer(t, grpcServer)
 
 	orderer, _ := NewOrderer("grpc://"+addr, "", "", mocks.NewMockConfig(), kap)
-	blocks, errs, cancel := orderer.SendDeliver(&fab.SignedEnvelope{})
+	blocks, errs, cancel := orderer.SendDeliver(context.Background(), &fab.SignedEnvelope{})
 	defer cancel()
 
 	select {
@@ -59,7 +60,7 @@ func TestDeprecatedSendDeliverError(t *testing.T) {
 	// Test deliver with deliver error from OS
 	testError := errors.New("test error")
 	mockServer.DeliverError = testError
-	blocks, errs, cancel := orderer.SendDeliver(&fab.SignedEnvelope{})
+	blocks, errs, cancel := orderer.SendDeliver(context.Background(), &fab.SignedEnvelope{})
 	defer cancel()
 
 	select {
@@ -77,7 +78,7 @@ func TestDeprecatedSendDeliverError(t *testing.T) {
 func TestDeprecatedSendDeliverBadURL(t *testing.T) {
 	orderer, _ := NewOrderer(testOrdererURL+"invalid-test", "", "", mocks.NewMockConfig(), kap)
 	// Test deliver happy path
-	blocks, errs, cancel := orderer.SendDeliver(&fab.SignedEnvelope{})
+	blocks, errs, cancel := orderer.SendDeliver(context.Background(), &fab.SignedEnvelope{})
 	defer cancel()
 
 	select {
@@ -123,14 +124,14 @@ func TestDeprecatedSendBroadcast(t *testing.T) {
 	_, addr := startMockServer(t, grpcServer)
 
 	orderer, _ := NewOrderer("grpc://"+addr, "", "", mocks.NewMockConfig(), kap)
-	_, err := orderer.SendBroadcast(&fab.SignedEnvelope{})
+	_, err := orderer.SendBroadcast(context.Background(), &fab.SignedEnvelope{})
 
 	if err != nil {
 		t.Fatalf("Test SendBroadcast was not supposed to fail")
 	}
 
 	orderer, _ = NewOrderer(testOrdererURL+"Test", "", "", mocks.NewMockConfig(), kap)
-	_, err = orderer.SendBroadcast(&fab.SignedEnvelope{})
+	_, err = orderer.SendBroadcast(context.Background(), &fab.SignedEnvelope{})
 
 	if err == nil || !strings.HasPrefix(err.Error(), "NewAtomicBroadcastClient") {
 		t.Fatalf("Test SendBroadcast was supposed to fail with expected error, instead it fail with [%s] error", err)
@@ -153,7 +154,7 @@ func TestDeprecatedSendDeliverServerBadResponse(t *testing.T) {
 	addr := startCustomizedMockServer(t, testOrdererURL, grpcServer, &broadcastServer)
 	orderer, _ := NewOrderer("grpc://"+addr, "", "", mocks.NewMockConfig(), kap)
 
-	blocks, errors, cancel := orderer.SendDeliver(&fab.SignedEnvelope{})
+	blocks, errors, cancel := orderer.SendDeliver(context.Background(), &fab.SignedEnvelope{})
 	defer cancel()
 
 	select {
@@ -184,7 +185,7 @@ func TestDeprecatedSendDeliverServerSuccessResponse(t *testing.T) {
 
 	orderer, _ := NewOrderer("grpc://"+addr, "", "", mocks.NewMockConfig(), kap)
 
-	blocks, errors, cancel := orderer.SendDeliver(&fab.SignedEnvelope{})
+	blocks, errors, cancel := orderer.SendDeliver(context.Background(), &fab.SignedEnvelope{})
 	defer cancel()
 
 	select {
@@ -210,7 +211,7 @@ func TestDeprecatedSendDeliverFailure(t *testing.T) {
 	addr := startCustomizedMockServer(t, testOrdererURL, grpcServer, &broadcastServer)
 	orderer, _ := NewOrderer("grpc://"+addr, "", "", mocks.NewMockConfig(), kap)
 
-	blocks, errors, cancel := orderer.SendDeliver(&fab.SignedEnvelope{})
+	blocks, errors, cancel := orderer.SendDeliver(context.Background(), &fab.SignedEnvelope{})
 	defer cancel()
 
 	select {
@@ -236,7 +237,7 @@ func TestDeprecatedSendBroadcastServerBadResponse(t *testing.T) {
 	addr := startCustomizedMockServer(t, testOrdererURL, grpcServer, &broadcastServer)
 	orderer, _ := NewOrderer("grpc://"+addr, "", "", mocks.NewMockConfig(), kap)
 
-	_, err := orderer.SendBroadcast(&fab.SignedEnvelope{})
+	_, err := orderer.SendBroadcast(context.Background(), &fab.SignedEnvelope{})
 
 	if err == nil {
 		t.Fatalf("Expected error")
@@ -258,7 +259,7 @@ func TestDeprecatedSendBroadcastError(t *testing.T) {
 	addr := startCustomizedMockServer(t, testOrdererURL, grpcServer, &broadcastServer)
 	orderer, _ := NewOrderer("grpc://"+addr, "", "", mocks.NewMockConfig(), kap)
 
-	status, err := orderer.SendBroadcast(&fab.SignedEnvelope{})
+	status, err := orderer.SendBroadcast(context.Background(), &fab.SignedEnvelope{})
 
 	if err == nil || status != nil {
 		t.Fatalf("expected Send Broadcast to fail with error, but got %s", err)
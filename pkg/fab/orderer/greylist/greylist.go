@@ -0,0 +1,99 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package greylist provides a filter that temporarily excludes orderers that failed.
+package greylist
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/urlutil"
+	"github.com/hyperledger/fabric-sdk-go/pkg/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/logging"
+)
+
+var logger = logging.NewLogger("fabric_sdk_go")
+
+// Filter is an orderer filter that greylists certain orderers that are
+// known to be down for the configured amount of time
+type Filter struct {
+	// greylistURLs contains a map of orderer URLs as keys and timestamps as values
+	// orderers are expired from the greylist based on these timestamps
+	greylistURLs   sync.Map
+	expiryInterval time.Duration
+}
+
+// New creates a new greylist filter with the given expiry interval
+func New(expire time.Duration) *Filter {
+	return &Filter{expiryInterval: expire}
+}
+
+// Accept returns whether or not to Accept an orderer as a candidate for broadcast
+func (b *Filter) Accept(orderer fab.Orderer) bool {
+	ordererAddress := urlutil.ToAddress(orderer.URL())
+	value, ok := b.greylistURLs.Load(ordererAddress)
+	if ok {
+		timeAdded, ok := value.(time.Time)
+		if ok && timeAdded.Add(b.expiryInterval).After(time.Now()) {
+			logger.Infof("Rejecting orderer %s", orderer.URL())
+			return false
+		}
+		b.greylistURLs.Delete(ordererAddress)
+	}
+
+	return true
+}
+
+// Filter returns the orderers that are not greylisted. All the orderers are returned
+// if they are all greylisted, since they may have recovered in the meantime.
+func (b *Filter) Filter(orderers []fab.Orderer) []fab.Orderer {
+	var accepted []fab.Orderer
+	for _, o := range orderers {
+		if b.Accept(o) {
+			accepted = append(accepted, o)
+		}
+	}
+	if len(accepted) == 0 {
+		logger.Debugf("All orderers are greylisted, trying all of them")
+		return orderers
+	}
+	return accepted
+}
+
+// Greylist the orderer URL of the given error
+func (b *Filter) Greylist(err error) {
+	s, ok := status.FromError(err)
+	if !ok {
+		return
+	}
+	if ok, ordererURL := required(s); ok && ordererURL != "" {
+		logger.Infof("Greylisting orderer %s", ordererURL)
+		b.greylistURLs.Store(ordererURL, time.Now())
+	}
+}
+
+// required decides whether the given status error warrants a greylist
+// on the orderer causing the error
+func required(s *status.Status) (bool, string) {
+	if s.Group == status.OrdererClientStatus && s.Code == status.ConnectionFailed.ToInt32() {
+		return true, ordererURLFromConnectionFailedStatus(s.Details)
+	}
+	return false, ""
+}
+
+// ordererURLFromConnectionFailedStatus extracts the orderer url from the status error
+// details
+func ordererURLFromConnectionFailedStatus(details []interface{}) string {
+	if len(details) != 0 {
+		url, ok := details[0].(string)
+		if ok {
+			return urlutil.ToAddress(url)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,71 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package greylist
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGreylistFilter(t *testing.T) {
+	expiryPeriod := time.Second * 2
+	goodOrderers := createMockOrderers(0, 10)
+	badOrderers := createMockOrderers(10, 20)
+
+	f := New(expiryPeriod)
+	for index, badOrderer := range badOrderers {
+		f.Greylist(connectionFailedStatus(badOrderer.URL()))
+		assert.False(t, f.Accept(badOrderer), "Expected bad orderer to be greylisted")
+		assert.True(t, f.Accept(goodOrderers[index]), "Expected good orderer to be accepted")
+	}
+
+	assert.Equal(t, goodOrderers, f.Filter(append(goodOrderers, badOrderers...)), "Expected bad orderers to be filtered")
+	assert.Equal(t, badOrderers, f.Filter(badOrderers), "Expected all orderers when all are greylisted")
+
+	time.Sleep(expiryPeriod)
+	for index, badOrderer := range badOrderers {
+		assert.True(t, f.Accept(badOrderer), "Expected bad orderer to be accepted after expiry period")
+		assert.True(t, f.Accept(goodOrderers[index]), "Expected good orderer to be accepted")
+	}
+}
+
+func TestGreylistInvalidErr(t *testing.T) {
+	f := New(time.Microsecond * 1)
+	f.Greylist(fmt.Errorf("test"))
+
+	ok, url := required(status.New(status.OrdererServerStatus, status.OK.ToInt32(), "", nil))
+	assert.False(t, ok)
+	assert.Empty(t, url)
+
+	ok, url = required(status.New(status.EndorserClientStatus, status.ConnectionFailed.ToInt32(), "", []interface{}{"peer"}))
+	assert.False(t, ok)
+	assert.Empty(t, url)
+
+	ok, url = required(status.New(status.OrdererClientStatus, status.ConnectionFailed.ToInt32(), "", nil))
+	assert.True(t, ok)
+	assert.Empty(t, url)
+}
+
+func connectionFailedStatus(url string) error {
+	return status.New(status.OrdererClientStatus, status.ConnectionFailed.ToInt32(),
+		"test", []interface{}{url})
+}
+
+func createMockOrderers(fromIndex int, toIndex int) []fab.Orderer {
+	var mockOrderers []fab.Orderer
+	for i := fromIndex; i < toIndex; i++ {
+		mockOrderers = append(mockOrderers, mocks.NewMockOrderer("grpcs://myOrderer.org:"+strconv.Itoa(i), nil))
+	}
+	return mockOrderers
+}
@@ -0,0 +1,38 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package lbp provides the load-balance policies that select the orderer a transaction is broadcast to.
+package lbp
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/logging"
+)
+
+var logger = logging.NewLogger("fabric_sdk_go")
+
+// LoadBalancePolicy orders a set of orderers. A transaction is broadcast to the
+// first orderer and the following ones are tried in turn if it fails.
+type LoadBalancePolicy interface {
+	Order(orderers []fab.Orderer) []fab.Orderer
+}
+
+// New returns the load-balance policy of the given orderer selection strategy.
+// The preferred orderer addresses are only used by the Preferred strategy.
+func New(strategy core.OrdererSelectionStrategy, preferred []string) LoadBalancePolicy {
+	switch strategy {
+	case core.RoundRobinOrdererSelection:
+		return NewRoundRobin()
+	case core.PreferredOrdererSelection:
+		return NewPreferred(preferred...)
+	case core.RandomOrdererSelection, "":
+		return NewRandom()
+	default:
+		logger.Warnf("Unknown orderer selection strategy [%s], using random", strategy)
+		return NewRandom()
+	}
+}
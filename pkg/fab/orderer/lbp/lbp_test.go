@@ -0,0 +1,104 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lbp
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	fabmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+)
+
+func TestRandom(t *testing.T) {
+	lbp := NewRandom()
+
+	assert.Empty(t, lbp.Order([]fab.Orderer{}), "expecting no orderers with empty set of orderers")
+
+	orderers := newMockOrderers(10)
+
+	// Invoke a number of times and make sure it doesn't start with the same orderer each time
+	differentOrdererChosen := false
+	var lastOrdererChosen fab.Orderer
+	for i := 0; i < 10; i++ {
+		ordered := lbp.Order(orderers)
+		assert.ElementsMatch(t, orderers, ordered, "expecting all orderers to be returned")
+		if lastOrdererChosen != nil && ordered[0] != lastOrdererChosen {
+			differentOrdererChosen = true
+			break
+		}
+		lastOrdererChosen = ordered[0]
+	}
+
+	if !differentOrdererChosen {
+		t.Fatalf("the same orderer was chosen every time")
+	}
+}
+
+func TestRoundRobin(t *testing.T) {
+	lbp := NewRoundRobin()
+
+	assert.Empty(t, lbp.Order([]fab.Orderer{}), "expecting no orderers with empty set of orderers")
+
+	orderers := newMockOrderers(10)
+
+	// Invoke a number of times and make sure it starts with each one consecutively
+	lastIndexChosen := -1
+	for i := 0; i < len(orderers); i++ {
+		ordered := lbp.Order(orderers)
+		assert.Len(t, ordered, len(orderers))
+
+		chosenIndex := findIndex(orderers, ordered[0])
+		if lastIndexChosen >= 0 {
+			assert.Equal(t, (lastIndexChosen+1)%len(orderers), chosenIndex, "expecting the next orderer to be chosen")
+		}
+		for j, o := range ordered {
+			assert.Equal(t, orderers[(chosenIndex+j)%len(orderers)], o, "expecting the others to follow in order")
+		}
+		lastIndexChosen = chosenIndex
+	}
+}
+
+func TestPreferred(t *testing.T) {
+	orderers := newMockOrderers(5)
+	lbp := NewPreferred("grpcs://orderer_3:7050", "orderer_1:7050", "unknown:7050")
+
+	for i := 0; i < 10; i++ {
+		ordered := lbp.Order(orderers)
+		assert.ElementsMatch(t, orderers, ordered, "expecting all orderers to be returned")
+		assert.Equal(t, orderers[3], ordered[0], "expecting the first preferred orderer first")
+		assert.Equal(t, orderers[1], ordered[1], "expecting the second preferred orderer second")
+	}
+}
+
+func TestNew(t *testing.T) {
+	assert.IsType(t, &Random{}, New("", nil))
+	assert.IsType(t, &Random{}, New(core.RandomOrdererSelection, nil))
+	assert.IsType(t, &RoundRobin{}, New(core.RoundRobinOrdererSelection, nil))
+	assert.IsType(t, &Preferred{}, New(core.PreferredOrdererSelection, []string{"orderer_1:7050"}))
+	assert.IsType(t, &Random{}, New("unknown", nil))
+}
+
+func findIndex(orderers []fab.Orderer, orderer fab.Orderer) int {
+	for i, o := range orderers {
+		if orderer == o {
+			return i
+		}
+	}
+	panic("orderer does not exist in list of orderers")
+}
+
+func newMockOrderers(numOrderers int) []fab.Orderer {
+	var orderers []fab.Orderer
+	for i := 0; i < numOrderers; i++ {
+		orderers = append(orderers, fabmocks.NewMockOrderer(fmt.Sprintf("grpc://orderer_%d:7050", i), nil))
+	}
+	return orderers
+}
@@ -0,0 +1,51 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lbp
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/urlutil"
+)
+
+// Preferred implements a load-balance policy that prefers the given orderers,
+// e.g. the orderer of the local organization
+type Preferred struct {
+	addresses []string
+	others    *Random
+}
+
+// NewPreferred returns a new Preferred load-balance policy. The orderers with the
+// given addresses are tried first, in the given order; the others follow in random order.
+func NewPreferred(addresses ...string) *Preferred {
+	var normalized []string
+	for _, address := range addresses {
+		normalized = append(normalized, urlutil.ToAddress(address))
+	}
+	return &Preferred{addresses: normalized, others: NewRandom()}
+}
+
+// Order returns the preferred orderers followed by the others in random order
+func (lbp *Preferred) Order(orderers []fab.Orderer) []fab.Orderer {
+	var ordered []fab.Orderer
+	chosen := make([]bool, len(orderers))
+	for _, address := range lbp.addresses {
+		for i, o := range orderers {
+			if !chosen[i] && urlutil.ToAddress(o.URL()) == address {
+				ordered = append(ordered, o)
+				chosen[i] = true
+			}
+		}
+	}
+
+	var others []fab.Orderer
+	for i, o := range orderers {
+		if !chosen[i] {
+			others = append(others, o)
+		}
+	}
+	return append(ordered, lbp.others.Order(others)...)
+}
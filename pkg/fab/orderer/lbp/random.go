@@ -0,0 +1,31 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lbp
+
+import (
+	"math/rand"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+)
+
+// Random implements a random load-balance policy
+type Random struct {
+}
+
+// NewRandom returns a new Random load-balance policy
+func NewRandom() *Random {
+	return &Random{}
+}
+
+// Order returns the orderers in random order
+func (lbp *Random) Order(orderers []fab.Orderer) []fab.Orderer {
+	ordered := make([]fab.Orderer, len(orderers))
+	for i, index := range rand.Perm(len(orderers)) {
+		ordered[i] = orderers[index]
+	}
+	return ordered
+}
@@ -0,0 +1,51 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lbp
+
+import (
+	"math/rand"
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+)
+
+// RoundRobin implements a round-robin load-balance policy
+type RoundRobin struct {
+	sync.Mutex
+	index int
+}
+
+// NewRoundRobin returns a new RoundRobin load-balance policy
+func NewRoundRobin() *RoundRobin {
+	return &RoundRobin{
+		index: -1,
+	}
+}
+
+// Order returns the orderers starting with the one following the first orderer of the previous call
+func (lbp *RoundRobin) Order(orderers []fab.Orderer) []fab.Orderer {
+	if len(orderers) == 0 {
+		return nil
+	}
+
+	lbp.Lock()
+	if lbp.index < 0 {
+		// First time - start at a random index
+		lbp.index = rand.Intn(len(orderers))
+	} else {
+		lbp.index++
+	}
+	if lbp.index >= len(orderers) {
+		lbp.index = 0
+	}
+	start := lbp.index
+	lbp.Unlock()
+
+	logger.Debugf("Starting at orderer index %d", start)
+
+	return append(append([]fab.Orderer{}, orderers[start:]...), orderers[:start]...)
+}
@@ -208,12 +208,13 @@ func (o *Orderer) URL() string {
 }
 
 // SendBroadcast Send the created transaction to Orderer.
-func (o *Orderer) SendBroadcast(envelope *fab.SignedEnvelope) (*common.Status, error) {
-	return o.sendBroadcast(envelope, o.secured)
+// The broadcast is aborted when the given context is cancelled or its deadline expires.
+func (o *Orderer) SendBroadcast(reqCtx grpcContext.Context, envelope *fab.SignedEnvelope) (*common.Status, error) {
+	return o.sendBroadcast(reqCtx, envelope, o.secured)
 }
 
 // SendBroadcast Send the created transaction to Orderer.
-func (o *Orderer) sendBroadcast(envelope *fab.SignedEnvelope, secured bool) (*common.Status, error) {
+func (o *Orderer) sendBroadcast(reqCtx grpcContext.Context, envelope *fab.SignedEnvelope, secured bool) (*common.Status, error) {
 	var grpcOpts []grpc.DialOption
 	grpcOpts = append(grpcOpts, o.grpcDialOption...)
 	if secured {
@@ -222,14 +223,13 @@ func (o *Orderer) sendBroadcast(envelope *fab.SignedEnvelope, secured bool) (*co
 		grpcOpts = append(grpcOpts, grpc.WithInsecure())
 	}
 
-	ctx := grpcContext.Background()
-	ctx, cancel := grpcContext.WithTimeout(ctx, o.dialTimeout)
+	ctx, cancel := grpcContext.WithTimeout(reqCtx, o.dialTimeout)
 	defer cancel()
 
 	conn, err := grpc.DialContext(ctx, o.url, grpcOpts...)
 
 	if err != nil {
-		return nil, status.New(status.OrdererClientStatus, status.ConnectionFailed.ToInt32(), err.Error(), nil)
+		return nil, status.New(status.OrdererClientStatus, status.ConnectionFailed.ToInt32(), err.Error(), []interface{}{o.url})
 	}
 	defer conn.Close()
 	broadcastStream, err := ab.NewAtomicBroadcastClient(conn).Broadcast(ctx)
@@ -239,10 +239,10 @@ func (o *Orderer) sendBroadcast(envelope *fab.SignedEnvelope, secured bool) (*co
 			err = status.NewFromGRPCStatus(rpcStatus)
 		}
 		logger.Error("NewAtomicBroadcastClient failed, cause : ", err)
-		if secured && o.allowInsecure {
+		if secured && o.allowInsecure && reqCtx.Err() == nil {
 			//If secured mode failed and allow insecure is enabled then retry in insecure mode
 			logger.Debug("Secured sendBroadcast failed, attempting insecured")
-			return o.sendBroadcast(envelope, false)
+			return o.sendBroadcast(reqCtx, envelope, false)
 		}
 		return nil, errors.Wrap(err, "NewAtomicBroadcastClient failed")
 	}
@@ -289,14 +289,15 @@ func (o *Orderer) sendBroadcast(envelope *fab.SignedEnvelope, secured bool) (*co
 // SendDeliver sends a deliver request to the ordering service and returns the
 // blocks requested
 // envelope: contains the seek request for blocks
-func (o *Orderer) SendDeliver(envelope *fab.SignedEnvelope) (chan *common.Block, chan error, grpcContext.CancelFunc) {
-	return o.sendDeliver(envelope, o.secured)
+// The delivery is aborted when the given context is cancelled or its deadline expires.
+func (o *Orderer) SendDeliver(reqCtx grpcContext.Context, envelope *fab.SignedEnvelope) (chan *common.Block, chan error, grpcContext.CancelFunc) {
+	return o.sendDeliver(reqCtx, envelope, o.secured)
 }
 
 // SendDeliver sends a deliver request to the ordering service and returns the
 // blocks requested
 // envelope: contains the seek request for blocks
-func (o *Orderer) sendDeliver(envelope *fab.SignedEnvelope, secured bool) (chan *common.Block, chan error, grpcContext.CancelFunc) {
+func (o *Orderer) sendDeliver(reqCtx grpcContext.Context, envelope *fab.SignedEnvelope, secured bool) (chan *common.Block, chan error, grpcContext.CancelFunc) {
 	responses := make(chan *common.Block)
 	errs := make(chan error, 1)
 
@@ -309,8 +310,7 @@ func (o *Orderer) sendDeliver(envelope *fab.SignedEnvelope, secured bool) (chan
 		grpcOpts = append(o.grpcDialOption, grpc.WithInsecure())
 	}
 
-	ctx := grpcContext.Background()
-	ctx, cancel := grpcContext.WithTimeout(ctx, o.dialTimeout)
+	ctx, cancel := grpcContext.WithTimeout(reqCtx, o.dialTimeout)
 
 	conn, err := grpc.DialContext(ctx, o.url, grpcOpts...)
 	if err != nil {
@@ -322,12 +322,12 @@ func (o *Orderer) sendDeliver(envelope *fab.SignedEnvelope, secured bool) (chan
 	broadcastStream, err := ab.NewAtomicBroadcastClient(conn).Deliver(ctx)
 	if err != nil {
 		logger.Error("NewAtomicBroadcastClient failed, cause : ", err)
-		if secured && o.allowInsecure {
+		if secured && o.allowInsecure && reqCtx.Err() == nil {
 			//If secured mode failed and allow insecure is enabled then retry in insecure mode
 			logger.Debug("Secured sendBroadcast failed, attempting insecured")
 
 			cancel()
-			return o.sendDeliver(envelope, false)
+			return o.sendDeliver(reqCtx, envelope, false)
 		}
 		errs <- errors.Wrap(err, "NewAtomicBroadcastClient failed")
 		return responses, errs, cancel
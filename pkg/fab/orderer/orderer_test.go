@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package orderer
 
 import (
+	"context"
 	"crypto/x509"
 	"fmt"
 	"net"
@@ -56,7 +57,7 @@ func TestSendDeliverHappy(t *testing.T) {
 
 	orderer, _ := New(mocks.NewMockConfig(), WithURL(addr), FromOrdererConfig(ordererConfig))
 	// Test deliver happy path
-	blocks, errs, cancel := orderer.SendDeliver(&fab.SignedEnvelope{})
+	blocks, errs, cancel := orderer.SendDeliver(context.Background(), &fab.SignedEnvelope{})
 	defer cancel()
 
 	select {
@@ -81,7 +82,7 @@ func TestSendDeliverErr(t *testing.T) {
 	// Test deliver with deliver error from OS
 	testError := errors.New("test error")
 	mockServer.DeliverError = testError
-	blocks, errs, cancel := orderer.SendDeliver(&fab.SignedEnvelope{})
+	blocks, errs, cancel := orderer.SendDeliver(context.Background(), &fab.SignedEnvelope{})
 	defer cancel()
 
 	select {
@@ -100,7 +101,7 @@ func TestSendDeliver(t *testing.T) {
 	orderer, _ := New(mocks.NewMockConfig(), WithURL(testOrdererURL+"invalid-test"))
 
 	// Test deliver happy path
-	blocks, errs, cancel := orderer.SendDeliver(&fab.SignedEnvelope{})
+	blocks, errs, cancel := orderer.SendDeliver(context.Background(), &fab.SignedEnvelope{})
 	defer cancel()
 
 	select {
@@ -196,7 +197,7 @@ func TestSendBroadcast(t *testing.T) {
 	_, addr := startMockServer(t, grpcServer)
 	ordererConfig := getGRPCOpts(addr, true, false)
 	orderer, _ := New(mocks.NewMockConfig(), WithURL(addr), FromOrdererConfig(ordererConfig), WithInsecure())
-	_, err := orderer.SendBroadcast(&fab.SignedEnvelope{})
+	_, err := orderer.SendBroadcast(context.Background(), &fab.SignedEnvelope{})
 
 	if err != nil {
 		t.Fatalf("Test SendBroadcast was not supposed to fail")
@@ -204,7 +205,7 @@ func TestSendBroadcast(t *testing.T) {
 
 	orderer, _ = New(mocks.NewMockConfig(), WithURL(testOrdererURL+"Test"), FromOrdererConfig(ordererConfig))
 	orderer.dialTimeout = 15
-	_, err = orderer.SendBroadcast(&fab.SignedEnvelope{})
+	_, err = orderer.SendBroadcast(context.Background(), &fab.SignedEnvelope{})
 	if err == nil {
 		t.Fatalf("Expected error 'Orderer Client Status 2 context deadline exceeded'")
 	}
@@ -230,7 +231,7 @@ func TestSendDeliverServerBadResponse(t *testing.T) {
 	addr := startCustomizedMockServer(t, testOrdererURL, grpcServer, &broadcastServer)
 	orderer, _ := New(mocks.NewMockConfig(), WithURL("grpc://"+addr), WithInsecure())
 
-	blocks, errors, cancel := orderer.SendDeliver(&fab.SignedEnvelope{})
+	blocks, errors, cancel := orderer.SendDeliver(context.Background(), &fab.SignedEnvelope{})
 	defer cancel()
 
 	select {
@@ -261,7 +262,7 @@ func TestSendDeliverServerSuccessResponse(t *testing.T) {
 
 	orderer, _ := New(mocks.NewMockConfig(), WithURL("grpc://"+addr), WithInsecure())
 
-	blocks, errors, cancel := orderer.SendDeliver(&fab.SignedEnvelope{})
+	blocks, errors, cancel := orderer.SendDeliver(context.Background(), &fab.SignedEnvelope{})
 	defer cancel()
 
 	select {
@@ -287,7 +288,7 @@ func TestSendDeliverFailure(t *testing.T) {
 	addr := startCustomizedMockServer(t, testOrdererURL, grpcServer, &broadcastServer)
 	orderer, _ := New(mocks.NewMockConfig(), WithURL("grpc://"+addr), WithInsecure())
 
-	blocks, errors, cancel := orderer.SendDeliver(&fab.SignedEnvelope{})
+	blocks, errors, cancel := orderer.SendDeliver(context.Background(), &fab.SignedEnvelope{})
 	defer cancel()
 
 	select {
@@ -313,7 +314,7 @@ func TestSendBroadcastServerBadResponse(t *testing.T) {
 	addr := startCustomizedMockServer(t, testOrdererURL, grpcServer, &broadcastServer)
 	orderer, _ := New(mocks.NewMockConfig(), WithURL("grpc://"+addr), WithInsecure())
 
-	_, err := orderer.SendBroadcast(&fab.SignedEnvelope{})
+	_, err := orderer.SendBroadcast(context.Background(), &fab.SignedEnvelope{})
 
 	if err == nil {
 		t.Fatalf("Expected error")
@@ -335,7 +336,7 @@ func TestSendBroadcastError(t *testing.T) {
 	addr := startCustomizedMockServer(t, testOrdererURL, grpcServer, &broadcastServer)
 	orderer, _ := New(mocks.NewMockConfig(), WithURL("grpc://"+addr), WithInsecure())
 
-	statusCode, err := orderer.SendBroadcast(&fab.SignedEnvelope{})
+	statusCode, err := orderer.SendBroadcast(context.Background(), &fab.SignedEnvelope{})
 
 	if err == nil || statusCode != nil {
 		t.Fatalf("expected Send Broadcast to fail with error, but got %s", err)
@@ -359,7 +360,7 @@ func TestBroadcastBadDial(t *testing.T) {
 	orderer.grpcDialOption = append(orderer.grpcDialOption, grpc.WithBlock())
 	orderer.secured = true
 	orderer.allowInsecure = true
-	_, err = orderer.SendBroadcast(&fab.SignedEnvelope{})
+	_, err = orderer.SendBroadcast(context.Background(), &fab.SignedEnvelope{})
 	assert.NotNil(t, err)
 
 	if err == nil || !strings.Contains(err.Error(), "CONNECTION_FAILED") {
@@ -435,7 +436,7 @@ func getGRPCOpts(addr string, failFast bool, keepAliveOptions bool) *core.Ordere
 func TestForDeadlineExceeded(t *testing.T) {
 	orderer, _ := New(mocks.NewMockConfig(), WithURL(testOrdererURL+"Test"))
 	orderer.dialTimeout = 1 * time.Second
-	_, err := orderer.SendBroadcast(&fab.SignedEnvelope{})
+	_, err := orderer.SendBroadcast(context.Background(), &fab.SignedEnvelope{})
 	if err == nil || !strings.HasPrefix(err.Error(), "NewAtomicBroadcastClient") {
 		t.Fatalf("Test SendBroadcast was supposed to fail with 'gRPC Transport Status Code: (4) DeadlineExceeded', instead it failed with [%s] error", err)
 	}
@@ -450,7 +451,7 @@ func TestSendDeliverDefaultOpts(t *testing.T) {
 		fmt.Printf("%v %v %v\n", i, &v, reflect.TypeOf(v))
 
 	}
-	_, err := orderer.SendBroadcast(&fab.SignedEnvelope{})
+	_, err := orderer.SendBroadcast(context.Background(), &fab.SignedEnvelope{})
 	if err == nil {
 		t.Fatalf("Expected error 'Orderer Client Status 2 context deadline exceeded' %v", err)
 	}
@@ -462,7 +463,7 @@ func TestSendDeliverDefaultOpts(t *testing.T) {
 	orderer, _ = New(mocks.NewMockConfig(), WithURL("grpc://"+addr), WithInsecure())
 	orderer.dialTimeout = 5 * time.Second
 	// Test deliver happy path
-	blocks, errs, cancel := orderer.SendDeliver(&fab.SignedEnvelope{})
+	blocks, errs, cancel := orderer.SendDeliver(context.Background(), &fab.SignedEnvelope{})
 	defer cancel()
 
 	select {
@@ -483,7 +484,7 @@ func TestForGRPCErrorsWithKeepAliveOpts(t *testing.T) {
 	ordererConfig := getGRPCOpts("grpc://"+testOrdererURL+"Test", true, true)
 	orderer, _ := New(mocks.NewMockConfig(), WithURL(testOrdererURL+"Test"), FromOrdererConfig(ordererConfig))
 	orderer.dialTimeout = 5 * time.Second
-	_, err := orderer.SendBroadcast(&fab.SignedEnvelope{})
+	_, err := orderer.SendBroadcast(context.Background(), &fab.SignedEnvelope{})
 	if err == nil {
 		t.Fatalf("Expected error 'Orderer Client Status 2 context deadline exceeded'")
 	}
@@ -496,7 +497,7 @@ func TestForGRPCErrorsWithKeepAliveOpts(t *testing.T) {
 	ordererConfig = getGRPCOpts(testOrdererURL+"Test", false, true)
 	orderer, _ = New(mocks.NewMockConfig(), WithURL(testOrdererURL+"Test"), FromOrdererConfig(ordererConfig))
 	orderer.dialTimeout = 5 * time.Second
-	_, err = orderer.SendBroadcast(&fab.SignedEnvelope{})
+	_, err = orderer.SendBroadcast(context.Background(), &fab.SignedEnvelope{})
 	if err == nil {
 		t.Fatalf("Expected error 'Orderer Client Status 2 context deadline exceeded'")
 	}
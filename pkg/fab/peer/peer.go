@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package peer
 
 import (
+	grpccontext "context"
 	"encoding/pem"
 	"fmt"
 
@@ -250,8 +251,8 @@ func (p *Peer) URL() string {
 }
 
 // ProcessTransactionProposal sends the created proposal to peer for endorsement.
-func (p *Peer) ProcessTransactionProposal(proposal fab.ProcessProposalRequest) (*fab.TransactionProposalResponse, error) {
-	return p.processor.ProcessTransactionProposal(proposal)
+func (p *Peer) ProcessTransactionProposal(reqCtx grpccontext.Context, proposal fab.ProcessProposalRequest) (*fab.TransactionProposalResponse, error) {
+	return p.processor.ProcessTransactionProposal(reqCtx, proposal)
 }
 
 func (p *Peer) String() string {
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package peer
 
 import (
+	"context"
 	"encoding/pem"
 	"fmt"
 	"io/ioutil"
@@ -199,10 +200,10 @@ func TestProposalProcessorSendProposal(t *testing.T) {
 	tp := mockProcessProposalRequest()
 	tpr := fab.TransactionProposalResponse{Endorser: "example.com", Status: 99, ProposalResponse: nil}
 
-	proc.EXPECT().ProcessTransactionProposal(tp).Return(&tpr, nil)
+	proc.EXPECT().ProcessTransactionProposal(gomock.Any(), tp).Return(&tpr, nil)
 
 	p := Peer{processor: proc, name: "", roles: nil}
-	tpr1, err := p.ProcessTransactionProposal(tp)
+	tpr1, err := p.ProcessTransactionProposal(context.Background(), tp)
 
 	if err != nil || !reflect.DeepEqual(&tpr, tpr1) {
 		t.Fatalf("Peer didn't proxy proposal processing")
@@ -78,10 +78,10 @@ func newPeerEndorser(endorseReq *peerEndorserRequest) (*peerEndorser, error) {
 }
 
 // ProcessTransactionProposal sends the transaction proposal to a peer and returns the response.
-func (p *peerEndorser) ProcessTransactionProposal(request fab.ProcessProposalRequest) (*fab.TransactionProposalResponse, error) {
+func (p *peerEndorser) ProcessTransactionProposal(reqCtx grpccontext.Context, request fab.ProcessProposalRequest) (*fab.TransactionProposalResponse, error) {
 	logger.Debugf("Processing proposal using endorser: %s", p.target)
 
-	proposalResponse, err := p.sendProposal(request, p.secured)
+	proposalResponse, err := p.sendProposal(reqCtx, request, p.secured)
 	if err != nil {
 		tpr := fab.TransactionProposalResponse{Endorser: p.target}
 		return &tpr, errors.Wrapf(err, "Transaction processing for endorser [%s]", p.target)
@@ -95,7 +95,7 @@ func (p *peerEndorser) ProcessTransactionProposal(request fab.ProcessProposalReq
 	return &tpr, nil
 }
 
-func (p *peerEndorser) conn(secured bool) (*grpc.ClientConn, error) {
+func (p *peerEndorser) conn(reqCtx grpccontext.Context, secured bool) (*grpc.ClientConn, error) {
 	// Establish connection to Ordering Service
 	var grpcOpts []grpc.DialOption
 	if secured {
@@ -104,8 +104,7 @@ func (p *peerEndorser) conn(secured bool) (*grpc.ClientConn, error) {
 		grpcOpts = append(p.grpcDialOption, grpc.WithInsecure())
 	}
 
-	ctx := grpccontext.Background()
-	ctx, cancel := grpccontext.WithTimeout(ctx, p.dialTimeout)
+	ctx, cancel := grpccontext.WithTimeout(reqCtx, p.dialTimeout)
 	defer cancel()
 
 	return grpc.DialContext(ctx, p.target, grpcOpts...)
@@ -115,26 +114,26 @@ func (p *peerEndorser) releaseConn(conn *grpc.ClientConn) {
 	conn.Close()
 }
 
-func (p *peerEndorser) sendProposal(proposal fab.ProcessProposalRequest, secured bool) (*pb.ProposalResponse, error) {
-	conn, err := p.conn(secured)
+func (p *peerEndorser) sendProposal(reqCtx grpccontext.Context, proposal fab.ProcessProposalRequest, secured bool) (*pb.ProposalResponse, error) {
+	conn, err := p.conn(reqCtx, secured)
 	if err != nil {
-		if secured && p.allowInsecure {
+		if secured && p.allowInsecure && reqCtx.Err() == nil {
 			//If secured mode failed and allow insecure is enabled then retry in insecure mode
 			logger.Debug("Secured NewEndorserClient failed, attempting insecured")
-			return p.sendProposal(proposal, false)
+			return p.sendProposal(reqCtx, proposal, false)
 		}
 		return nil, status.New(status.EndorserClientStatus, status.ConnectionFailed.ToInt32(), err.Error(), []interface{}{p.target})
 	}
 	defer p.releaseConn(conn)
 
 	endorserClient := pb.NewEndorserClient(conn)
-	resp, err := endorserClient.ProcessProposal(grpccontext.Background(), proposal.SignedProposal)
+	resp, err := endorserClient.ProcessProposal(reqCtx, proposal.SignedProposal)
 	if err != nil {
 		logger.Error("NewEndorserClient failed, cause : ", err)
-		if secured && p.allowInsecure {
+		if secured && p.allowInsecure && reqCtx.Err() == nil {
 			//If secured mode failed and allow insecure is enabled then retry in insecure mode
 			logger.Debug("Secured NewEndorserClient failed, attempting insecured")
-			return p.sendProposal(proposal, false)
+			return p.sendProposal(reqCtx, proposal, false)
 		}
 
 		rpcStatus, ok := grpcstatus.FromError(err)
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package peer
 
 import (
+	"context"
 	"crypto/x509"
 	"fmt"
 	"net"
@@ -290,7 +291,7 @@ func testProcessProposal(t *testing.T, url string) (*fab.TransactionProposalResp
 		t.Fatalf("Peer conn construction error (%v)", err)
 	}
 
-	return conn.ProcessTransactionProposal(mockProcessProposalRequest())
+	return conn.ProcessTransactionProposal(context.Background(), mockProcessProposalRequest())
 }
 
 func getPeerEndorserRequest(url string, cert *x509.Certificate, serverHostOverride string,
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package api
 
 import (
+	reqContext "context"
+
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
 	common "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
@@ -21,6 +23,8 @@ type Resource interface {
 	QueryChannels(peer fab.ProposalProcessor) (*pb.ChannelQueryResponse, error)
 	GenesisBlockFromOrderer(channelName string, orderer fab.Orderer) (*common.Block, error)
 	LastConfigFromOrderer(channelName string, orderer fab.Orderer) (*common.ConfigEnvelope, error)
+	BlockFromOrderer(reqCtx reqContext.Context, channelName string, orderer fab.Orderer, blockNumber uint64) (*common.Block, error)
+	NewestBlockFromOrderer(reqCtx reqContext.Context, channelName string, orderer fab.Orderer) (*common.Block, error)
 	JoinChannel(request JoinChannelRequest) error
 	SignChannelConfig(config []byte, signer context.Identity) (*common.ConfigSignature, error)
 }
@@ -55,6 +59,10 @@ type InstallChaincodeRequest struct {
 	Version string
 	// required - package (chaincode package type and bytes)
 	Package *CCPackage
+	// optional - chaincode deployment spec, installed as is instead of the package
+	DeploymentSpec *pb.ChaincodeDeploymentSpec
+	// optional - signed chaincode deployment package, installed instead of the package
+	SignedPackage *common.Envelope
 	// required - proposal processor list
 	Targets []fab.ProposalProcessor
 }
@@ -8,6 +8,8 @@ SPDX-License-Identifier: Apache-2.0
 package resource
 
 import (
+	reqContext "context"
+
 	"github.com/golang/protobuf/proto"
 	ab "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/orderer"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
@@ -18,7 +20,7 @@ import (
 )
 
 // block retrieves the block at the given position
-func (r *Resource) block(orderers []fab.Orderer, channel string, pos *ab.SeekPosition) (*common.Block, error) {
+func (r *Resource) block(reqCtx reqContext.Context, orderers []fab.Orderer, channel string, pos *ab.SeekPosition) (*common.Block, error) {
 	th, err := txn.NewHeader(r.clientContext, channel)
 	if err != nil {
 		return nil, errors.Wrap(err, "generating TX ID failed")
@@ -69,7 +71,7 @@ func (r *Resource) block(orderers []fab.Orderer, channel string, pos *ab.SeekPos
 		Data:   seekInfoBytes,
 	}
 
-	return txn.SendPayload(r.clientContext, &payload, orderers)
+	return txn.SendPayload(reqCtx, r.clientContext, &payload, orderers)
 }
 
 // newNewestSeekPosition returns a SeekPosition that requests the newest block
@@ -0,0 +1,171 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resource
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"io"
+	"regexp"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// ChaincodePackageInfo describes a chaincode deployment spec or signed chaincode deployment package.
+type ChaincodePackageInfo struct {
+	Name    string
+	Version string
+	Path    string
+	Type    pb.ChaincodeSpec_Type
+	// ID is the hash of the package that the peer reports in the ChaincodeInfo of the installed chaincode
+	ID []byte
+	// Signed is true for signed chaincode deployment packages
+	Signed bool
+	// Owners are the serialized identities of the owners that endorsed a signed chaincode deployment package
+	Owners [][]byte
+	// Files are the files of the code package
+	Files []ChaincodePackageFile
+}
+
+// ChaincodePackageFile is a file of a code package.
+type ChaincodePackageFile struct {
+	Name string
+	Size int64
+}
+
+// The chaincode name and version formats that the peer accepts
+var (
+	chaincodeNameRegExp    = regexp.MustCompile("^[a-zA-Z0-9]+([-_][a-zA-Z0-9]+)*$")
+	chaincodeVersionRegExp = regexp.MustCompile("^[A-Za-z0-9_.+-]+$")
+)
+
+// UnmarshalChaincodePackage unmarshals a chaincode package, as written by the peer CLI's package command.
+// The package is either a chaincode deployment spec or a signed chaincode deployment package, in which case
+// the signed package is returned along with the deployment spec it carries.
+//
+// The bytes of either message may unmarshal as the other, so a chaincode deployment spec is only accepted
+// with a valid chaincode name and version, and a signed package only with the CHAINCODE_PACKAGE header type.
+func UnmarshalChaincodePackage(raw []byte) (*pb.ChaincodeDeploymentSpec, *common.Envelope, error) {
+	if len(raw) == 0 {
+		return nil, nil, errors.New("chaincode package is empty")
+	}
+
+	cds := &pb.ChaincodeDeploymentSpec{}
+	if err := proto.Unmarshal(raw, cds); err == nil && isChaincodeDeploymentSpec(cds) {
+		return cds, nil, nil
+	}
+
+	signedPkg := &common.Envelope{}
+	if err := proto.Unmarshal(raw, signedPkg); err != nil {
+		return nil, nil, errors.Wrap(err, "chaincode package is neither a chaincode deployment spec nor a signed chaincode package")
+	}
+	_, cds, err := ExtractSignedCCPackage(signedPkg)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "chaincode package is neither a chaincode deployment spec nor a signed chaincode package")
+	}
+	return cds, signedPkg, nil
+}
+
+// isChaincodeDeploymentSpec returns true if the chaincode deployment spec has a valid chaincode name and version
+func isChaincodeDeploymentSpec(cds *pb.ChaincodeDeploymentSpec) bool {
+	chaincodeID := cds.GetChaincodeSpec().GetChaincodeId()
+	return chaincodeNameRegExp.MatchString(chaincodeID.GetName()) && chaincodeVersionRegExp.MatchString(chaincodeID.GetVersion())
+}
+
+// InspectChaincodePackage describes the chaincode package, as written by the peer CLI's package command.
+func InspectChaincodePackage(raw []byte) (*ChaincodePackageInfo, error) {
+	cds, signedPkg, err := UnmarshalChaincodePackage(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var signedCDS *pb.SignedChaincodeDeploymentSpec
+	if signedPkg != nil {
+		if signedCDS, _, err = ExtractSignedCCPackage(signedPkg); err != nil {
+			return nil, err
+		}
+	}
+
+	files, err := codePackageFiles(cds.CodePackage)
+	if err != nil {
+		return nil, err
+	}
+
+	chaincodeID := cds.ChaincodeSpec.ChaincodeId
+	info := &ChaincodePackageInfo{
+		Name:    chaincodeID.Name,
+		Version: chaincodeID.Version,
+		Path:    chaincodeID.Path,
+		Type:    cds.ChaincodeSpec.Type,
+		ID:      ChaincodePackageID(cds, signedCDS),
+		Signed:  signedCDS != nil,
+		Files:   files,
+	}
+	for _, endorsement := range signedCDS.GetOwnerEndorsements() {
+		info.Owners = append(info.Owners, endorsement.Endorser)
+	}
+	return info, nil
+}
+
+// ChaincodePackageID computes the hash of the chaincode deployment spec, or of the signed chaincode deployment
+// spec if provided, that the peer reports as the ID of the installed chaincode.
+func ChaincodePackageID(cds *pb.ChaincodeDeploymentSpec, signedCDS *pb.SignedChaincodeDeploymentSpec) []byte {
+	chaincodeID := cds.GetChaincodeSpec().GetChaincodeId()
+
+	codeHash := sha256.Sum256(cds.CodePackage)
+
+	hash := sha256.New()
+	hash.Write([]byte(chaincodeID.GetName()))
+	hash.Write([]byte(chaincodeID.GetVersion()))
+	metadataHash := hash.Sum(nil)
+
+	hash.Reset()
+	hash.Write(codeHash[:])
+	hash.Write(metadataHash)
+	if signedCDS != nil {
+		// The signed package ID includes the instantiation policy and the owners
+		signatureHash := sha256.New()
+		signatureHash.Write(signedCDS.InstantiationPolicy)
+		for _, endorsement := range signedCDS.OwnerEndorsements {
+			signatureHash.Write(endorsement.Endorser)
+		}
+		hash.Write(signatureHash.Sum(nil))
+	}
+	return hash.Sum(nil)
+}
+
+// codePackageFiles lists the files of the .tar.gz code package
+func codePackageFiles(code []byte) ([]ChaincodePackageFile, error) {
+	if len(code) == 0 {
+		return nil, nil
+	}
+
+	gzr, err := gzip.NewReader(bytes.NewReader(code))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read code package")
+	}
+	tr := tar.NewReader(gzr)
+
+	var files []ChaincodePackageFile
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read code package")
+		}
+		files = append(files, ChaincodePackageFile{Name: header.Name, Size: header.Size})
+	}
+	return files, nil
+}
@@ -0,0 +1,143 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resource
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+func newTestCodePackage(t *testing.T, files ...string) []byte {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for _, file := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: file, Size: int64(len(file)), Mode: 0644}); err != nil {
+			t.Fatalf("Failed to write code package: %s", err)
+		}
+		if _, err := tw.Write([]byte(file)); err != nil {
+			t.Fatalf("Failed to write code package: %s", err)
+		}
+	}
+	tw.Close()
+	gw.Close()
+	return buf.Bytes()
+}
+
+func newTestInspectCDS(t *testing.T) *pb.ChaincodeDeploymentSpec {
+	cds, err := CreateChaincodeDeploymentSpec(ChaincodeInstallRequest{
+		Name:    "examplecc",
+		Path:    "github.com/examplecc",
+		Version: "1",
+		Package: &ChaincodePackage{Type: pb.ChaincodeSpec_GOLANG, Code: newTestCodePackage(t, "src/github.com/examplecc/cc.go", "META-INF/statedb/couchdb/indexes/index.json")},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create chaincode deployment spec: %s", err)
+	}
+	return cds
+}
+
+func TestInspectChaincodePackage(t *testing.T) {
+	cds := newTestInspectCDS(t)
+
+	info, err := InspectChaincodePackage(marshalOrPanic(cds))
+	if err != nil {
+		t.Fatalf("Failed to inspect chaincode package: %s", err)
+	}
+	assert.Equal(t, "examplecc", info.Name)
+	assert.Equal(t, "1", info.Version)
+	assert.Equal(t, "github.com/examplecc", info.Path)
+	assert.Equal(t, pb.ChaincodeSpec_GOLANG, info.Type)
+	assert.False(t, info.Signed)
+	assert.Equal(t, []ChaincodePackageFile{
+		{Name: "src/github.com/examplecc/cc.go", Size: int64(len("src/github.com/examplecc/cc.go"))},
+		{Name: "META-INF/statedb/couchdb/indexes/index.json", Size: int64(len("META-INF/statedb/couchdb/indexes/index.json"))},
+	}, info.Files)
+
+	// The ID is the hash of the code hash and the hash of the chaincode name and version
+	codeHash := sha256.Sum256(cds.CodePackage)
+	metadataHash := sha256.Sum256([]byte("examplecc1"))
+	expectedID := sha256.Sum256(append(codeHash[:], metadataHash[:]...))
+	assert.Equal(t, expectedID[:], info.ID)
+}
+
+func TestInspectSignedChaincodePackage(t *testing.T) {
+	cds := newTestInspectCDS(t)
+	policy := cauthdsl.SignedByMspAdmin("Org1MSP")
+
+	pkg, err := CreateSignedCCPackage(newTestOwnerContext("Org1MSP"), cds, policy)
+	if err != nil {
+		t.Fatalf("Failed to create signed chaincode package: %s", err)
+	}
+
+	info, err := InspectChaincodePackage(marshalOrPanic(pkg))
+	if err != nil {
+		t.Fatalf("Failed to inspect chaincode package: %s", err)
+	}
+	assert.Equal(t, "examplecc", info.Name)
+	assert.True(t, info.Signed)
+	assert.Equal(t, [][]byte{[]byte("Org1MSPadmin")}, info.Owners)
+	assert.Len(t, info.Files, 2)
+
+	// The ID of the signed package includes the hash of the instantiation policy and the owners
+	codeHash := sha256.Sum256(cds.CodePackage)
+	metadataHash := sha256.Sum256([]byte("examplecc1"))
+	signatureHash := sha256.Sum256(append(marshalOrPanic(policy), []byte("Org1MSPadmin")...))
+	expectedID := sha256.Sum256(append(append(codeHash[:], metadataHash[:]...), signatureHash[:]...))
+	assert.Equal(t, expectedID[:], info.ID)
+}
+
+func TestUnmarshalChaincodePackage(t *testing.T) {
+	cds := newTestInspectCDS(t)
+
+	unmarshalled, signedPkg, err := UnmarshalChaincodePackage(marshalOrPanic(cds))
+	if err != nil {
+		t.Fatalf("Failed to unmarshal chaincode package: %s", err)
+	}
+	assert.Nil(t, signedPkg, "unexpected signed chaincode package")
+	assert.Equal(t, cds.CodePackage, unmarshalled.CodePackage)
+
+	_, _, err = UnmarshalChaincodePackage(nil)
+	assert.Error(t, err, "expected error for empty chaincode package")
+
+	_, _, err = UnmarshalChaincodePackage([]byte("invalid"))
+	assert.Error(t, err, "expected error for invalid chaincode package")
+
+	// Deployment specs without chaincode ID are rejected
+	_, _, err = UnmarshalChaincodePackage(marshalOrPanic(&pb.ChaincodeDeploymentSpec{CodePackage: []byte("code")}))
+	assert.Error(t, err, "expected error for chaincode package without chaincode ID")
+
+	// Signed packages are only accepted with the chaincode package header type
+	pkg, err := CreateSignedCCPackage(newTestOwnerContext("Org1MSP"), cds, cauthdsl.SignedByMspAdmin("Org1MSP"))
+	if err != nil {
+		t.Fatalf("Failed to create signed chaincode package: %s", err)
+	}
+	unmarshalled, signedPkg, err = UnmarshalChaincodePackage(marshalOrPanic(pkg))
+	if err != nil {
+		t.Fatalf("Failed to unmarshal signed chaincode package: %s", err)
+	}
+	assert.NotNil(t, signedPkg, "expected signed chaincode package")
+	assert.Equal(t, cds.CodePackage, unmarshalled.CodePackage)
+
+	payload := &common.Payload{}
+	if err := proto.Unmarshal(pkg.Payload, payload); err != nil {
+		t.Fatalf("unmarshal of signed chaincode package payload failed: %s", err)
+	}
+	payload.Header.ChannelHeader = marshalOrPanic(&common.ChannelHeader{Type: int32(common.HeaderType_ENDORSER_TRANSACTION)})
+	_, _, err = UnmarshalChaincodePackage(marshalOrPanic(&common.Envelope{Payload: marshalOrPanic(payload)}))
+	assert.Error(t, err, "expected error for envelope that isn't a chaincode package")
+}
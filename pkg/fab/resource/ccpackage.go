@@ -0,0 +1,194 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resource
+
+import (
+	"bytes"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/crypto"
+	fcutils "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	protos_utils "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+// CreateSignedCCPackage creates a signed chaincode deployment package of the deployment spec with
+// the instantiation policy of the chaincode. The package is endorsed and signed by the owner of the context.
+func CreateSignedCCPackage(ctx context.Client, cds *pb.ChaincodeDeploymentSpec, instantiationPolicy *common.SignaturePolicyEnvelope) (*common.Envelope, error) {
+	if cds == nil || cds.ChaincodeSpec == nil || cds.ChaincodeSpec.ChaincodeId == nil {
+		return nil, errors.New("chaincode deployment spec is required")
+	}
+	if instantiationPolicy == nil {
+		return nil, errors.New("instantiation policy is required")
+	}
+
+	cdsBytes, err := proto.Marshal(cds)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of chaincode deployment spec failed")
+	}
+	policyBytes, err := proto.Marshal(instantiationPolicy)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of instantiation policy failed")
+	}
+
+	signedCDS := &pb.SignedChaincodeDeploymentSpec{
+		ChaincodeDeploymentSpec: cdsBytes,
+		InstantiationPolicy:     policyBytes,
+	}
+	return signCCPackage(ctx, signedCDS)
+}
+
+// SignCCPackage adds the endorsement of the owner of the context to the signed chaincode deployment package.
+// The returned package is signed by the owner of the context.
+func SignCCPackage(ctx context.Client, pkg *common.Envelope) (*common.Envelope, error) {
+	signedCDS, _, err := ExtractSignedCCPackage(pkg)
+	if err != nil {
+		return nil, err
+	}
+	return signCCPackage(ctx, signedCDS)
+}
+
+// MergeSignedCCPackages merges the owner endorsements of packages of the same chaincode deployment spec
+// and instantiation policy, that were endorsed separately by the owners, into a single package for install.
+func MergeSignedCCPackages(pkgs ...*common.Envelope) (*common.Envelope, error) {
+	if len(pkgs) == 0 {
+		return nil, errors.New("at least one signed chaincode package is required")
+	}
+
+	var merged *pb.SignedChaincodeDeploymentSpec
+	var header *common.Header
+	endorsers := make(map[string]bool)
+	for _, pkg := range pkgs {
+		signedCDS, _, err := ExtractSignedCCPackage(pkg)
+		if err != nil {
+			return nil, err
+		}
+
+		if merged == nil {
+			payload, err := protos_utils.ExtractPayload(pkg)
+			if err != nil {
+				return nil, errors.Wrap(err, "unmarshal of signed chaincode package payload failed")
+			}
+			header = payload.Header
+			merged = &pb.SignedChaincodeDeploymentSpec{
+				ChaincodeDeploymentSpec: signedCDS.ChaincodeDeploymentSpec,
+				InstantiationPolicy:     signedCDS.InstantiationPolicy,
+			}
+		} else {
+			if !bytes.Equal(merged.ChaincodeDeploymentSpec, signedCDS.ChaincodeDeploymentSpec) {
+				return nil, errors.New("signed chaincode packages have different chaincode deployment specs")
+			}
+			if !bytes.Equal(merged.InstantiationPolicy, signedCDS.InstantiationPolicy) {
+				return nil, errors.New("signed chaincode packages have different instantiation policies")
+			}
+		}
+
+		for _, endorsement := range signedCDS.OwnerEndorsements {
+			if endorsers[string(endorsement.Endorser)] {
+				continue
+			}
+			endorsers[string(endorsement.Endorser)] = true
+			merged.OwnerEndorsements = append(merged.OwnerEndorsements, endorsement)
+		}
+	}
+
+	data, err := proto.Marshal(merged)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of signed chaincode deployment spec failed")
+	}
+	payloadBytes, err := proto.Marshal(&common.Payload{Header: header, Data: data})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of signed chaincode package payload failed")
+	}
+	return &common.Envelope{Payload: payloadBytes}, nil
+}
+
+// ExtractSignedCCPackage extracts the signed chaincode deployment spec, and the chaincode deployment spec
+// it carries, from the signed chaincode deployment package.
+func ExtractSignedCCPackage(pkg *common.Envelope) (*pb.SignedChaincodeDeploymentSpec, *pb.ChaincodeDeploymentSpec, error) {
+	if pkg == nil {
+		return nil, nil, errors.New("signed chaincode package is required")
+	}
+
+	payload, err := protos_utils.ExtractPayload(pkg)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "unmarshal of signed chaincode package payload failed")
+	}
+	if payload.Header == nil {
+		return nil, nil, errors.New("signed chaincode package has no header")
+	}
+	channelHeader, err := protos_utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "unmarshal of signed chaincode package header failed")
+	}
+	if channelHeader.Type != int32(common.HeaderType_CHAINCODE_PACKAGE) {
+		return nil, nil, errors.Errorf("invalid signed chaincode package header type [%d]", channelHeader.Type)
+	}
+
+	signedCDS := &pb.SignedChaincodeDeploymentSpec{}
+	if err := proto.Unmarshal(payload.Data, signedCDS); err != nil {
+		return nil, nil, errors.Wrap(err, "unmarshal of signed chaincode deployment spec failed")
+	}
+	cds := &pb.ChaincodeDeploymentSpec{}
+	if err := proto.Unmarshal(signedCDS.ChaincodeDeploymentSpec, cds); err != nil {
+		return nil, nil, errors.Wrap(err, "unmarshal of chaincode deployment spec failed")
+	}
+	if cds.ChaincodeSpec == nil || cds.ChaincodeSpec.ChaincodeId == nil {
+		return nil, nil, errors.New("signed chaincode package has no chaincode ID")
+	}
+	return signedCDS, cds, nil
+}
+
+// signCCPackage adds the owner endorsement of the context to the signed chaincode deployment spec
+// and wraps it in a chaincode package envelope signed by the context
+func signCCPackage(ctx context.Client, signedCDS *pb.SignedChaincodeDeploymentSpec) (*common.Envelope, error) {
+	creator, err := ctx.SerializedIdentity()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get user context's identity")
+	}
+	for _, endorsement := range signedCDS.OwnerEndorsements {
+		if bytes.Equal(endorsement.Endorser, creator) {
+			return nil, errors.New("signed chaincode package is already endorsed by the owner")
+		}
+	}
+
+	signingMgr := ctx.SigningManager()
+
+	// the owner endorses the deployment spec and the instantiation policy
+	signingBytes := fcutils.ConcatenateBytes(signedCDS.ChaincodeDeploymentSpec, signedCDS.InstantiationPolicy, creator)
+	signature, err := signingMgr.Sign(signingBytes, ctx.PrivateKey())
+	if err != nil {
+		return nil, errors.WithMessage(err, "signing of chaincode package failed")
+	}
+	signedCDS.OwnerEndorsements = append(signedCDS.OwnerEndorsements, &pb.Endorsement{Endorser: creator, Signature: signature})
+
+	data, err := proto.Marshal(signedCDS)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of signed chaincode deployment spec failed")
+	}
+
+	nonce, err := crypto.GetRandomNonce()
+	if err != nil {
+		return nil, errors.WithMessage(err, "nonce creation failed")
+	}
+	channelHeader := protos_utils.MakeChannelHeader(common.HeaderType_CHAINCODE_PACKAGE, 0, "", 0)
+	signatureHeader := &common.SignatureHeader{Creator: creator, Nonce: nonce}
+	payloadBytes, err := proto.Marshal(&common.Payload{Header: protos_utils.MakePayloadHeader(channelHeader, signatureHeader), Data: data})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of signed chaincode package payload failed")
+	}
+
+	payloadSignature, err := signingMgr.Sign(payloadBytes, ctx.PrivateKey())
+	if err != nil {
+		return nil, errors.WithMessage(err, "signing of chaincode package failed")
+	}
+	return &common.Envelope{Payload: payloadBytes, Signature: payloadSignature}, nil
+}
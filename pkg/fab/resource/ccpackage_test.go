@@ -0,0 +1,137 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resource
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// testOwner is a chaincode owner with a distinct serialized identity
+type testOwner struct {
+	core.User
+	identity []byte
+}
+
+func (o *testOwner) SerializedIdentity() ([]byte, error) {
+	return o.identity, nil
+}
+
+func newTestOwnerContext(mspID string) context.Client {
+	owner := &testOwner{User: mocks.NewMockUserWithMSPID(mspID+"admin", mspID), identity: []byte(mspID + "admin")}
+	return mocks.NewMockContext(owner)
+}
+
+func newTestCDS(t *testing.T) *pb.ChaincodeDeploymentSpec {
+	cds, err := CreateChaincodeDeploymentSpec(ChaincodeInstallRequest{
+		Name:    "examplecc",
+		Path:    "github.com/examplecc",
+		Version: "1",
+		Package: &ChaincodePackage{Type: pb.ChaincodeSpec_GOLANG, Code: []byte("code")},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create chaincode deployment spec: %s", err)
+	}
+	return cds
+}
+
+func TestSignedCCPackage(t *testing.T) {
+	cds := newTestCDS(t)
+	policy := cauthdsl.SignedByAnyAdmin([]string{"Org1MSP", "Org2MSP"})
+
+	pkg, err := CreateSignedCCPackage(newTestOwnerContext("Org1MSP"), cds, policy)
+	if err != nil {
+		t.Fatalf("Failed to create signed chaincode package: %s", err)
+	}
+	assert.NotEmpty(t, pkg.Signature, "expected package signed by the owner")
+
+	pkg, err = SignCCPackage(newTestOwnerContext("Org2MSP"), pkg)
+	if err != nil {
+		t.Fatalf("Failed to sign chaincode package: %s", err)
+	}
+
+	signedCDS, extractedCDS, err := ExtractSignedCCPackage(pkg)
+	if err != nil {
+		t.Fatalf("Failed to extract signed chaincode package: %s", err)
+	}
+	assert.True(t, proto.Equal(cds, extractedCDS), "unexpected chaincode deployment spec")
+	assert.Equal(t, marshalOrPanic(policy), signedCDS.InstantiationPolicy)
+	if assert.Len(t, signedCDS.OwnerEndorsements, 2) {
+		assert.Equal(t, []byte("Org1MSPadmin"), signedCDS.OwnerEndorsements[0].Endorser)
+		assert.Equal(t, []byte("Org2MSPadmin"), signedCDS.OwnerEndorsements[1].Endorser)
+		// The mock signing manager returns the signed bytes
+		expected := append(append(append([]byte{}, signedCDS.ChaincodeDeploymentSpec...), signedCDS.InstantiationPolicy...), []byte("Org1MSPadmin")...)
+		assert.Equal(t, expected, signedCDS.OwnerEndorsements[0].Signature)
+	}
+
+	// An owner endorses the package once
+	_, err = SignCCPackage(newTestOwnerContext("Org2MSP"), pkg)
+	assert.Error(t, err, "expected error for owner that already endorsed the package")
+}
+
+func TestSignedCCPackageErrors(t *testing.T) {
+	ctx := newTestOwnerContext("Org1MSP")
+	policy := cauthdsl.SignedByMspAdmin("Org1MSP")
+
+	_, err := CreateSignedCCPackage(ctx, nil, policy)
+	assert.Error(t, err, "expected error for missing deployment spec")
+
+	_, err = CreateSignedCCPackage(ctx, newTestCDS(t), nil)
+	assert.Error(t, err, "expected error for missing instantiation policy")
+
+	_, _, err = ExtractSignedCCPackage(nil)
+	assert.Error(t, err, "expected error for missing package")
+
+	// Envelopes that aren't chaincode packages are rejected
+	payload := &common.Payload{Header: &common.Header{ChannelHeader: marshalOrPanic(&common.ChannelHeader{Type: int32(common.HeaderType_CONFIG_UPDATE)})}}
+	_, _, err = ExtractSignedCCPackage(&common.Envelope{Payload: marshalOrPanic(payload)})
+	assert.Error(t, err, "expected error for invalid header type")
+}
+
+func TestMergeSignedCCPackages(t *testing.T) {
+	cds := newTestCDS(t)
+	policy := cauthdsl.SignedByAnyAdmin([]string{"Org1MSP", "Org2MSP"})
+
+	org1Pkg, err := CreateSignedCCPackage(newTestOwnerContext("Org1MSP"), cds, policy)
+	if err != nil {
+		t.Fatalf("Failed to create signed chaincode package: %s", err)
+	}
+	org2Pkg, err := CreateSignedCCPackage(newTestOwnerContext("Org2MSP"), cds, policy)
+	if err != nil {
+		t.Fatalf("Failed to create signed chaincode package: %s", err)
+	}
+
+	pkg, err := MergeSignedCCPackages(org1Pkg, org2Pkg, org1Pkg)
+	if err != nil {
+		t.Fatalf("Failed to merge signed chaincode packages: %s", err)
+	}
+	signedCDS, _, err := ExtractSignedCCPackage(pkg)
+	if err != nil {
+		t.Fatalf("Failed to extract signed chaincode package: %s", err)
+	}
+	assert.Len(t, signedCDS.OwnerEndorsements, 2)
+
+	// Packages must have the same instantiation policy
+	otherPkg, err := CreateSignedCCPackage(newTestOwnerContext("Org2MSP"), cds, cauthdsl.SignedByMspAdmin("Org2MSP"))
+	if err != nil {
+		t.Fatalf("Failed to create signed chaincode package: %s", err)
+	}
+	_, err = MergeSignedCCPackages(org1Pkg, otherPkg)
+	assert.Error(t, err, "expected error for different instantiation policies")
+
+	_, err = MergeSignedCCPackages()
+	assert.Error(t, err, "expected error for no packages")
+}
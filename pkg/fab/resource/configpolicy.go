@@ -0,0 +1,448 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resource
+
+import (
+	"bytes"
+	"encoding/pem"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	channelConfig "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/channelconfig"
+	fcutils "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+)
+
+const (
+	applicationGroupKey      = "Application"
+	consortiumsGroupKey      = "Consortiums"
+	channelCreationPolicyKey = "ChannelCreationPolicy"
+)
+
+// IsChannelCreation returns true if the config update creates a new channel, i.e. it
+// names the consortium the channel is created in
+func IsChannelCreation(configUpdate []byte) (bool, error) {
+	update := &common.ConfigUpdate{}
+	if err := proto.Unmarshal(configUpdate, update); err != nil {
+		return false, errors.Wrap(err, "unmarshal config update failed")
+	}
+	_, ok := update.GetWriteSet().GetValues()[channelConfig.ConsortiumKey]
+	return ok, nil
+}
+
+// CheckConfigSignatures checks that the signatures of the config update satisfy the modification policies,
+// in the given channel config, of all the config elements that the update modifies. If a membership is given,
+// the signers are validated and their signatures verified with it.
+//
+// Signers are matched to the principals of signature policies by MSP ID. Admin principals are only
+// satisfied by signers whose certificate is an admin certificate of their MSP in the channel config.
+func CheckConfigSignatures(config *common.Config, configUpdate []byte, signatures []*common.ConfigSignature, membership fab.ChannelMembership) error {
+	if config == nil || config.ChannelGroup == nil {
+		return errors.New("no channel group included for config")
+	}
+
+	update := &common.ConfigUpdate{}
+	if err := proto.Unmarshal(configUpdate, update); err != nil {
+		return errors.Wrap(err, "unmarshal config update failed")
+	}
+	if update.WriteSet == nil {
+		return errors.New("config update has no write set")
+	}
+
+	signers, err := configSigners(configUpdate, signatures, membership)
+	if err != nil {
+		return err
+	}
+
+	checker := &policyChecker{
+		channelGroup: config.ChannelGroup,
+		admins:       make(map[string][][]byte),
+		signers:      signers,
+	}
+	if err := checker.loadAdmins(config.ChannelGroup); err != nil {
+		return err
+	}
+	return checker.checkGroup([]string{channelConfig.ChannelGroupKey}, config.ChannelGroup, update.ReadSet, update.WriteSet)
+}
+
+// CheckChannelCreationSignatures checks that the signatures of the config update, which creates a channel,
+// satisfy the channel creation policy of the consortium in the given config of the orderer system channel.
+// As the orderer does, the policy is evaluated against the organizations of the new channel's application
+// group, which must be members of the consortium. The signatures themselves are verified by the orderer.
+func CheckChannelCreationSignatures(systemConfig *common.Config, configUpdate []byte, signatures []*common.ConfigSignature) error {
+	if systemConfig == nil || systemConfig.ChannelGroup == nil {
+		return errors.New("no channel group included for system channel config")
+	}
+
+	update := &common.ConfigUpdate{}
+	if err := proto.Unmarshal(configUpdate, update); err != nil {
+		return errors.Wrap(err, "unmarshal config update failed")
+	}
+	consortiumValue, ok := update.GetWriteSet().GetValues()[channelConfig.ConsortiumKey]
+	if !ok {
+		return errors.New("config update doesn't create a channel")
+	}
+	consortiumName := &common.Consortium{}
+	if err := proto.Unmarshal(consortiumValue.Value, consortiumName); err != nil {
+		return errors.Wrap(err, "unmarshal consortium failed")
+	}
+
+	consortium, ok := systemConfig.ChannelGroup.Groups[consortiumsGroupKey].GetGroups()[consortiumName.Name]
+	if !ok {
+		return errors.Errorf("consortium [%s] not found in system channel config", consortiumName.Name)
+	}
+	policyValue, ok := consortium.Values[channelCreationPolicyKey]
+	if !ok {
+		return errors.Errorf("consortium [%s] has no channel creation policy", consortiumName.Name)
+	}
+	policy := &common.Policy{}
+	if err := proto.Unmarshal(policyValue.Value, policy); err != nil {
+		return errors.Wrap(err, "unmarshal channel creation policy failed")
+	}
+
+	// The application group of the new channel has the consortium's definitions of its organizations
+	application := &common.ConfigGroup{Groups: make(map[string]*common.ConfigGroup)}
+	for orgName := range update.WriteSet.Groups[applicationGroupKey].GetGroups() {
+		org, ok := consortium.Groups[orgName]
+		if !ok {
+			return errors.Errorf("organization [%s] is not a member of consortium [%s]", orgName, consortiumName.Name)
+		}
+		application.Groups[orgName] = org
+	}
+	if len(application.Groups) == 0 {
+		return errors.New("config update has no application organizations")
+	}
+
+	signers, err := configSigners(configUpdate, signatures, nil)
+	if err != nil {
+		return err
+	}
+
+	checker := &policyChecker{
+		admins:  make(map[string][][]byte),
+		signers: signers,
+	}
+	if err := checker.loadAdmins(application); err != nil {
+		return err
+	}
+	satisfied, err := checker.evaluate(application, policy)
+	if err != nil {
+		return errors.WithMessage(err, "evaluation of channel creation policy failed")
+	}
+	if !satisfied {
+		return errors.Errorf("signatures do not satisfy the channel creation policy of consortium [%s]", consortiumName.Name)
+	}
+	return nil
+}
+
+// configSigners returns the distinct identities that signed the config update
+func configSigners(configUpdate []byte, signatures []*common.ConfigSignature, membership fab.ChannelMembership) ([]*msp.SerializedIdentity, error) {
+	var signers []*msp.SerializedIdentity
+	seen := make(map[string]bool)
+	for _, signature := range signatures {
+		header := &common.SignatureHeader{}
+		if err := proto.Unmarshal(signature.SignatureHeader, header); err != nil {
+			return nil, errors.Wrap(err, "unmarshal signature header failed")
+		}
+
+		if membership != nil {
+			if err := membership.Validate(header.Creator); err != nil {
+				return nil, errors.WithMessage(err, "signer is not valid")
+			}
+			signedBytes := fcutils.ConcatenateBytes(signature.SignatureHeader, configUpdate)
+			if err := membership.Verify(header.Creator, signedBytes, signature.Signature); err != nil {
+				return nil, errors.WithMessage(err, "config signature is not valid")
+			}
+		}
+
+		// an identity satisfies a policy only once, whatever the number of its signatures
+		if seen[string(header.Creator)] {
+			continue
+		}
+		seen[string(header.Creator)] = true
+
+		signer := &msp.SerializedIdentity{}
+		if err := proto.Unmarshal(header.Creator, signer); err != nil {
+			return nil, errors.Wrap(err, "unmarshal signer identity failed")
+		}
+		signers = append(signers, signer)
+	}
+	return signers, nil
+}
+
+type policyChecker struct {
+	channelGroup *common.ConfigGroup
+	admins       map[string][][]byte
+	signers      []*msp.SerializedIdentity
+}
+
+// loadAdmins collects the admin certificates of the MSPs defined in the config
+func (c *policyChecker) loadAdmins(group *common.ConfigGroup) error {
+	if value, ok := group.Values[channelConfig.MSPKey]; ok {
+		mspConfig := &msp.MSPConfig{}
+		if err := proto.Unmarshal(value.Value, mspConfig); err != nil {
+			return errors.Wrap(err, "unmarshal MSP config failed")
+		}
+		fabricConfig := &msp.FabricMSPConfig{}
+		if err := proto.Unmarshal(mspConfig.Config, fabricConfig); err != nil {
+			return errors.Wrap(err, "unmarshal fabric MSP config failed")
+		}
+		for _, admin := range fabricConfig.Admins {
+			c.admins[fabricConfig.Name] = append(c.admins[fabricConfig.Name], certBytes(admin))
+		}
+	}
+
+	for _, subGroup := range group.Groups {
+		if err := c.loadAdmins(subGroup); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkGroup checks the modification policies of the elements of the group modified by the update.
+// Elements added by the update are covered by the modification policy of their group, whose version changes.
+func (c *policyChecker) checkGroup(path []string, existing, readSet, writeSet *common.ConfigGroup) error {
+	if readSet != nil && readSet.Version != existing.Version {
+		return errors.Errorf("config update read set of [%s] is stale: version %d, current version %d", strings.Join(path, "/"), readSet.Version, existing.Version)
+	}
+
+	if readSet == nil || readSet.Version != writeSet.Version {
+		if err := c.checkModification(path, path, existing.Version, writeSet.Version, existing.ModPolicy); err != nil {
+			return err
+		}
+	}
+
+	for key, value := range writeSet.Values {
+		existingValue, ok := existing.Values[key]
+		if !ok {
+			continue
+		}
+		readValue, ok := readSet.GetValues()[key]
+		if ok && readValue.Version == value.Version {
+			continue
+		}
+		if err := c.checkModification(append(path, key), path, existingValue.Version, value.Version, existingValue.ModPolicy); err != nil {
+			return err
+		}
+	}
+
+	for key, policy := range writeSet.Policies {
+		existingPolicy, ok := existing.Policies[key]
+		if !ok {
+			continue
+		}
+		readPolicy, ok := readSet.GetPolicies()[key]
+		if ok && readPolicy.Version == policy.Version {
+			continue
+		}
+		if err := c.checkModification(append(path, key), path, existingPolicy.Version, policy.Version, existingPolicy.ModPolicy); err != nil {
+			return err
+		}
+	}
+
+	for key, group := range writeSet.Groups {
+		existingGroup, ok := existing.Groups[key]
+		if !ok {
+			continue
+		}
+		if err := c.checkGroup(append(path, key), existingGroup, readSet.GetGroups()[key], group); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkModification checks the version and the modification policy of the modified element.
+// Relative policy names are resolved in the group at the given policy path.
+func (c *policyChecker) checkModification(elementPath, policyPath []string, existingVersion, version uint64, modPolicy string) error {
+	element := strings.Join(elementPath, "/")
+	if version != existingVersion+1 {
+		return errors.Errorf("config update of [%s] must increment its version %d, got %d", element, existingVersion, version)
+	}
+	if modPolicy == "" {
+		return errors.Errorf("[%s] has no modification policy", element)
+	}
+
+	var names []string
+	if strings.HasPrefix(modPolicy, "/") {
+		names = strings.Split(modPolicy[1:], "/")
+	} else {
+		names = append(append([]string{}, policyPath...), strings.Split(modPolicy, "/")...)
+	}
+
+	group, err := c.group(names[:len(names)-1])
+	if err != nil {
+		return errors.WithMessage(err, "modification policy of ["+element+"] not found")
+	}
+	policy, ok := group.Policies[names[len(names)-1]]
+	if !ok || policy.Policy == nil {
+		return errors.Errorf("modification policy [%s] of [%s] not found", strings.Join(names, "/"), element)
+	}
+
+	satisfied, err := c.evaluate(group, policy.Policy)
+	if err != nil {
+		return errors.WithMessage(err, "evaluation of modification policy ["+strings.Join(names, "/")+"] failed")
+	}
+	if !satisfied {
+		return errors.Errorf("signatures do not satisfy the modification policy [%s] of [%s]", strings.Join(names, "/"), element)
+	}
+	return nil
+}
+
+func (c *policyChecker) group(path []string) (*common.ConfigGroup, error) {
+	if len(path) == 0 || path[0] != channelConfig.ChannelGroupKey {
+		return nil, errors.Errorf("invalid group path [%s]", strings.Join(path, "/"))
+	}
+	group := c.channelGroup
+	for _, key := range path[1:] {
+		subGroup, ok := group.Groups[key]
+		if !ok {
+			return nil, errors.Errorf("group [%s] not found", strings.Join(path, "/"))
+		}
+		group = subGroup
+	}
+	return group, nil
+}
+
+// evaluate returns true if the signers satisfy the policy defined in the given group
+func (c *policyChecker) evaluate(group *common.ConfigGroup, policy *common.Policy) (bool, error) {
+	switch common.Policy_PolicyType(policy.Type) {
+	case common.Policy_SIGNATURE:
+		envelope := &common.SignaturePolicyEnvelope{}
+		if err := proto.Unmarshal(policy.Value, envelope); err != nil {
+			return false, errors.Wrap(err, "unmarshal signature policy envelope failed")
+		}
+		return c.evaluateRule(envelope.Rule, envelope.Identities, make([]bool, len(c.signers)))
+
+	case common.Policy_IMPLICIT_META:
+		metaPolicy := &common.ImplicitMetaPolicy{}
+		if err := proto.Unmarshal(policy.Value, metaPolicy); err != nil {
+			return false, errors.Wrap(err, "unmarshal implicit meta policy failed")
+		}
+		return c.evaluateImplicitMeta(group, metaPolicy)
+
+	default:
+		return false, errors.Errorf("unsupported policy type %v", policy.Type)
+	}
+}
+
+func (c *policyChecker) evaluateImplicitMeta(group *common.ConfigGroup, metaPolicy *common.ImplicitMetaPolicy) (bool, error) {
+	var total, satisfied int
+	for _, subGroup := range group.Groups {
+		subPolicy, ok := subGroup.Policies[metaPolicy.SubPolicy]
+		if !ok || subPolicy.Policy == nil {
+			continue
+		}
+		total++
+		ok, err := c.evaluate(subGroup, subPolicy.Policy)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			satisfied++
+		}
+	}
+
+	switch metaPolicy.Rule {
+	case common.ImplicitMetaPolicy_ANY:
+		return satisfied >= 1, nil
+	case common.ImplicitMetaPolicy_ALL:
+		return satisfied == total, nil
+	case common.ImplicitMetaPolicy_MAJORITY:
+		return satisfied >= total/2+1, nil
+	default:
+		return false, errors.Errorf("unknown implicit meta policy rule %v", metaPolicy.Rule)
+	}
+}
+
+// evaluateRule evaluates the signature policy rule, each signer satisfying at most one principal
+func (c *policyChecker) evaluateRule(rule *common.SignaturePolicy, identities []*msp.MSPPrincipal, used []bool) (bool, error) {
+	switch t := rule.GetType().(type) {
+	case *common.SignaturePolicy_SignedBy:
+		if t.SignedBy < 0 || int(t.SignedBy) >= len(identities) {
+			return false, errors.Errorf("signature policy references unknown principal %d", t.SignedBy)
+		}
+		for i, signer := range c.signers {
+			if !used[i] && c.satisfiesPrincipal(signer, identities[t.SignedBy]) {
+				used[i] = true
+				return true, nil
+			}
+		}
+		return false, nil
+
+	case *common.SignaturePolicy_NOutOf_:
+		verified := 0
+		for _, subRule := range t.NOutOf.Rules {
+			subUsed := append([]bool{}, used...)
+			ok, err := c.evaluateRule(subRule, identities, subUsed)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				verified++
+				copy(used, subUsed)
+			}
+		}
+		return verified >= int(t.NOutOf.N), nil
+
+	default:
+		return false, errors.Errorf("unknown signature policy rule type %T", t)
+	}
+}
+
+func (c *policyChecker) satisfiesPrincipal(signer *msp.SerializedIdentity, principal *msp.MSPPrincipal) bool {
+	switch principal.PrincipalClassification {
+	case msp.MSPPrincipal_ROLE:
+		role := &msp.MSPRole{}
+		if err := proto.Unmarshal(principal.Principal, role); err != nil || role.MspIdentifier != signer.Mspid {
+			return false
+		}
+		if role.Role == msp.MSPRole_ADMIN {
+			return c.isAdmin(signer)
+		}
+		return true
+	case msp.MSPPrincipal_ORGANIZATION_UNIT:
+		ou := &msp.OrganizationUnit{}
+		if err := proto.Unmarshal(principal.Principal, ou); err != nil {
+			return false
+		}
+		return ou.MspIdentifier == signer.Mspid
+	case msp.MSPPrincipal_IDENTITY:
+		identity := &msp.SerializedIdentity{}
+		if err := proto.Unmarshal(principal.Principal, identity); err != nil {
+			return false
+		}
+		return identity.Mspid == signer.Mspid && bytes.Equal(certBytes(identity.IdBytes), certBytes(signer.IdBytes))
+	default:
+		return false
+	}
+}
+
+func (c *policyChecker) isAdmin(signer *msp.SerializedIdentity) bool {
+	cert := certBytes(signer.IdBytes)
+	for _, admin := range c.admins[signer.Mspid] {
+		if bytes.Equal(admin, cert) {
+			return true
+		}
+	}
+	return false
+}
+
+// certBytes returns the DER bytes of a PEM encoded certificate
+func certBytes(cert []byte) []byte {
+	block, _ := pem.Decode(cert)
+	if block == nil {
+		return cert
+	}
+	return block.Bytes
+}
@@ -0,0 +1,255 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resource
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+)
+
+func marshalOrPanic(msg proto.Message) []byte {
+	bytes, err := proto.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return bytes
+}
+
+func newTestOrgGroup(mspID string, admin []byte) *common.ConfigGroup {
+	mspConfig := &msp.MSPConfig{Config: marshalOrPanic(&msp.FabricMSPConfig{Name: mspID, Admins: [][]byte{admin}})}
+	return &common.ConfigGroup{
+		Version:   1,
+		ModPolicy: "Admins",
+		Values: map[string]*common.ConfigValue{
+			"MSP":         {ModPolicy: "Admins", Value: marshalOrPanic(mspConfig)},
+			"AnchorPeers": {ModPolicy: "Admins", Value: []byte("anchor")},
+		},
+		Policies: map[string]*common.ConfigPolicy{
+			"Admins": {
+				ModPolicy: "Admins",
+				Policy:    &common.Policy{Type: int32(common.Policy_SIGNATURE), Value: marshalOrPanic(cauthdsl.SignedByMspAdmin(mspID))},
+			},
+		},
+	}
+}
+
+func newTestPolicyConfig() *common.Config {
+	majorityAdmins := &common.ImplicitMetaPolicy{SubPolicy: "Admins", Rule: common.ImplicitMetaPolicy_MAJORITY}
+	return &common.Config{
+		ChannelGroup: &common.ConfigGroup{
+			ModPolicy: "Admins",
+			Groups: map[string]*common.ConfigGroup{
+				"Application": {
+					Version:   1,
+					ModPolicy: "Admins",
+					Groups: map[string]*common.ConfigGroup{
+						"Org1MSP": newTestOrgGroup("Org1MSP", []byte("org1admin")),
+						"Org2MSP": newTestOrgGroup("Org2MSP", []byte("org2admin")),
+					},
+					Policies: map[string]*common.ConfigPolicy{
+						"Admins": {
+							ModPolicy: "Admins",
+							Policy:    &common.Policy{Type: int32(common.Policy_IMPLICIT_META), Value: marshalOrPanic(majorityAdmins)},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func newTestConfigSignature(mspID string, cert []byte) *common.ConfigSignature {
+	creator := marshalOrPanic(&msp.SerializedIdentity{Mspid: mspID, IdBytes: cert})
+	return &common.ConfigSignature{
+		SignatureHeader: marshalOrPanic(&common.SignatureHeader{Creator: creator, Nonce: []byte(mspID + string(cert))}),
+		Signature:       []byte("signature"),
+	}
+}
+
+func computeTestConfigUpdate(t *testing.T, original, updated *common.Config) []byte {
+	configUpdate, err := ComputeConfigUpdate("mychannel", original, updated)
+	if err != nil {
+		t.Fatalf("Failed to compute config update: %s", err)
+	}
+	return marshalOrPanic(configUpdate)
+}
+
+func TestCheckConfigSignaturesOrgUpdate(t *testing.T) {
+	original := newTestPolicyConfig()
+	updated := proto.Clone(original).(*common.Config)
+	updated.ChannelGroup.Groups["Application"].Groups["Org1MSP"].Values["AnchorPeers"].Value = []byte("new anchor")
+	configUpdate := computeTestConfigUpdate(t, original, updated)
+
+	org1Admin := newTestConfigSignature("Org1MSP", []byte("org1admin"))
+	org1Member := newTestConfigSignature("Org1MSP", []byte("org1member"))
+	org2Admin := newTestConfigSignature("Org2MSP", []byte("org2admin"))
+
+	// The anchor peers of an org are modified by the admins of the org
+	err := CheckConfigSignatures(original, configUpdate, []*common.ConfigSignature{org1Admin}, nil)
+	assert.Nil(t, err)
+
+	err = CheckConfigSignatures(original, configUpdate, []*common.ConfigSignature{org1Member}, nil)
+	assert.NotNil(t, err, "expected error for signature of non admin")
+
+	err = CheckConfigSignatures(original, configUpdate, []*common.ConfigSignature{org2Admin}, nil)
+	assert.NotNil(t, err, "expected error for signature of admin of another org")
+
+	// Signatures are verified with the membership
+	membership := mocks.NewMockMembership()
+	err = CheckConfigSignatures(original, configUpdate, []*common.ConfigSignature{org1Admin}, membership)
+	assert.Nil(t, err)
+
+	membership.VerifyErr = errors.New("invalid signature")
+	err = CheckConfigSignatures(original, configUpdate, []*common.ConfigSignature{org1Admin}, membership)
+	assert.NotNil(t, err, "expected error for invalid signature")
+}
+
+func TestCheckConfigSignaturesAddOrg(t *testing.T) {
+	original := newTestPolicyConfig()
+	updated := proto.Clone(original).(*common.Config)
+	updated.ChannelGroup.Groups["Application"].Groups["Org3MSP"] = newTestOrgGroup("Org3MSP", []byte("org3admin"))
+	configUpdate := computeTestConfigUpdate(t, original, updated)
+
+	org1Admin := newTestConfigSignature("Org1MSP", []byte("org1admin"))
+	org2Admin := newTestConfigSignature("Org2MSP", []byte("org2admin"))
+
+	// Adding an org requires a majority of the admins of the application orgs
+	err := CheckConfigSignatures(original, configUpdate, []*common.ConfigSignature{org1Admin}, nil)
+	assert.NotNil(t, err, "expected error without a majority of admins")
+
+	err = CheckConfigSignatures(original, configUpdate, []*common.ConfigSignature{org1Admin, org1Admin}, nil)
+	assert.NotNil(t, err, "expected error for duplicate signatures")
+
+	err = CheckConfigSignatures(original, configUpdate, []*common.ConfigSignature{org1Admin, org2Admin}, nil)
+	assert.Nil(t, err)
+}
+
+func TestCheckConfigSignaturesErrors(t *testing.T) {
+	original := newTestPolicyConfig()
+	updated := proto.Clone(original).(*common.Config)
+	updated.ChannelGroup.Groups["Application"].Groups["Org1MSP"].Values["AnchorPeers"].Value = []byte("new anchor")
+	configUpdate := computeTestConfigUpdate(t, original, updated)
+	signatures := []*common.ConfigSignature{newTestConfigSignature("Org1MSP", []byte("org1admin"))}
+
+	err := CheckConfigSignatures(nil, configUpdate, signatures, nil)
+	assert.NotNil(t, err, "expected error for nil config")
+
+	err = CheckConfigSignatures(original, []byte("invalid"), signatures, nil)
+	assert.NotNil(t, err, "expected error for invalid config update")
+
+	// The config has changed since the update was computed
+	current := proto.Clone(original).(*common.Config)
+	current.ChannelGroup.Groups["Application"].Groups["Org1MSP"].Version = 2
+	err = CheckConfigSignatures(current, configUpdate, signatures, nil)
+	assert.NotNil(t, err, "expected error for stale read set")
+
+	// Missing modification policy
+	current = proto.Clone(original).(*common.Config)
+	current.ChannelGroup.Groups["Application"].Groups["Org1MSP"].Values["AnchorPeers"].ModPolicy = "Unknown"
+	err = CheckConfigSignatures(current, configUpdate, signatures, nil)
+	assert.NotNil(t, err, "expected error for unknown modification policy")
+}
+
+func TestIsChannelCreation(t *testing.T) {
+	update := &common.ConfigUpdate{
+		ChannelId: "mychannel",
+		WriteSet: &common.ConfigGroup{
+			Values: map[string]*common.ConfigValue{"Consortium": {Value: []byte("SampleConsortium")}},
+		},
+	}
+	creation, err := IsChannelCreation(marshalOrPanic(update))
+	assert.Nil(t, err)
+	assert.True(t, creation)
+
+	delete(update.WriteSet.Values, "Consortium")
+	creation, err = IsChannelCreation(marshalOrPanic(update))
+	assert.Nil(t, err)
+	assert.False(t, creation)
+}
+
+func newTestSystemConfig() *common.Config {
+	anyAdmins := &common.ImplicitMetaPolicy{SubPolicy: "Admins", Rule: common.ImplicitMetaPolicy_ANY}
+	creationPolicy := &common.Policy{Type: int32(common.Policy_IMPLICIT_META), Value: marshalOrPanic(anyAdmins)}
+	return &common.Config{
+		ChannelGroup: &common.ConfigGroup{
+			Groups: map[string]*common.ConfigGroup{
+				"Consortiums": {
+					Groups: map[string]*common.ConfigGroup{
+						"SampleConsortium": {
+							Groups: map[string]*common.ConfigGroup{
+								"Org1MSP": newTestOrgGroup("Org1MSP", []byte("org1admin")),
+								"Org2MSP": newTestOrgGroup("Org2MSP", []byte("org2admin")),
+								"Org3MSP": newTestOrgGroup("Org3MSP", []byte("org3admin")),
+							},
+							Values: map[string]*common.ConfigValue{
+								"ChannelCreationPolicy": {Value: marshalOrPanic(creationPolicy)},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func newTestChannelCreation(consortium string, orgs ...string) []byte {
+	application := &common.ConfigGroup{Groups: make(map[string]*common.ConfigGroup)}
+	for _, org := range orgs {
+		application.Groups[org] = &common.ConfigGroup{}
+	}
+	return marshalOrPanic(&common.ConfigUpdate{
+		ChannelId: "mychannel",
+		WriteSet: &common.ConfigGroup{
+			Groups: map[string]*common.ConfigGroup{"Application": application},
+			Values: map[string]*common.ConfigValue{"Consortium": {Value: marshalOrPanic(&common.Consortium{Name: consortium})}},
+		},
+	})
+}
+
+func TestCheckChannelCreationSignatures(t *testing.T) {
+	systemConfig := newTestSystemConfig()
+	configUpdate := newTestChannelCreation("SampleConsortium", "Org1MSP", "Org2MSP")
+
+	// Any admin of the channel's organizations satisfies the channel creation policy
+	err := CheckChannelCreationSignatures(systemConfig, configUpdate, []*common.ConfigSignature{newTestConfigSignature("Org2MSP", []byte("org2admin"))})
+	assert.Nil(t, err, "expected admin signature to satisfy the channel creation policy")
+
+	err = CheckChannelCreationSignatures(systemConfig, configUpdate, []*common.ConfigSignature{newTestConfigSignature("Org2MSP", []byte("org2member"))})
+	assert.NotNil(t, err, "expected error for member signature")
+
+	// Admins of consortium members that aren't in the channel don't satisfy the policy
+	err = CheckChannelCreationSignatures(systemConfig, configUpdate, []*common.ConfigSignature{newTestConfigSignature("Org3MSP", []byte("org3admin"))})
+	assert.NotNil(t, err, "expected error for admin of organization outside the channel")
+}
+
+func TestCheckChannelCreationSignaturesErrors(t *testing.T) {
+	systemConfig := newTestSystemConfig()
+	signatures := []*common.ConfigSignature{newTestConfigSignature("Org1MSP", []byte("org1admin"))}
+
+	err := CheckChannelCreationSignatures(nil, newTestChannelCreation("SampleConsortium", "Org1MSP"), signatures)
+	assert.NotNil(t, err, "expected error for nil system config")
+
+	err = CheckChannelCreationSignatures(systemConfig, newTestChannelCreation("OtherConsortium", "Org1MSP"), signatures)
+	assert.NotNil(t, err, "expected error for unknown consortium")
+
+	err = CheckChannelCreationSignatures(systemConfig, newTestChannelCreation("SampleConsortium", "Org4MSP"), signatures)
+	assert.NotNil(t, err, "expected error for organization that isn't a consortium member")
+
+	err = CheckChannelCreationSignatures(systemConfig, newTestChannelCreation("SampleConsortium"), signatures)
+	assert.NotNil(t, err, "expected error for channel without organizations")
+
+	err = CheckChannelCreationSignatures(systemConfig, marshalOrPanic(&common.ConfigUpdate{ChannelId: "mychannel", WriteSet: &common.ConfigGroup{}}), signatures)
+	assert.NotNil(t, err, "expected error for config update that doesn't create a channel")
+}
@@ -0,0 +1,236 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resource
+
+import (
+	"bytes"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+// ComputeConfigUpdate computes the config update that transitions the channel from the original
+// to the updated configuration. The read set holds the versions of the elements the update depends on
+// and the write set holds the modified elements with incremented versions, as expected by the orderer.
+func ComputeConfigUpdate(channelID string, original, updated *common.Config) (*common.ConfigUpdate, error) {
+	if original == nil || original.ChannelGroup == nil {
+		return nil, errors.New("no channel group included for original config")
+	}
+	if updated == nil || updated.ChannelGroup == nil {
+		return nil, errors.New("no channel group included for updated config")
+	}
+
+	readSet, writeSet, groupUpdated := computeGroupUpdate(original.ChannelGroup, updated.ChannelGroup)
+	if !groupUpdated {
+		return nil, errors.New("no differences detected between original and updated config")
+	}
+
+	return &common.ConfigUpdate{
+		ChannelId: channelID,
+		ReadSet:   readSet,
+		WriteSet:  writeSet,
+	}, nil
+}
+
+func computePoliciesMapUpdate(original, updated map[string]*common.ConfigPolicy) (readSet, writeSet, sameSet map[string]*common.ConfigPolicy, updatedMembers bool) {
+	readSet = make(map[string]*common.ConfigPolicy)
+	writeSet = make(map[string]*common.ConfigPolicy)
+	sameSet = make(map[string]*common.ConfigPolicy)
+
+	for policyName, originalPolicy := range original {
+		updatedPolicy, ok := updated[policyName]
+		if !ok {
+			updatedMembers = true
+			continue
+		}
+
+		if originalPolicy.ModPolicy == updatedPolicy.ModPolicy && proto.Equal(originalPolicy.Policy, updatedPolicy.Policy) {
+			sameSet[policyName] = &common.ConfigPolicy{Version: originalPolicy.Version}
+			continue
+		}
+
+		writeSet[policyName] = &common.ConfigPolicy{
+			Version:   originalPolicy.Version + 1,
+			ModPolicy: updatedPolicy.ModPolicy,
+			Policy:    updatedPolicy.Policy,
+		}
+	}
+
+	for policyName, updatedPolicy := range updated {
+		if _, ok := original[policyName]; ok {
+			// If the updatedPolicy is in the original set of policies, it was already handled
+			continue
+		}
+		updatedMembers = true
+		writeSet[policyName] = &common.ConfigPolicy{
+			Version:   0,
+			ModPolicy: updatedPolicy.ModPolicy,
+			Policy:    updatedPolicy.Policy,
+		}
+	}
+
+	return
+}
+
+func computeValuesMapUpdate(original, updated map[string]*common.ConfigValue) (readSet, writeSet, sameSet map[string]*common.ConfigValue, updatedMembers bool) {
+	readSet = make(map[string]*common.ConfigValue)
+	writeSet = make(map[string]*common.ConfigValue)
+	sameSet = make(map[string]*common.ConfigValue)
+
+	for valueName, originalValue := range original {
+		updatedValue, ok := updated[valueName]
+		if !ok {
+			updatedMembers = true
+			continue
+		}
+
+		if originalValue.ModPolicy == updatedValue.ModPolicy && bytes.Equal(originalValue.Value, updatedValue.Value) {
+			sameSet[valueName] = &common.ConfigValue{Version: originalValue.Version}
+			continue
+		}
+
+		writeSet[valueName] = &common.ConfigValue{
+			Version:   originalValue.Version + 1,
+			ModPolicy: updatedValue.ModPolicy,
+			Value:     updatedValue.Value,
+		}
+	}
+
+	for valueName, updatedValue := range updated {
+		if _, ok := original[valueName]; ok {
+			// If the updatedValue is in the original set of values, it was already handled
+			continue
+		}
+		updatedMembers = true
+		writeSet[valueName] = &common.ConfigValue{
+			Version:   0,
+			ModPolicy: updatedValue.ModPolicy,
+			Value:     updatedValue.Value,
+		}
+	}
+
+	return
+}
+
+func computeGroupsMapUpdate(original, updated map[string]*common.ConfigGroup) (readSet, writeSet, sameSet map[string]*common.ConfigGroup, updatedMembers bool) {
+	readSet = make(map[string]*common.ConfigGroup)
+	writeSet = make(map[string]*common.ConfigGroup)
+	sameSet = make(map[string]*common.ConfigGroup)
+
+	for groupName, originalGroup := range original {
+		updatedGroup, ok := updated[groupName]
+		if !ok {
+			updatedMembers = true
+			continue
+		}
+
+		groupReadSet, groupWriteSet, groupUpdated := computeGroupUpdate(originalGroup, updatedGroup)
+		if !groupUpdated {
+			sameSet[groupName] = groupReadSet
+			continue
+		}
+
+		readSet[groupName] = groupReadSet
+		writeSet[groupName] = groupWriteSet
+	}
+
+	for groupName, updatedGroup := range updated {
+		if _, ok := original[groupName]; ok {
+			// If the updatedGroup is in the original set of groups, it was already handled
+			continue
+		}
+		updatedMembers = true
+		_, groupWriteSet, _ := computeGroupUpdate(newConfigGroup(), updatedGroup)
+		writeSet[groupName] = &common.ConfigGroup{
+			Version:   0,
+			ModPolicy: updatedGroup.ModPolicy,
+			Policies:  groupWriteSet.Policies,
+			Values:    groupWriteSet.Values,
+			Groups:    groupWriteSet.Groups,
+		}
+	}
+
+	return
+}
+
+// computeGroupUpdate returns the read and write sets of the update from the original to the updated group,
+// and whether the group was updated
+func computeGroupUpdate(original, updated *common.ConfigGroup) (readSet, writeSet *common.ConfigGroup, updatedGroup bool) {
+	readSetPolicies, writeSetPolicies, sameSetPolicies, policiesMembersUpdated := computePoliciesMapUpdate(original.Policies, updated.Policies)
+	readSetValues, writeSetValues, sameSetValues, valuesMembersUpdated := computeValuesMapUpdate(original.Values, updated.Values)
+	readSetGroups, writeSetGroups, sameSetGroups, groupsMembersUpdated := computeGroupsMapUpdate(original.Groups, updated.Groups)
+
+	// If the membership and the mod policy of the group are unchanged, its version is unchanged
+	if !(policiesMembersUpdated || valuesMembersUpdated || groupsMembersUpdated || original.ModPolicy != updated.ModPolicy) {
+
+		// If none of the members were modified either, the group is unchanged
+		if len(readSetPolicies) == 0 &&
+			len(writeSetPolicies) == 0 &&
+			len(readSetValues) == 0 &&
+			len(writeSetValues) == 0 &&
+			len(readSetGroups) == 0 &&
+			len(writeSetGroups) == 0 {
+			return &common.ConfigGroup{
+				Version: original.Version,
+			}, &common.ConfigGroup{
+				Version: original.Version,
+			}, false
+		}
+
+		return &common.ConfigGroup{
+			Version:  original.Version,
+			Policies: readSetPolicies,
+			Values:   readSetValues,
+			Groups:   readSetGroups,
+		}, &common.ConfigGroup{
+			Version:  original.Version,
+			Policies: writeSetPolicies,
+			Values:   writeSetValues,
+			Groups:   writeSetGroups,
+		}, true
+	}
+
+	// The membership of the group changed, so the group is written with an incremented version
+	// and all its unchanged members are included at their current version
+	for k, samePolicy := range sameSetPolicies {
+		readSetPolicies[k] = samePolicy
+		writeSetPolicies[k] = samePolicy
+	}
+
+	for k, sameValue := range sameSetValues {
+		readSetValues[k] = sameValue
+		writeSetValues[k] = sameValue
+	}
+
+	for k, sameGroup := range sameSetGroups {
+		readSetGroups[k] = sameGroup
+		writeSetGroups[k] = sameGroup
+	}
+
+	return &common.ConfigGroup{
+		Version:  original.Version,
+		Policies: readSetPolicies,
+		Values:   readSetValues,
+		Groups:   readSetGroups,
+	}, &common.ConfigGroup{
+		Version:   original.Version + 1,
+		Policies:  writeSetPolicies,
+		Values:    writeSetValues,
+		Groups:    writeSetGroups,
+		ModPolicy: updated.ModPolicy,
+	}, true
+}
+
+func newConfigGroup() *common.ConfigGroup {
+	return &common.ConfigGroup{
+		Groups:   make(map[string]*common.ConfigGroup),
+		Values:   make(map[string]*common.ConfigValue),
+		Policies: make(map[string]*common.ConfigPolicy),
+	}
+}
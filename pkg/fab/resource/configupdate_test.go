@@ -0,0 +1,135 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resource
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+func newTestConfig() *common.Config {
+	return &common.Config{
+		Sequence: 3,
+		ChannelGroup: &common.ConfigGroup{
+			Version:   1,
+			ModPolicy: "Admins",
+			Groups: map[string]*common.ConfigGroup{
+				"Application": {
+					Version:   2,
+					ModPolicy: "Admins",
+					Groups: map[string]*common.ConfigGroup{
+						"Org1MSP": {
+							Version: 1,
+							Values: map[string]*common.ConfigValue{
+								"AnchorPeers": {Version: 0, ModPolicy: "Admins", Value: []byte("peer0.org1")},
+								"MSP":         {Version: 0, ModPolicy: "Admins", Value: []byte("org1-msp")},
+							},
+						},
+					},
+					Policies: map[string]*common.ConfigPolicy{
+						"Admins": {Version: 0, ModPolicy: "Admins", Policy: &common.Policy{Type: 3, Value: []byte("admins")}},
+					},
+				},
+			},
+			Values: map[string]*common.ConfigValue{
+				"BlockDataHashingStructure": {Version: 0, ModPolicy: "Admins", Value: []byte("hashing")},
+			},
+		},
+	}
+}
+
+func TestComputeConfigUpdateModifiedValue(t *testing.T) {
+	original := newTestConfig()
+	updated := proto.Clone(original).(*common.Config)
+	updated.ChannelGroup.Groups["Application"].Groups["Org1MSP"].Values["AnchorPeers"].Value = []byte("peer1.org1")
+
+	configUpdate, err := ComputeConfigUpdate("mychannel", original, updated)
+	if err != nil {
+		t.Fatalf("Failed to compute config update: %s", err)
+	}
+	assert.Equal(t, "mychannel", configUpdate.ChannelId)
+
+	// Only the modified value is written, with an incremented version
+	writeOrg := configUpdate.WriteSet.Groups["Application"].Groups["Org1MSP"]
+	assert.Equal(t, uint64(1), writeOrg.Version)
+	assert.Len(t, writeOrg.Values, 1)
+	assert.Equal(t, uint64(1), writeOrg.Values["AnchorPeers"].Version)
+	assert.Equal(t, []byte("peer1.org1"), writeOrg.Values["AnchorPeers"].Value)
+	assert.Empty(t, configUpdate.WriteSet.Values)
+
+	// The read set holds the versions of the enclosing groups
+	readOrg := configUpdate.ReadSet.Groups["Application"].Groups["Org1MSP"]
+	assert.Equal(t, uint64(1), readOrg.Version)
+	assert.Empty(t, readOrg.Values)
+	assert.Equal(t, uint64(2), configUpdate.ReadSet.Groups["Application"].Version)
+}
+
+func TestComputeConfigUpdateAddedGroup(t *testing.T) {
+	original := newTestConfig()
+	updated := proto.Clone(original).(*common.Config)
+	updated.ChannelGroup.Groups["Application"].Groups["Org2MSP"] = &common.ConfigGroup{
+		ModPolicy: "Admins",
+		Values: map[string]*common.ConfigValue{
+			"MSP": {ModPolicy: "Admins", Value: []byte("org2-msp")},
+		},
+	}
+
+	configUpdate, err := ComputeConfigUpdate("mychannel", original, updated)
+	if err != nil {
+		t.Fatalf("Failed to compute config update: %s", err)
+	}
+
+	// The membership of the application group changed, so its version is incremented
+	// and its unchanged members are included at their current version
+	writeApp := configUpdate.WriteSet.Groups["Application"]
+	assert.Equal(t, uint64(3), writeApp.Version)
+	assert.Equal(t, "Admins", writeApp.ModPolicy)
+	assert.Equal(t, uint64(1), writeApp.Groups["Org1MSP"].Version)
+	assert.Empty(t, writeApp.Groups["Org1MSP"].Values)
+	assert.Equal(t, uint64(0), writeApp.Policies["Admins"].Version)
+
+	org2 := writeApp.Groups["Org2MSP"]
+	assert.Equal(t, uint64(0), org2.Version)
+	assert.Equal(t, []byte("org2-msp"), org2.Values["MSP"].Value)
+
+	readApp := configUpdate.ReadSet.Groups["Application"]
+	assert.Equal(t, uint64(2), readApp.Version)
+	assert.Contains(t, readApp.Groups, "Org1MSP")
+	assert.NotContains(t, readApp.Groups, "Org2MSP")
+}
+
+func TestComputeConfigUpdateRemovedPolicy(t *testing.T) {
+	original := newTestConfig()
+	updated := proto.Clone(original).(*common.Config)
+	delete(updated.ChannelGroup.Groups["Application"].Policies, "Admins")
+
+	configUpdate, err := ComputeConfigUpdate("mychannel", original, updated)
+	if err != nil {
+		t.Fatalf("Failed to compute config update: %s", err)
+	}
+
+	writeApp := configUpdate.WriteSet.Groups["Application"]
+	assert.Equal(t, uint64(3), writeApp.Version)
+	assert.Empty(t, writeApp.Policies)
+}
+
+func TestComputeConfigUpdateErrors(t *testing.T) {
+	original := newTestConfig()
+
+	_, err := ComputeConfigUpdate("mychannel", original, proto.Clone(original).(*common.Config))
+	assert.NotNil(t, err, "expected error for unchanged config")
+
+	_, err = ComputeConfigUpdate("mychannel", &common.Config{}, original)
+	assert.NotNil(t, err, "expected error for original config without channel group")
+
+	_, err = ComputeConfigUpdate("mychannel", original, nil)
+	assert.NotNil(t, err, "expected error for nil updated config")
+}
@@ -9,9 +9,11 @@ package resource
 import (
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 	protos_utils "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
 	"github.com/pkg/errors"
@@ -29,6 +31,10 @@ type ChaincodeInstallRequest struct {
 	Path    string
 	Version string
 	Package *ChaincodePackage
+	// chaincode deployment spec to install as is, instead of the package (optional)
+	DeploymentSpec *pb.ChaincodeDeploymentSpec
+	// signed chaincode deployment package to install instead of the package (optional)
+	SignedPackage *common.Envelope
 }
 
 // ChaincodePackage contains package type and bytes required to create CDS
@@ -47,24 +53,47 @@ func CreateChaincodeInstallProposal(txh fab.TransactionHeader, request Chaincode
 	return txn.CreateChaincodeInvokeProposal(txh, cir)
 }
 
-func createInstallInvokeRequest(request ChaincodeInstallRequest) (fab.ChaincodeInvokeRequest, error) {
-	// Generate arguments for install
-	args := [][]byte{}
+// CreateChaincodeDeploymentSpec creates the deployment spec of the chaincode package to install.
+func CreateChaincodeDeploymentSpec(request ChaincodeInstallRequest) (*pb.ChaincodeDeploymentSpec, error) {
+	if request.Package == nil {
+		return nil, errors.New("chaincode package is required")
+	}
+
 	timestamp := time.Now()
 	ts, err := ptypes.TimestampProto(timestamp)
 	if err != nil {
-		return fab.ChaincodeInvokeRequest{}, errors.Wrap(err, "failed to create timestamp in install proposal")
+		return nil, errors.Wrap(err, "failed to create timestamp in chaincode deployment spec")
 	}
 
 	ccds := &pb.ChaincodeDeploymentSpec{ChaincodeSpec: &pb.ChaincodeSpec{
 		Type: request.Package.Type, ChaincodeId: &pb.ChaincodeID{Name: request.Name, Path: request.Path, Version: request.Version}},
 		CodePackage: request.Package.Code, EffectiveDate: ts}
+	return ccds, nil
+}
+
+func createInstallInvokeRequest(request ChaincodeInstallRequest) (fab.ChaincodeInvokeRequest, error) {
+	// Generate arguments for install
+	args := [][]byte{}
+
+	var ccpack proto.Message
+	if request.SignedPackage != nil {
+		// The peer accepts the signed chaincode deployment package in place of the deployment spec
+		ccpack = request.SignedPackage
+	} else if request.DeploymentSpec != nil {
+		ccpack = request.DeploymentSpec
+	} else {
+		ccds, err := CreateChaincodeDeploymentSpec(request)
+		if err != nil {
+			return fab.ChaincodeInvokeRequest{}, err
+		}
+		ccpack = ccds
+	}
 
-	ccdsBytes, err := protos_utils.Marshal(ccds)
+	ccpackBytes, err := protos_utils.Marshal(ccpack)
 	if err != nil {
 		return fab.ChaincodeInvokeRequest{}, errors.WithMessage(err, "marshal of chaincode deployment spec failed")
 	}
-	args = append(args, ccdsBytes)
+	args = append(args, ccpackBytes)
 
 	cir := fab.ChaincodeInvokeRequest{
 		ChaincodeID: lscc,
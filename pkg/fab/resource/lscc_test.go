@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package resource
 
 import (
+	reqContext "context"
 	"testing"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
@@ -33,6 +34,6 @@ func TestCreateChaincodeInstallProposal(t *testing.T) {
 	prop, err := CreateChaincodeInstallProposal(txid, request)
 	assert.Nil(t, err, "CreateChaincodeInstallProposal failed")
 
-	_, err = txn.SendProposal(c.clientContext, prop, []fab.ProposalProcessor{&peer})
+	_, err = txn.SendProposal(reqContext.Background(), c.clientContext, prop, []fab.ProposalProcessor{&peer})
 	assert.Nil(t, err, "sending mock proposal failed")
 }
@@ -8,6 +8,7 @@ SPDX-License-Identifier: Apache-2.0
 package resource
 
 import (
+	reqContext "context"
 	"net/http"
 
 	"github.com/golang/protobuf/proto"
@@ -107,7 +108,7 @@ func (c *Resource) createChannelFromEnvelope(request api.CreateChannelRequest) (
 	}
 
 	// Send request
-	_, err = request.Orderer.SendBroadcast(env)
+	_, err = request.Orderer.SendBroadcast(reqContext.Background(), env)
 	if err != nil {
 		return fab.EmptyTransactionID, errors.WithMessage(err, "failed broadcast to orderer")
 	}
@@ -117,7 +118,18 @@ func (c *Resource) createChannelFromEnvelope(request api.CreateChannelRequest) (
 // GenesisBlockFromOrderer returns the genesis block from the defined orderer that may be
 // used in a join request
 func (c *Resource) GenesisBlockFromOrderer(channelName string, orderer fab.Orderer) (*common.Block, error) {
-	return c.block([]fab.Orderer{orderer}, channelName, newSpecificSeekPosition(0))
+	return c.block(reqContext.Background(), []fab.Orderer{orderer}, channelName, newSpecificSeekPosition(0))
+}
+
+// BlockFromOrderer returns the block with the given number from the given orderer.
+// The orderer waits for the block if it isn't committed yet, until the request context is done
+func (c *Resource) BlockFromOrderer(reqCtx reqContext.Context, channelName string, orderer fab.Orderer, blockNumber uint64) (*common.Block, error) {
+	return c.block(reqCtx, []fab.Orderer{orderer}, channelName, newSpecificSeekPosition(blockNumber))
+}
+
+// NewestBlockFromOrderer returns the newest block of the channel from the given orderer
+func (c *Resource) NewestBlockFromOrderer(reqCtx reqContext.Context, channelName string, orderer fab.Orderer) (*common.Block, error) {
+	return c.block(reqCtx, []fab.Orderer{orderer}, channelName, newNewestSeekPosition())
 }
 
 // LastConfigFromOrderer fetches the current configuration block for the specified channel
@@ -126,7 +138,7 @@ func (c *Resource) LastConfigFromOrderer(channelName string, orderer fab.Orderer
 	logger.Debugf("channelConfig - start for channel %s", channelName)
 
 	// Get the newest block
-	block, err := c.block([]fab.Orderer{orderer}, channelName, newNewestSeekPosition())
+	block, err := c.block(reqContext.Background(), []fab.Orderer{orderer}, channelName, newNewestSeekPosition())
 	if err != nil {
 		return nil, err
 	}
@@ -140,7 +152,7 @@ func (c *Resource) LastConfigFromOrderer(channelName string, orderer fab.Orderer
 	logger.Debugf("channelConfig - Last config index: %d\n", lastConfig.Index)
 
 	// Get the last config block
-	block, err = c.block([]fab.Orderer{orderer}, channelName, newSpecificSeekPosition(lastConfig.Index))
+	block, err = c.block(reqContext.Background(), []fab.Orderer{orderer}, channelName, newSpecificSeekPosition(lastConfig.Index))
 	if err != nil {
 		return nil, errors.WithMessage(err, "retrieve block failed")
 	}
@@ -210,7 +222,7 @@ func (c *Resource) createOrUpdateChannel(txh *txn.TransactionHeader, request api
 		return errors.WithMessage(err, "CreatePayload failed")
 	}
 
-	_, err = txn.BroadcastPayload(c.clientContext, payload, []fab.Orderer{request.Orderer})
+	_, err = txn.BroadcastPayload(reqContext.Background(), c.clientContext, payload, []fab.Orderer{request.Orderer})
 	if err != nil {
 		return errors.WithMessage(err, "SendEnvelope failed")
 	}
@@ -273,18 +285,22 @@ func (c *Resource) InstallChaincode(req api.InstallChaincodeRequest) ([]*fab.Tra
 	if req.Version == "" {
 		return nil, fab.EmptyTransactionID, errors.New("chaincode version required")
 	}
-	if req.Package == nil {
+	if req.Package == nil && req.DeploymentSpec == nil && req.SignedPackage == nil {
 		return nil, fab.EmptyTransactionID, errors.New("chaincode package is required")
 	}
 
 	propReq := ChaincodeInstallRequest{
-		Name:    req.Name,
-		Path:    req.Path,
-		Version: req.Version,
-		Package: &ChaincodePackage{
+		Name:           req.Name,
+		Path:           req.Path,
+		Version:        req.Version,
+		DeploymentSpec: req.DeploymentSpec,
+		SignedPackage:  req.SignedPackage,
+	}
+	if req.Package != nil {
+		propReq.Package = &ChaincodePackage{
 			Type: req.Package.Type,
 			Code: req.Package.Code,
-		},
+		}
 	}
 
 	txh, err := txn.NewHeader(c.clientContext, fab.SystemChannel)
@@ -297,7 +313,7 @@ func (c *Resource) InstallChaincode(req api.InstallChaincodeRequest) ([]*fab.Tra
 		return nil, fab.EmptyTransactionID, errors.WithMessage(err, "creation of install chaincode proposal failed")
 	}
 
-	transactionProposalResponse, err := txn.SendProposal(c.clientContext, prop, req.Targets)
+	transactionProposalResponse, err := txn.SendProposal(reqContext.Background(), c.clientContext, prop, req.Targets)
 
 	return transactionProposalResponse, prop.TxnID, err
 }
@@ -331,7 +347,7 @@ func (c *Resource) queryChaincodeWithTarget(request fab.ChaincodeInvokeRequest,
 		return nil, errors.WithMessage(err, "NewProposal failed")
 	}
 
-	tpr, err := txn.SendProposal(c.clientContext, tp, targets)
+	tpr, err := txn.SendProposal(reqContext.Background(), c.clientContext, tp, targets)
 	if err != nil {
 		return nil, errors.WithMessage(err, "SendProposal failed")
 	}
@@ -17,6 +17,7 @@ import (
 
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/crypto"
 	contextApi "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
@@ -29,6 +30,7 @@ type TransactionHeader struct {
 	creator   []byte
 	nonce     []byte
 	channelID string
+	signer    fab.IdentityContext
 }
 
 // TransactionID returns the transaction's computed identifier.
@@ -53,14 +55,25 @@ func (th *TransactionHeader) ChannelID() string {
 
 // NewHeader computes a TransactionID from the current user context and holds
 // metadata to create transaction proposals.
-func NewHeader(ctx contextApi.Client, channelID string) (*TransactionHeader, error) {
+// The identity of the context creates the transaction unless another one is given with fab.WithIdentity.
+func NewHeader(ctx contextApi.Client, channelID string, opts ...fab.TxnHeaderOpt) (*TransactionHeader, error) {
+	options := fab.TxnHeaderOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	var identity fab.IdentityContext = ctx
+	if options.Identity != nil {
+		identity = options.Identity
+	}
+
 	// generate a random nonce
 	nonce, err := crypto.GetRandomNonce()
 	if err != nil {
 		return nil, errors.WithMessage(err, "nonce creation failed")
 	}
 
-	creator, err := ctx.SerializedIdentity()
+	creator, err := identity.SerializedIdentity()
 	if err != nil {
 		return nil, errors.WithMessage(err, "identity from context failed")
 	}
@@ -81,6 +94,7 @@ func NewHeader(ctx contextApi.Client, channelID string) (*TransactionHeader, err
 		creator:   creator,
 		nonce:     nonce,
 		channelID: channelID,
+		signer:    options.Identity,
 	}
 
 	return &txnID, nil
@@ -99,6 +113,20 @@ func computeTxnID(nonce, creator []byte, h hash.Hash) (string, error) {
 	return id, nil
 }
 
+// signingContext replaces the identity of a context with the identity signing a transaction
+type signingContext struct {
+	core.Providers
+	contextApi.Identity
+}
+
+// withSigner returns the context signing with the given identity, if any
+func withSigner(ctx context, signer fab.IdentityContext) context {
+	if signer == nil {
+		return ctx
+	}
+	return &signingContext{Providers: ctx, Identity: signer}
+}
+
 // signPayload signs payload
 func signPayload(ctx context, payload *common.Payload) (*fab.SignedEnvelope, error) {
 	payloadBytes, err := proto.Marshal(payload)
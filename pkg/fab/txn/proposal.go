@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package txn
 
 import (
+	reqContext "context"
 	"sync"
 
 	"github.com/golang/protobuf/proto"
@@ -49,11 +50,20 @@ func CreateChaincodeInvokeProposal(txh fab.TransactionHeader, request fab.Chainc
 	tp := fab.TransactionProposal{
 		TxnID:    txh.TransactionID(),
 		Proposal: proposal,
+		Signer:   signer(txh),
 	}
 
 	return &tp, nil
 }
 
+// signer returns the identity that was given to create the transaction header, if any
+func signer(txh fab.TransactionHeader) fab.IdentityContext {
+	if th, ok := txh.(*TransactionHeader); ok && th.signer != nil {
+		return th.signer
+	}
+	return nil
+}
+
 // signProposal creates a SignedProposal based on the current context.
 func signProposal(ctx context, proposal *pb.Proposal) (*pb.SignedProposal, error) {
 	proposalBytes, err := proto.Marshal(proposal)
@@ -75,7 +85,8 @@ func signProposal(ctx context, proposal *pb.Proposal) (*pb.SignedProposal, error
 }
 
 // SendProposal sends a TransactionProposal to ProposalProcessor.
-func SendProposal(ctx context, proposal *fab.TransactionProposal, targets []fab.ProposalProcessor) ([]*fab.TransactionProposalResponse, error) {
+// Outstanding requests are aborted when reqCtx is cancelled or its deadline expires.
+func SendProposal(reqCtx reqContext.Context, ctx context, proposal *fab.TransactionProposal, targets []fab.ProposalProcessor) ([]*fab.TransactionProposalResponse, error) {
 
 	if proposal == nil {
 		return nil, errors.New("proposal is required")
@@ -85,7 +96,7 @@ func SendProposal(ctx context, proposal *fab.TransactionProposal, targets []fab.
 		return nil, errors.New("targets is required")
 	}
 
-	signedProposal, err := signProposal(ctx, proposal.Proposal)
+	signedProposal, err := signProposal(withSigner(ctx, proposal.Signer), proposal.Proposal)
 	if err != nil {
 		return nil, errors.WithMessage(err, "sign proposal failed")
 	}
@@ -102,7 +113,7 @@ func SendProposal(ctx context, proposal *fab.TransactionProposal, targets []fab.
 		go func(processor fab.ProposalProcessor) {
 			defer wg.Done()
 
-			resp, err := processor.ProcessTransactionProposal(request)
+			resp, err := processor.ProcessTransactionProposal(reqCtx, request)
 			if err != nil {
 				logger.Debugf("Received error response from txn proposal processing: %v", err)
 				responseMtx.Lock()
@@ -6,6 +6,7 @@ SPDX-License-Identifier: Apache-2.0
 package txn
 
 import (
+	reqContext "context"
 	"fmt"
 	"reflect"
 	"testing"
@@ -14,6 +15,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
 	mock_context "github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab/mocks"
 	"github.com/hyperledger/fabric-sdk-go/pkg/errors/multi"
@@ -81,7 +83,7 @@ func TestSendTransactionProposal(t *testing.T) {
 		t.Fatalf("new transaction proposal failed: %s", err)
 	}
 
-	tpr, err := SendProposal(ctx, tp, []fab.ProposalProcessor{&peer})
+	tpr, err := SendProposal(reqContext.Background(), ctx, tp, []fab.ProposalProcessor{&peer})
 	if err != nil {
 		t.Fatalf("send transaction proposal failed: %s", err)
 	}
@@ -112,7 +114,7 @@ func TestNewTransactionProposalParams(t *testing.T) {
 		t.Fatalf("new transaction proposal failed: %s", err)
 	}
 
-	_, err = SendProposal(ctx, tp, nil)
+	_, err = SendProposal(reqContext.Background(), ctx, tp, nil)
 	if err == nil {
 		t.Fatalf("Expected error")
 	}
@@ -145,6 +147,102 @@ func TestNewTransactionProposalParams(t *testing.T) {
 	}
 }
 
+func TestNewTransactionProposalWithIdentity(t *testing.T) {
+	user := mocks.NewMockUserWithMSPID("test", "1234")
+	ctx := &mocks.MockContext{
+		MockProviderContext: mocks.NewMockProviderContextCustom(mocks.NewMockConfig(), &mocks.MockCryptoSuite{}, &skiSigningManager{}, &mocks.MockStateStore{}, nil),
+		Identity:            user,
+	}
+	other := &testIdentity{mspID: "Org2MSP", serialized: []byte("other"), key: &testKey{ski: []byte("otherkey")}}
+
+	request := fab.ChaincodeInvokeRequest{
+		ChaincodeID: "cc",
+		Fcn:         "Hello",
+	}
+
+	txh, err := NewHeader(ctx, testChannel, fab.WithIdentity(other))
+	if err != nil {
+		t.Fatalf("create transaction ID failed: %s", err)
+	}
+	assert.Equal(t, other.serialized, txh.Creator())
+
+	tp, err := CreateChaincodeInvokeProposal(txh, request)
+	if err != nil {
+		t.Fatalf("new transaction proposal failed: %s", err)
+	}
+	assert.Equal(t, other, tp.Signer)
+
+	signedProposal, err := signProposal(withSigner(ctx, tp.Signer), tp.Proposal)
+	if err != nil {
+		t.Fatalf("signProposal failed: %s", err)
+	}
+	assert.Equal(t, other.key.SKI(), signedProposal.Signature, "expected proposal to be signed by the given identity")
+
+	// Without an identity the proposal is signed by the identity of the context
+	txh, err = NewHeader(ctx, testChannel)
+	if err != nil {
+		t.Fatalf("create transaction ID failed: %s", err)
+	}
+	tp, err = CreateChaincodeInvokeProposal(txh, request)
+	if err != nil {
+		t.Fatalf("new transaction proposal failed: %s", err)
+	}
+	assert.Nil(t, tp.Signer)
+	assert.Equal(t, ctx, withSigner(ctx, tp.Signer))
+}
+
+type testIdentity struct {
+	mspID      string
+	serialized []byte
+	key        core.Key
+}
+
+func (i *testIdentity) MspID() string {
+	return i.mspID
+}
+
+func (i *testIdentity) SerializedIdentity() ([]byte, error) {
+	return i.serialized, nil
+}
+
+func (i *testIdentity) PrivateKey() core.Key {
+	return i.key
+}
+
+type testKey struct {
+	ski []byte
+}
+
+func (k *testKey) Bytes() ([]byte, error) {
+	return k.ski, nil
+}
+
+func (k *testKey) SKI() []byte {
+	return k.ski
+}
+
+func (k *testKey) Symmetric() bool {
+	return false
+}
+
+func (k *testKey) Private() bool {
+	return true
+}
+
+func (k *testKey) PublicKey() (core.Key, error) {
+	return k, nil
+}
+
+// skiSigningManager "signs" with the SKI of the key so that tests can tell which key signed
+type skiSigningManager struct{}
+
+func (m *skiSigningManager) Sign(object []byte, key core.Key) ([]byte, error) {
+	if key == nil {
+		return nil, nil
+	}
+	return key.SKI(), nil
+}
+
 func TestConcurrentPeers(t *testing.T) {
 	const numPeers = 10000
 	peers := setupMassiveTestPeers(numPeers)
@@ -152,7 +250,7 @@ func TestConcurrentPeers(t *testing.T) {
 	user := mocks.NewMockUserWithMSPID("test", "1234")
 	ctx := mocks.NewMockContext(user)
 
-	result, err := SendProposal(ctx, &fab.TransactionProposal{
+	result, err := SendProposal(reqContext.Background(), ctx, &fab.TransactionProposal{
 		Proposal: &pb.Proposal{},
 	}, peers)
 	if err != nil {
@@ -182,10 +280,10 @@ func TestSendTransactionProposalToProcessors(t *testing.T) {
 	}
 
 	tpr := fab.TransactionProposalResponse{Endorser: "example.com", Status: 99}
-	proc.EXPECT().ProcessTransactionProposal(tp).Return(&tpr, nil)
+	proc.EXPECT().ProcessTransactionProposal(gomock.Any(), tp).Return(&tpr, nil)
 	targets := []fab.ProposalProcessor{proc}
 
-	result, err := SendProposal(ctx, &fab.TransactionProposal{
+	result, err := SendProposal(reqContext.Background(), ctx, &fab.TransactionProposal{
 		Proposal: &pb.Proposal{},
 	}, nil)
 
@@ -193,7 +291,7 @@ func TestSendTransactionProposalToProcessors(t *testing.T) {
 		t.Fatalf("Test SendTransactionProposal failed, validation on peer is nil is not working as expected: %v", err)
 	}
 
-	result, err = SendProposal(ctx, &fab.TransactionProposal{
+	result, err = SendProposal(reqContext.Background(), ctx, &fab.TransactionProposal{
 		Proposal: &pb.Proposal{},
 	}, []fab.ProposalProcessor{})
 
@@ -201,7 +299,7 @@ func TestSendTransactionProposalToProcessors(t *testing.T) {
 		t.Fatalf("Test SendTransactionProposal failed, validation on missing peer objects is not working: %v", err)
 	}
 
-	result, err = SendProposal(ctx, &fab.TransactionProposal{
+	result, err = SendProposal(reqContext.Background(), ctx, &fab.TransactionProposal{
 		Proposal: &pb.Proposal{}}, targets)
 
 	if result == nil || err != nil {
@@ -230,11 +328,11 @@ func TestProposalResponseError(t *testing.T) {
 
 	// Test with error from lower layer
 	tpr := fab.TransactionProposalResponse{Endorser: "example.com", Status: 200}
-	proc.EXPECT().ProcessTransactionProposal(tp).Return(&tpr, testError)
-	proc2.EXPECT().ProcessTransactionProposal(tp).Return(&tpr, testError)
+	proc.EXPECT().ProcessTransactionProposal(gomock.Any(), tp).Return(&tpr, testError)
+	proc2.EXPECT().ProcessTransactionProposal(gomock.Any(), tp).Return(&tpr, testError)
 
 	targets := []fab.ProposalProcessor{proc, proc2}
-	_, err = SendProposal(ctx, &fab.TransactionProposal{
+	_, err = SendProposal(reqContext.Background(), ctx, &fab.TransactionProposal{
 		Proposal: &pb.Proposal{},
 	}, targets)
 	errs, ok := err.(multi.Errors)
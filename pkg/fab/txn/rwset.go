@@ -0,0 +1,98 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txn
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	protos_utils "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+// TxRWSet is a decoded view of the read/write set produced by a chaincode invocation.
+type TxRWSet struct {
+	NsRWSets       []*NsRWSet
+	ChaincodeEvent *pb.ChaincodeEvent
+}
+
+// NsRWSet contains the reads, writes, deletes and range queries performed on a single namespace (chaincode).
+type NsRWSet struct {
+	Namespace    string
+	Reads        []*kvrwset.KVRead
+	Writes       []*kvrwset.KVWrite
+	Deletes      []string
+	RangeQueries []*kvrwset.RangeQueryInfo
+}
+
+// NsRWSet returns the read/write set of the given namespace or nil if the namespace was not touched.
+func (s *TxRWSet) NsRWSet(namespace string) *NsRWSet {
+	for _, nsRWSet := range s.NsRWSets {
+		if nsRWSet.Namespace == namespace {
+			return nsRWSet
+		}
+	}
+	return nil
+}
+
+// DecodeProposalResponsePayload decodes the read/write set and chaincode event
+// from the payload of a proposal response.
+func DecodeProposalResponsePayload(payload []byte) (*TxRWSet, error) {
+	prp, err := protos_utils.GetProposalResponsePayload(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal of proposal response payload failed")
+	}
+
+	action, err := protos_utils.GetChaincodeAction(prp.Extension)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal of chaincode action failed")
+	}
+
+	return DecodeChaincodeAction(action)
+}
+
+// DecodeChaincodeAction decodes the read/write set and chaincode event of a chaincode action.
+func DecodeChaincodeAction(action *pb.ChaincodeAction) (*TxRWSet, error) {
+	txRwSet := &rwsetutil.TxRwSet{}
+	if err := txRwSet.FromProtoBytes(action.Results); err != nil {
+		return nil, errors.Wrap(err, "unmarshal of read/write set failed")
+	}
+
+	decoded := &TxRWSet{}
+	for _, nsRwSet := range txRwSet.NsRwSets {
+		decoded.NsRWSets = append(decoded.NsRWSets, newNsRWSet(nsRwSet))
+	}
+
+	if len(action.Events) > 0 {
+		event, err := protos_utils.GetChaincodeEvents(action.Events)
+		if err != nil {
+			return nil, errors.Wrap(err, "unmarshal of chaincode event failed")
+		}
+		decoded.ChaincodeEvent = event
+	}
+
+	return decoded, nil
+}
+
+func newNsRWSet(nsRwSet *rwsetutil.NsRwSet) *NsRWSet {
+	decoded := &NsRWSet{Namespace: nsRwSet.NameSpace}
+	if nsRwSet.KvRwSet == nil {
+		return decoded
+	}
+
+	decoded.Reads = nsRwSet.KvRwSet.Reads
+	decoded.RangeQueries = nsRwSet.KvRwSet.RangeQueriesInfo
+	for _, write := range nsRwSet.KvRwSet.Writes {
+		if write.IsDelete {
+			decoded.Deletes = append(decoded.Deletes, write.Key)
+			continue
+		}
+		decoded.Writes = append(decoded.Writes, write)
+	}
+	return decoded
+}
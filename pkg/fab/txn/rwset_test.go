@@ -0,0 +1,94 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txn
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+func TestDecodeProposalResponsePayload(t *testing.T) {
+	txRwSet := &rwsetutil.TxRwSet{
+		NsRwSets: []*rwsetutil.NsRwSet{
+			{NameSpace: "lscc", KvRwSet: &kvrwset.KVRWSet{}},
+			{NameSpace: "example_cc", KvRwSet: &kvrwset.KVRWSet{
+				Reads:            []*kvrwset.KVRead{{Key: "a", Version: &kvrwset.Version{BlockNum: 3, TxNum: 1}}},
+				RangeQueriesInfo: []*kvrwset.RangeQueryInfo{{StartKey: "a", EndKey: "z", ItrExhausted: true}},
+				Writes: []*kvrwset.KVWrite{
+					{Key: "b", Value: []byte("200")},
+					{Key: "c", IsDelete: true},
+				},
+			}},
+		},
+	}
+	results, err := txRwSet.ToProtoBytes()
+	if err != nil {
+		t.Fatalf("marshal of read/write set failed: %s", err)
+	}
+
+	event, err := proto.Marshal(&pb.ChaincodeEvent{ChaincodeId: "example_cc", EventName: "moved"})
+	if err != nil {
+		t.Fatalf("marshal of chaincode event failed: %s", err)
+	}
+
+	extension, err := proto.Marshal(&pb.ChaincodeAction{Results: results, Events: event})
+	if err != nil {
+		t.Fatalf("marshal of chaincode action failed: %s", err)
+	}
+
+	payload, err := proto.Marshal(&pb.ProposalResponsePayload{Extension: extension})
+	if err != nil {
+		t.Fatalf("marshal of proposal response payload failed: %s", err)
+	}
+
+	decoded, err := DecodeProposalResponsePayload(payload)
+	if err != nil {
+		t.Fatalf("DecodeProposalResponsePayload failed: %s", err)
+	}
+
+	assert.Len(t, decoded.NsRWSets, 2)
+	assert.Nil(t, decoded.NsRWSet("unknown"))
+
+	lscc := decoded.NsRWSet("lscc")
+	if lscc == nil {
+		t.Fatal("expected read/write set for lscc")
+	}
+	assert.Empty(t, lscc.Reads)
+
+	ns := decoded.NsRWSet("example_cc")
+	if ns == nil {
+		t.Fatal("expected read/write set for example_cc")
+	}
+	assert.Len(t, ns.Reads, 1)
+	assert.Equal(t, "a", ns.Reads[0].Key)
+	assert.EqualValues(t, 3, ns.Reads[0].Version.BlockNum)
+	assert.Len(t, ns.Writes, 1)
+	assert.Equal(t, "b", ns.Writes[0].Key)
+	assert.Equal(t, []byte("200"), ns.Writes[0].Value)
+	assert.Equal(t, []string{"c"}, ns.Deletes)
+	assert.Len(t, ns.RangeQueries, 1)
+	assert.Equal(t, "z", ns.RangeQueries[0].EndKey)
+
+	if decoded.ChaincodeEvent == nil {
+		t.Fatal("expected chaincode event")
+	}
+	assert.Equal(t, "moved", decoded.ChaincodeEvent.EventName)
+}
+
+func TestDecodeProposalResponsePayloadInvalid(t *testing.T) {
+	_, err := DecodeProposalResponsePayload([]byte("invalid"))
+	assert.NotNil(t, err, "expected error for invalid payload")
+
+	_, err = DecodeChaincodeAction(&pb.ChaincodeAction{Results: []byte("invalid")})
+	assert.NotNil(t, err, "expected error for invalid read/write set")
+}
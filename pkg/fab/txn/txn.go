@@ -9,15 +9,17 @@ package txn
 
 import (
 	"bytes"
-	"math/rand"
+	reqContext "context"
 	"sync"
-	"time"
 
 	"github.com/pkg/errors"
 
 	contextApi "github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/orderer/greylist"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/orderer/lbp"
 	"github.com/hyperledger/fabric-sdk-go/pkg/logging"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
@@ -40,6 +42,31 @@ type context interface {
 	contextApi.Identity
 }
 
+// BroadcastOptions contains the options for broadcasting to the ordering service
+type BroadcastOptions struct {
+	// Policy orders the orderers that are tried in turn; they are tried in random order if nil
+	Policy lbp.LoadBalancePolicy
+	// Greylist skips the orderers that failed recently and records the ones that fail, if set
+	Greylist *greylist.Filter
+}
+
+// BroadcastOpt is a broadcast option
+type BroadcastOpt func(opts *BroadcastOptions)
+
+// WithOrdererPolicy sets the load-balance policy that orders the orderers a broadcast tries
+func WithOrdererPolicy(policy lbp.LoadBalancePolicy) BroadcastOpt {
+	return func(opts *BroadcastOptions) {
+		opts.Policy = policy
+	}
+}
+
+// WithOrdererGreylist sets the greylist of the orderers a broadcast tries
+func WithOrdererGreylist(filter *greylist.Filter) BroadcastOpt {
+	return func(opts *BroadcastOptions) {
+		opts.Greylist = filter
+	}
+}
+
 // New create a transaction with proposal response, following the endorsement policy.
 func New(request fab.TransactionRequest) (*fab.Transaction, error) {
 	if len(request.ProposalResponses) == 0 {
@@ -110,7 +137,7 @@ func New(request fab.TransactionRequest) (*fab.Transaction, error) {
 }
 
 // Send send a transaction to the chain’s orderer service (one or more orderer endpoints) for consensus and committing to the ledger.
-func Send(ctx context, tx *fab.Transaction, orderers []fab.Orderer) (*fab.TransactionResponse, error) {
+func Send(reqCtx reqContext.Context, ctx context, tx *fab.Transaction, orderers []fab.Orderer, opts ...BroadcastOpt) (*fab.TransactionResponse, error) {
 	if orderers == nil || len(orderers) == 0 {
 		return nil, errors.New("orderers is nil")
 	}
@@ -135,7 +162,7 @@ func Send(ctx context, tx *fab.Transaction, orderers []fab.Orderer) (*fab.Transa
 	// create the payload
 	payload := common.Payload{Header: hdr, Data: txBytes}
 
-	transactionResponse, err := BroadcastPayload(ctx, &payload, orderers)
+	transactionResponse, err := BroadcastPayload(reqCtx, withSigner(ctx, tx.Proposal.Signer), &payload, orderers, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -143,9 +170,9 @@ func Send(ctx context, tx *fab.Transaction, orderers []fab.Orderer) (*fab.Transa
 	return transactionResponse, nil
 }
 
-// BroadcastPayload will send the given payload to some orderer, picking random endpoints
-// until all are exhausted
-func BroadcastPayload(ctx context, payload *common.Payload, orderers []fab.Orderer) (*fab.TransactionResponse, error) {
+// BroadcastPayload will send the given payload to some orderer, picking endpoints
+// in the order of the load-balance policy (random by default) until all are exhausted
+func BroadcastPayload(reqCtx reqContext.Context, ctx context, payload *common.Payload, orderers []fab.Orderer, opts ...BroadcastOpt) (*fab.TransactionResponse, error) {
 	// Check if orderers are defined
 	if len(orderers) == 0 {
 		return nil, errors.New("orderers not set")
@@ -156,29 +183,43 @@ func BroadcastPayload(ctx context, payload *common.Payload, orderers []fab.Order
 		return nil, err
 	}
 
-	return broadcastEnvelope(ctx, envelope, orderers)
+	return broadcastEnvelope(reqCtx, envelope, orderers, opts...)
 }
 
-// broadcastEnvelope will send the given envelope to some orderer, picking random endpoints
-// until all are exhausted
-func broadcastEnvelope(ctx context, envelope *fab.SignedEnvelope, orderers []fab.Orderer) (*fab.TransactionResponse, error) {
+// broadcastEnvelope will send the given envelope to some orderer, picking endpoints
+// in the order of the load-balance policy until all are exhausted
+func broadcastEnvelope(reqCtx reqContext.Context, envelope *fab.SignedEnvelope, orderers []fab.Orderer, opts ...BroadcastOpt) (*fab.TransactionResponse, error) {
 	// Check if orderers are defined
 	if len(orderers) == 0 {
 		return nil, errors.New("orderers not set")
 	}
 
-	// Copy aside the ordering service endpoints
-	randOrderers := []fab.Orderer{}
-	for _, o := range orderers {
-		randOrderers = append(randOrderers, o)
+	options := BroadcastOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	policy := options.Policy
+	if policy == nil {
+		policy = lbp.NewRandom()
 	}
 
-	// Iterate them in a random order and try broadcasting 1 by 1
+	candidates := orderers
+	if options.Greylist != nil {
+		candidates = options.Greylist.Filter(orderers)
+	}
+
+	// Iterate them in the order of the policy and try broadcasting 1 by 1
 	var errResp error
-	for _, i := range rand.Perm(len(randOrderers)) {
-		resp, err := sendBroadcast(envelope, randOrderers[i])
+	for _, o := range policy.Order(candidates) {
+		if reqCtx.Err() != nil {
+			return nil, errors.Wrap(reqCtx.Err(), "broadcast aborted")
+		}
+		resp, err := sendBroadcast(reqCtx, envelope, o)
 		if err != nil {
 			errResp = err
+			if options.Greylist != nil {
+				options.Greylist.Greylist(err)
+			}
 		} else {
 			return resp, nil
 		}
@@ -186,9 +227,9 @@ func broadcastEnvelope(ctx context, envelope *fab.SignedEnvelope, orderers []fab
 	return nil, errResp
 }
 
-func sendBroadcast(envelope *fab.SignedEnvelope, orderer fab.Orderer) (*fab.TransactionResponse, error) {
+func sendBroadcast(reqCtx reqContext.Context, envelope *fab.SignedEnvelope, orderer fab.Orderer) (*fab.TransactionResponse, error) {
 	logger.Debugf("Broadcasting envelope to orderer :%s\n", orderer.URL())
-	if _, err := orderer.SendBroadcast(envelope); err != nil {
+	if _, err := orderer.SendBroadcast(reqCtx, envelope); err != nil {
 		logger.Debugf("Receive Error Response from orderer :%v\n", err)
 		return nil, errors.Wrapf(err, "calling orderer '%s' failed", orderer.URL())
 	}
@@ -198,7 +239,7 @@ func sendBroadcast(envelope *fab.SignedEnvelope, orderer fab.Orderer) (*fab.Tran
 }
 
 // SendPayload sends the given payload to each orderer and returns a block response
-func SendPayload(ctx context, payload *common.Payload, orderers []fab.Orderer) (*common.Block, error) {
+func SendPayload(reqCtx reqContext.Context, ctx context, payload *common.Payload, orderers []fab.Orderer) (*common.Block, error) {
 	if orderers == nil || len(orderers) == 0 {
 		return nil, errors.New("orderers not set")
 	}
@@ -208,11 +249,11 @@ func SendPayload(ctx context, payload *common.Payload, orderers []fab.Orderer) (
 		return nil, err
 	}
 
-	return sendEnvelope(ctx, envelope, orderers)
+	return sendEnvelope(reqCtx, ctx, envelope, orderers)
 }
 
 // sendEnvelope sends the given envelope to each orderer and returns a block response
-func sendEnvelope(ctx context, envelope *fab.SignedEnvelope, orderers []fab.Orderer) (*common.Block, error) {
+func sendEnvelope(reqCtx reqContext.Context, ctx context, envelope *fab.SignedEnvelope, orderers []fab.Orderer) (*common.Block, error) {
 
 	var blockResponse *common.Block
 	var errorResponse error
@@ -226,7 +267,10 @@ func sendEnvelope(ctx context, envelope *fab.SignedEnvelope, orderers []fab.Orde
 		go func(orderer fab.Orderer) {
 			logger.Debugf("Broadcasting envelope to orderer :%s\n", orderer.URL())
 
-			blocks, errs, cancel := orderer.SendDeliver(envelope)
+			ordererCtx, cancelTimeout := reqContext.WithTimeout(reqCtx, ctx.Config().TimeoutOrDefault(core.OrdererResponse))
+			defer cancelTimeout()
+
+			blocks, errs, cancel := orderer.SendDeliver(ordererCtx, envelope)
 			defer cancel()
 
 			select {
@@ -249,10 +293,10 @@ func sendEnvelope(ctx context, envelope *fab.SignedEnvelope, orderers []fab.Orde
 				}
 				mutex.Unlock()
 
-			case <-time.After(ctx.Config().TimeoutOrDefault(core.OrdererResponse)):
+			case <-ordererCtx.Done():
 				mutex.Lock()
 				if errorResponse == nil {
-					errorResponse = errors.New("timeout waiting for response from orderer")
+					errorResponse = errors.Wrap(ordererCtx.Err(), "timeout waiting for response from orderer")
 				}
 				outstandingRequests--
 				if outstandingRequests == 0 {
@@ -279,21 +323,37 @@ func sendEnvelope(ctx context, envelope *fab.SignedEnvelope, orderers []fab.Orde
 
 // Status is the transaction status returned from eventhub tx events
 type Status struct {
-	Code  pb.TxValidationCode
-	Error error
+	Code        pb.TxValidationCode
+	BlockNumber uint64
+	Error       error
+}
+
+// NewStatus returns the transaction status of the given event service event.
+// The status contains an error if the transaction was invalidated.
+func NewStatus(event *fab.TxStatusEvent) Status {
+	txStatus := Status{Code: event.TxValidationCode, BlockNumber: event.BlockNumber}
+	if event.TxValidationCode != pb.TxValidationCode_VALID {
+		txStatus.Error = status.New(status.EventServerStatus, int32(event.TxValidationCode), "received invalid transaction", nil)
+	}
+	return txStatus
 }
 
 // RegisterStatus registers on the given eventhub for the given transaction id
 // returns a TxValidationCode channel which receives the validation code when the
 // transaction completes. If the code is TxValidationCode_VALID then
 // the transaction committed successfully, otherwise the code indicates the error
-// that occurred.
+// that occurred. The registration is removed once the status has been received.
 func RegisterStatus(txID fab.TransactionID, eventHub fab.EventHub) chan Status {
-	statusNotifier := make(chan Status)
-
-	eventHub.RegisterTxEvent(txID, func(txId fab.TransactionID, code pb.TxValidationCode, err error) {
-		logger.Debugf("Received code(%s) for txid(%s) and err(%s)\n", code, txId, err)
-		statusNotifier <- Status{Code: code, Error: err}
+	statusNotifier := make(chan Status, 1)
+
+	eventHub.RegisterTxBlockEvent(txID, func(txId fab.TransactionID, code pb.TxValidationCode, blockNum uint64, err error) {
+		logger.Debugf("Received code(%s) in block(%d) for txid(%s) and err(%s)\n", code, blockNum, txId, err)
+		eventHub.UnregisterTxEvent(txId)
+		select {
+		case statusNotifier <- Status{Code: code, BlockNumber: blockNum, Error: err}:
+		default:
+			logger.Debugf("Status for txid(%s) already delivered\n", txId)
+		}
 	})
 
 	return statusNotifier
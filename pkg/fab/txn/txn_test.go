@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package txn
 
 import (
+	reqContext "context"
 	"crypto/rand"
 	"fmt"
 	"os"
@@ -19,7 +20,10 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/errors/status"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/orderer/greylist"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/orderer/lbp"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 )
@@ -140,9 +144,6 @@ func (r *mockReader) Read(p []byte) (int, error) {
 }
 
 func TestBroadcastEnvelope(t *testing.T) {
-	user := mocks.NewMockUserWithMSPID("test", "1234")
-	ctx := mocks.NewMockContext(user)
-
 	lsnr1 := make(chan *fab.SignedEnvelope)
 	lsnr2 := make(chan *fab.SignedEnvelope)
 	//Create mock orderers
@@ -155,7 +156,7 @@ func TestBroadcastEnvelope(t *testing.T) {
 		Signature: []byte(""),
 		Payload:   []byte(""),
 	}
-	res, err := broadcastEnvelope(ctx, sigEnvelope, orderers)
+	res, err := broadcastEnvelope(reqContext.Background(), sigEnvelope, orderers)
 
 	if err != nil {
 		t.Fatalf("Test Broadcast Envelope Failed, cause %v %v", err, res)
@@ -185,7 +186,7 @@ func TestBroadcastEnvelope(t *testing.T) {
 	}
 	// It should always succeed even though one of them has failed
 	for i := 0; i < broadcastCount; i++ {
-		if res, err := broadcastEnvelope(ctx, sigEnvelope, orderers); err != nil {
+		if res, err := broadcastEnvelope(reqContext.Background(), sigEnvelope, orderers); err != nil {
 			t.Fatalf("Test Broadcast Envelope Failed, cause %v %v", err, res)
 		}
 	}
@@ -197,26 +198,90 @@ func TestBroadcastEnvelope(t *testing.T) {
 	}
 
 	for i := 0; i < broadcastCount; i++ {
-		_, err := broadcastEnvelope(ctx, sigEnvelope, orderers)
+		_, err := broadcastEnvelope(reqContext.Background(), sigEnvelope, orderers)
 		if !strings.Contains(err.Error(), "Service Unavailable") {
 			t.Fatal("Test Broadcast failed but didn't return the correct reason(should contain 'Service Unavailable')")
 		}
 	}
 
 	emptyOrderers := []fab.Orderer{}
-	_, err = broadcastEnvelope(ctx, sigEnvelope, emptyOrderers)
+	_, err = broadcastEnvelope(reqContext.Background(), sigEnvelope, emptyOrderers)
 
 	if err == nil || err.Error() != "orderers not set" {
 		t.Fatal("orderers not set validation on broadcast envelope is not working as expected")
 	}
 }
 
+func TestBroadcastEnvelopeWithOptions(t *testing.T) {
+	lsnr1 := make(chan *fab.SignedEnvelope, 10)
+	lsnr2 := make(chan *fab.SignedEnvelope, 10)
+	orderer1 := mocks.NewMockOrderer("grpc://orderer1:7050", lsnr1)
+	orderer2 := mocks.NewMockOrderer("grpc://orderer2:7050", lsnr2)
+	orderers := []fab.Orderer{orderer1, orderer2}
+
+	sigEnvelope := &fab.SignedEnvelope{
+		Signature: []byte(""),
+		Payload:   []byte(""),
+	}
+
+	filter := greylist.New(time.Minute)
+	opts := []BroadcastOpt{WithOrdererPolicy(lbp.NewPreferred("orderer2:7050")), WithOrdererGreylist(filter)}
+
+	// The preferred orderer is chosen
+	res, err := broadcastEnvelope(reqContext.Background(), sigEnvelope, orderers, opts...)
+	if err != nil {
+		t.Fatalf("Test Broadcast Envelope Failed, cause %v", err)
+	}
+	if res.Orderer != orderer2.URL() {
+		t.Fatalf("Expected preferred orderer to handle the broadcast but got %s", res.Orderer)
+	}
+
+	// The next orderer handles the broadcast if the preferred one fails, which is greylisted
+	orderer2.(mocks.MockOrderer).EnqueueSendBroadcastError(status.New(status.OrdererClientStatus, status.ConnectionFailed.ToInt32(), "unavailable", []interface{}{orderer2.URL()}))
+	res, err = broadcastEnvelope(reqContext.Background(), sigEnvelope, orderers, opts...)
+	if err != nil {
+		t.Fatalf("Test Broadcast Envelope Failed, cause %v", err)
+	}
+	if res.Orderer != orderer1.URL() {
+		t.Fatalf("Expected orderer1 to handle the broadcast but got %s", res.Orderer)
+	}
+	if filter.Accept(orderer2) {
+		t.Fatal("Expected failed orderer to be greylisted")
+	}
+
+	// The greylisted orderer is skipped
+	drain(lsnr1)
+	drain(lsnr2)
+	res, err = broadcastEnvelope(reqContext.Background(), sigEnvelope, orderers, opts...)
+	if err != nil {
+		t.Fatalf("Test Broadcast Envelope Failed, cause %v", err)
+	}
+	if res.Orderer != orderer1.URL() {
+		t.Fatalf("Expected greylisted orderer to be skipped but got %s", res.Orderer)
+	}
+	select {
+	case <-lsnr2:
+		t.Fatal("Expected greylisted orderer not to be called")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func drain(ch chan *fab.SignedEnvelope) {
+	for {
+		select {
+		case <-ch:
+		case <-time.After(100 * time.Millisecond):
+			return
+		}
+	}
+}
+
 func TestSendTransaction(t *testing.T) {
 	//Setup channel
 	user := mocks.NewMockUserWithMSPID("test", "1234")
 	ctx := mocks.NewMockContext(user)
 
-	response, err := Send(ctx, nil, nil)
+	response, err := Send(reqContext.Background(), ctx, nil, nil)
 
 	//Expect orderer is nil error
 	if response != nil || err == nil || err.Error() != "orderers is nil" {
@@ -228,7 +293,7 @@ func TestSendTransaction(t *testing.T) {
 	orderers := []fab.Orderer{orderer}
 
 	//Call Send Transaction with nil tx
-	response, err = Send(ctx, nil, orderers)
+	response, err = Send(reqContext.Background(), ctx, nil, orderers)
 
 	//Expect tx is nil error
 	if response != nil || err == nil || err.Error() != "transaction is nil" {
@@ -244,7 +309,7 @@ func TestSendTransaction(t *testing.T) {
 	}
 
 	//Call Send Transaction with nil proposal
-	response, err = Send(ctx, &txn, orderers)
+	response, err = Send(reqContext.Background(), ctx, &txn, orderers)
 
 	//Expect proposal is nil error
 	if response != nil || err == nil || err.Error() != "proposal is nil" {
@@ -259,7 +324,7 @@ func TestSendTransaction(t *testing.T) {
 		Transaction: &pb.Transaction{},
 	}
 	//Call Send Transaction
-	response, err = Send(ctx, &txn, orderers)
+	response, err = Send(reqContext.Background(), ctx, &txn, orderers)
 
 	//Expect header unmarshal error
 	if response != nil || err == nil || !strings.Contains(err.Error(), "unmarshal") {
@@ -275,7 +340,7 @@ func TestSendTransaction(t *testing.T) {
 	}
 
 	//Call Send Transaction
-	response, err = Send(ctx, &txn, orderers)
+	response, err = Send(reqContext.Background(), ctx, &txn, orderers)
 
 	if response == nil || err != nil {
 		t.Fatalf("Test SendTransaction failed, reason : '%s'", err.Error())
@@ -335,7 +400,7 @@ func TestConcurrentOrderers(t *testing.T) {
 		},
 		Transaction: &pb.Transaction{},
 	}
-	_, err = Send(ctx, &txn, orderers)
+	_, err = Send(reqContext.Background(), ctx, &txn, orderers)
 	if err != nil {
 		t.Fatalf("SendTransaction returned error: %s", err)
 	}
@@ -31,5 +31,5 @@ type ServiceProviderFactory interface {
 
 // SessionClientFactory allows overriding default clients and providers of a session
 type SessionClientFactory interface {
-	CreateChannelClient(sdk context.Providers, session context.Session, channelID string, targetFilter fab.TargetFilter) (*channel.Client, error)
+	CreateChannelClient(sdk context.Providers, session context.Session, channelID string, targetFilter fab.TargetFilter, opts ...channel.ClientOption) (*channel.Client, error)
 }
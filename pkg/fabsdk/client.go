@@ -8,6 +8,7 @@ package fabsdk
 
 import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/invoke"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/ledger"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/resmgmt"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
@@ -35,6 +36,8 @@ type ClientOption func(opts *clientOptions) error
 
 type clientOptions struct {
 	targetFilter fab.TargetFilter
+	interceptors []invoke.Interceptor
+	collConfig   invoke.CCCollectionConfigProvider
 }
 
 type clientProvider func() (*clientContext, error)
@@ -66,6 +69,24 @@ func WithTargetFilter(targetFilter fab.TargetFilter) ClientOption {
 	}
 }
 
+// WithInterceptors registers interceptors that are notified around every stage of the
+// invoke chain of the channel client.
+func WithInterceptors(interceptors ...invoke.Interceptor) ClientOption {
+	return func(opts *clientOptions) error {
+		opts.interceptors = append(opts.interceptors, interceptors...)
+		return nil
+	}
+}
+
+// WithCollectionConfigProvider sets the provider of the private data collection configuration
+// used by the channel client to select the endorsers of private data requests.
+func WithCollectionConfigProvider(provider invoke.CCCollectionConfigProvider) ClientOption {
+	return func(opts *clientOptions) error {
+		opts.collConfig = provider
+		return nil
+	}
+}
+
 // withConfig allows for overriding the configuration of the client.
 // TODO: This should be removed once the depreacted functions are removed.
 func withConfig(config core.Config) ContextOption {
@@ -192,10 +213,16 @@ func (c *ClientContext) Ledger(id string, opts ...ClientOption) (*ledger.Client,
 		return nil, err
 	}
 
+	channelService, err := p.providers.ChannelProvider().ChannelService(p.identity, id)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create channel service")
+	}
+
 	ctx := ledger.Context{
 		Providers:        p.providers,
 		Identity:         session,
 		DiscoveryService: discService,
+		ChannelService:   channelService,
 	}
 
 	return ledger.New(ctx, id, ledger.WithDefaultTargetFilter(o.targetFilter))
@@ -212,8 +239,16 @@ func (c *ClientContext) Channel(id string, opts ...ClientOption) (*channel.Clien
 	if err != nil {
 		return &channel.Client{}, errors.WithMessage(err, "unable to retrieve client options")
 	}
+	var chOpts []channel.ClientOption
+	if len(o.interceptors) > 0 {
+		chOpts = append(chOpts, channel.WithInterceptors(o.interceptors...))
+	}
+	if o.collConfig != nil {
+		chOpts = append(chOpts, channel.WithCollectionConfigProvider(o.collConfig))
+	}
+
 	session := newSession(p.identity, p.providers.ChannelProvider())
-	client, err := p.clientFactory.CreateChannelClient(p.providers, session, id, o.targetFilter)
+	client, err := p.clientFactory.CreateChannelClient(p.providers, session, id, o.targetFilter, chOpts...)
 	if err != nil {
 		return &channel.Client{}, errors.WithMessage(err, "failed to created new channel client")
 	}
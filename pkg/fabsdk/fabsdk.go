@@ -239,9 +239,16 @@ func initSDK(sdk *FabricSDK, opts []Option) error {
 	return nil
 }
 
+// closable is implemented by providers that maintain connections
+type closable interface {
+	Close()
+}
+
 // Close frees up caches and connections being maintained by the SDK
 func (sdk *FabricSDK) Close() {
-	// TODO: upcoming changes will have Close funcs being called from here.
+	if c, ok := sdk.channelProvider.(closable); ok {
+		c.Close()
+	}
 }
 
 // Config returns the SDK's configuration.
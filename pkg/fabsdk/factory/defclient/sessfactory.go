@@ -24,7 +24,7 @@ func NewSessionClientFactory() *SessionClientFactory {
 }
 
 // CreateChannelClient returns a client that can execute transactions on specified channel
-func (f *SessionClientFactory) CreateChannelClient(providers context.Providers, session context.Session, channelID string, targetFilter fab.TargetFilter) (*channel.Client, error) {
+func (f *SessionClientFactory) CreateChannelClient(providers context.Providers, session context.Session, channelID string, targetFilter fab.TargetFilter, opts ...channel.ClientOption) (*channel.Client, error) {
 
 	chProvider := providers.ChannelProvider()
 	chService, err := chProvider.ChannelService(session, channelID)
@@ -50,5 +50,5 @@ func (f *SessionClientFactory) CreateChannelClient(providers context.Providers,
 		SelectionService: selection,
 		ChannelService:   chService,
 	}
-	return channel.New(ctx)
+	return channel.New(ctx, opts...)
 }
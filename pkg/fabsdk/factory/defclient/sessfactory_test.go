@@ -11,6 +11,7 @@ package defclient
 import (
 	"testing"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/factory/defsvc"
 
@@ -48,6 +49,34 @@ func TestCreateChannelClient(t *testing.T) {
 
 }
 
+func TestCreateChannelClientOptions(t *testing.T) {
+	p := newMockProviders(t)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockSDK := mockapisdk.NewMockProviders(mockCtrl)
+
+	mockSDK.EXPECT().ChannelProvider().Return(p.ChannelProvider)
+	mockSDK.EXPECT().DiscoveryProvider().Return(p.DiscoveryProvider)
+	mockSDK.EXPECT().SelectionProvider().Return(p.SelectionProvider)
+	mockSDK.EXPECT().Config().Return(p.Config)
+
+	factory := NewSessionClientFactory()
+	session := newMockSession()
+
+	applied := false
+	_, err := factory.CreateChannelClient(mockSDK, session, "mychannel", nil, func(*channel.Client) error {
+		applied = true
+		return errors.New("option failed")
+	})
+	if err == nil {
+		t.Fatalf("Expected error of channel client option")
+	}
+	if !applied {
+		t.Fatalf("Expected channel client option to be applied")
+	}
+}
+
 func TestCreateChannelClientBadChannel(t *testing.T) {
 	p := newMockProviders(t)
 
@@ -175,14 +175,19 @@ func (m *MockSessionClientFactory) EXPECT() *MockSessionClientFactoryMockRecorde
 }
 
 // CreateChannelClient mocks base method
-func (m *MockSessionClientFactory) CreateChannelClient(arg0 context.Providers, arg1 context.Session, arg2 string, arg3 fab.TargetFilter) (*channel.Client, error) {
-	ret := m.ctrl.Call(m, "CreateChannelClient", arg0, arg1, arg2, arg3)
+func (m *MockSessionClientFactory) CreateChannelClient(arg0 context.Providers, arg1 context.Session, arg2 string, arg3 fab.TargetFilter, arg4 ...channel.ClientOption) (*channel.Client, error) {
+	varargs := []interface{}{arg0, arg1, arg2, arg3}
+	for _, a := range arg4 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateChannelClient", varargs...)
 	ret0, _ := ret[0].(*channel.Client)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateChannelClient indicates an expected call of CreateChannelClient
-func (mr *MockSessionClientFactoryMockRecorder) CreateChannelClient(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChannelClient", reflect.TypeOf((*MockSessionClientFactory)(nil).CreateChannelClient), arg0, arg1, arg2, arg3)
+func (mr *MockSessionClientFactoryMockRecorder) CreateChannelClient(arg0, arg1, arg2, arg3 interface{}, arg4 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1, arg2, arg3}, arg4...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChannelClient", reflect.TypeOf((*MockSessionClientFactory)(nil).CreateChannelClient), varargs...)
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/chconfig"
+	"github.com/pkg/errors"
 )
 
 // ChannelProvider keeps context across ChannelService instances.
@@ -23,14 +24,94 @@ import (
 type ChannelProvider struct {
 	fabricProvider fab.InfraProvider
 	chCfgMap       sync.Map
+	eventClientsMu sync.Mutex
+	eventClients   map[string]*eventClientRef
+}
+
+// eventClientRef is a shared event client with the number of event services that reference it
+type eventClientRef struct {
+	client fab.EventClient
+	refs   int
+}
+
+// eventServiceRef is an event service that references a shared event client.
+// Closing it releases the reference; the event client is closed once it isn't referenced.
+type eventServiceRef struct {
+	fab.EventService
+	once    sync.Once
+	release func()
+}
+
+// Close releases the reference to the event client
+func (ref *eventServiceRef) Close() {
+	ref.once.Do(ref.release)
 }
 
 // New creates a ChannelProvider based on a context
 func New(fabricProvider fab.InfraProvider) (*ChannelProvider, error) {
-	cp := ChannelProvider{fabricProvider: fabricProvider}
+	cp := ChannelProvider{
+		fabricProvider: fabricProvider,
+		eventClients:   make(map[string]*eventClientRef),
+	}
 	return &cp, nil
 }
 
+// Close closes the event clients of all channel services
+func (cp *ChannelProvider) Close() {
+	cp.eventClientsMu.Lock()
+	defer cp.eventClientsMu.Unlock()
+
+	for key, ref := range cp.eventClients {
+		ref.client.Close()
+		delete(cp.eventClients, key)
+	}
+}
+
+// eventService returns the event client of the given identity on the channel.
+// Event clients are shared by the channel services of the identity and connected on first use.
+// The returned event service should be closed once it's no longer used, which closes the event
+// client when no other event service references it.
+func (cp *ChannelProvider) eventService(ic fab.IdentityContext, channelID string) (fab.EventService, error) {
+	identity, err := ic.SerializedIdentity()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get serialized identity")
+	}
+	key := channelID + "_" + ic.MspID() + "_" + string(identity)
+
+	cp.eventClientsMu.Lock()
+	defer cp.eventClientsMu.Unlock()
+
+	ref, ok := cp.eventClients[key]
+	if !ok {
+		eventClient, err := cp.fabricProvider.CreateEventService(ic, channelID)
+		if err != nil {
+			return nil, err
+		}
+		ref = &eventClientRef{client: eventClient}
+		cp.eventClients[key] = ref
+	}
+	ref.refs++
+
+	return &eventServiceRef{
+		EventService: ref.client,
+		release:      func() { cp.releaseEventClient(key, ref) },
+	}, nil
+}
+
+// releaseEventClient releases a reference to the event client and closes the event client once it isn't referenced
+func (cp *ChannelProvider) releaseEventClient(key string, ref *eventClientRef) {
+	cp.eventClientsMu.Lock()
+	ref.refs--
+	if ref.refs > 0 || cp.eventClients[key] != ref {
+		cp.eventClientsMu.Unlock()
+		return
+	}
+	delete(cp.eventClients, key)
+	cp.eventClientsMu.Unlock()
+
+	ref.client.Close()
+}
+
 // ChannelService creates a ChannelService for an identity
 func (cp *ChannelProvider) ChannelService(ic fab.IdentityContext, channelID string) (fab.ChannelService, error) {
 
@@ -83,6 +164,11 @@ func (cs *ChannelService) EventHub() (fab.EventHub, error) {
 	return cs.fabricProvider.CreateEventHub(cs.identityContext, cs.cfg.Name())
 }
 
+// EventService returns the event service for the named channel.
+func (cs *ChannelService) EventService() (fab.EventService, error) {
+	return cs.provider.eventService(cs.identityContext, cs.cfg.Name())
+}
+
 // Config returns the Config for the named channel
 func (cs *ChannelService) Config() (fab.ChannelConfig, error) {
 	return cs.fabricProvider.CreateChannelConfig(cs.identityContext, cs.cfg.Name())
@@ -50,6 +50,94 @@ func TestBasicValidChannel(t *testing.T) {
 	assert.NotNil(t, m)
 }
 
+func TestEventServiceShared(t *testing.T) {
+	ctx := mocks.NewMockProviderContext()
+	pf := &MockProviderFactory{}
+
+	fp, err := pf.CreateFabricProvider(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error creating Fabric Provider: %v", err)
+	}
+
+	cp, err := New(fp)
+	if err != nil {
+		t.Fatalf("Unexpected error creating Channel Provider: %v", err)
+	}
+
+	user := mocks.NewMockUser("user")
+	channelService1, err := cp.ChannelService(user, "mychannel")
+	if err != nil {
+		t.Fatalf("Unexpected error creating Channel Service: %v", err)
+	}
+	channelService2, err := cp.ChannelService(user, "mychannel")
+	if err != nil {
+		t.Fatalf("Unexpected error creating Channel Service: %v", err)
+	}
+
+	es1, err := channelService1.EventService()
+	assert.Nil(t, err)
+	es2, err := channelService2.EventService()
+	assert.Nil(t, err)
+	assert.True(t, eventClient(es1) == eventClient(es2), "expected event client to be shared by the channel services of the identity")
+
+	otherUser := mocks.NewMockUserWithMSPID("otheruser", "Org2MSP")
+	channelService3, err := cp.ChannelService(otherUser, "mychannel")
+	if err != nil {
+		t.Fatalf("Unexpected error creating Channel Service: %v", err)
+	}
+	es3, err := channelService3.EventService()
+	assert.Nil(t, err)
+	assert.False(t, eventClient(es1) == eventClient(es3), "expected separate event client for another identity")
+
+	cp.Close()
+	assert.True(t, eventClient(es1).Closed, "expected event client to be closed")
+	assert.True(t, eventClient(es3).Closed, "expected event client to be closed")
+}
+
+func TestEventServiceRelease(t *testing.T) {
+	ctx := mocks.NewMockProviderContext()
+	pf := &MockProviderFactory{}
+
+	fp, err := pf.CreateFabricProvider(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error creating Fabric Provider: %v", err)
+	}
+
+	cp, err := New(fp)
+	if err != nil {
+		t.Fatalf("Unexpected error creating Channel Provider: %v", err)
+	}
+
+	user := mocks.NewMockUser("user")
+	channelService, err := cp.ChannelService(user, "mychannel")
+	if err != nil {
+		t.Fatalf("Unexpected error creating Channel Service: %v", err)
+	}
+
+	es1, err := channelService.EventService()
+	assert.Nil(t, err)
+	es2, err := channelService.EventService()
+	assert.Nil(t, err)
+
+	// Closing an event service twice releases it once
+	es1.(*eventServiceRef).Close()
+	es1.(*eventServiceRef).Close()
+	assert.False(t, eventClient(es1).Closed, "expected referenced event client to remain open")
+
+	es2.(*eventServiceRef).Close()
+	assert.True(t, eventClient(es2).Closed, "expected event client to be closed once released")
+
+	// A new event client is created once the previous one was released
+	es3, err := channelService.EventService()
+	assert.Nil(t, err)
+	assert.False(t, eventClient(es3) == eventClient(es1), "expected new event client")
+	assert.False(t, eventClient(es3).Closed)
+}
+
+func eventClient(es fab.EventService) *mocks.MockEventService {
+	return es.(*eventServiceRef).EventService.(*mocks.MockEventService)
+}
+
 // MockProviderFactory is configured to retrieve channel config from orderer
 type MockProviderFactory struct {
 	defcore.ProviderFactory
@@ -83,3 +171,8 @@ func (f *MockProviderFactory) CreateFabricProvider(context core.Providers) (fab.
 	}
 	return &cfp, nil
 }
+
+// CreateEventService returns a mock event service
+func (f *MockFabricProvider) CreateEventService(ic fab.IdentityContext, channelID string) (fab.EventClient, error) {
+	return mocks.NewMockEventService(), nil
+}
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package fabpvdr
 
 import (
+	"sync"
+
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context/api/fab"
@@ -14,16 +16,22 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/channel/membership"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/chconfig"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/endpoint"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/eventhubclient"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/orderer"
 	peerImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab/peer"
 	clientImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource/api"
+	"github.com/hyperledger/fabric-sdk-go/pkg/options"
 	"github.com/pkg/errors"
 )
 
 // FabricProvider represents the default implementation of Fabric objects.
 type FabricProvider struct {
 	providerContext core.Providers
+	// ordererSelections holds the orderer selection of each channel, shared by its transactors
+	ordererSelections sync.Map
 }
 
 type fabContext struct {
@@ -91,6 +99,91 @@ func (f *FabricProvider) CreateEventHub(ic fab.IdentityContext, channelID string
 	return events.FromConfig(eventCtx, &eventSource.PeerConfig)
 }
 
+// CreateEventService creates and connects the event client of the channel. The deliver client is used unless
+// the event hub client is selected with the client.eventService.type setting. Both clients only request
+// filtered block events, so that non-admin users are allowed to subscribe, unless full blocks are requested
+// with the client.eventService.blockEvents setting.
+func (f *FabricProvider) CreateEventService(ic fab.IdentityContext, channelID string) (fab.EventClient, error) {
+	clientConfig, err := f.providerContext.Config().Client()
+	if err != nil {
+		return nil, errors.WithMessage(err, "read configuration for client failed")
+	}
+
+	discoveryService, err := f.eventSourceDiscovery(ic, channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := &fabContext{
+		Providers: f.providerContext,
+		Identity:  ic,
+	}
+
+	var eventClient fab.EventClient
+	switch clientConfig.EventService.Type {
+	case core.DeliverEventServiceType, "":
+		var opts []options.Opt
+		if clientConfig.EventService.BlockEvents {
+			opts = append(opts, deliverclient.WithBlockEvents())
+		}
+		eventClient, err = deliverclient.New(ctx, channelID, discoveryService, opts...)
+	case core.EventHubEventServiceType:
+		var opts []options.Opt
+		if clientConfig.EventService.BlockEvents {
+			opts = append(opts, eventhubclient.WithBlockEvents())
+		}
+		eventClient, err = eventhubclient.New(ctx, channelID, discoveryService, opts...)
+	default:
+		return nil, errors.Errorf("unsupported event service type: %s", clientConfig.EventService.Type)
+	}
+	if err != nil {
+		return nil, errors.WithMessage(err, "event client creation failed")
+	}
+
+	if err := eventClient.Connect(); err != nil {
+		eventClient.Close()
+		return nil, errors.WithMessage(err, "event client connection failed")
+	}
+
+	return eventClient, nil
+}
+
+// eventSourceDiscovery returns a discovery service for the event source peers of the identity's organization
+func (f *FabricProvider) eventSourceDiscovery(ic fab.IdentityContext, channelID string) (fab.DiscoveryService, error) {
+	peerConfig, err := f.providerContext.Config().ChannelPeers(channelID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "read configuration for channel peers failed")
+	}
+
+	var peers []fab.Peer
+	for _, p := range peerConfig {
+		if !p.EventSource || p.MspID != ic.MspID() {
+			continue
+		}
+		eventEndpoint, err := endpoint.FromPeerConfig(f.providerContext.Config(), p.NetworkPeer)
+		if err != nil {
+			return nil, errors.WithMessage(err, "creating event endpoint failed")
+		}
+		peers = append(peers, eventEndpoint)
+	}
+
+	if len(peers) == 0 {
+		return nil, errors.New("unable to find event source for channel")
+	}
+
+	return &eventSourceDiscoveryService{peers: peers}, nil
+}
+
+// eventSourceDiscoveryService provides the event source peers of a channel to the event clients
+type eventSourceDiscoveryService struct {
+	peers []fab.Peer
+}
+
+// GetPeers returns the event source peers
+func (s *eventSourceDiscoveryService) GetPeers() ([]fab.Peer, error) {
+	return s.peers, nil
+}
+
 // CreateChannelConfig initializes the channel config
 func (f *FabricProvider) CreateChannelConfig(ic fab.IdentityContext, channelID string) (fab.ChannelConfig, error) {
 
@@ -115,7 +208,26 @@ func (f *FabricProvider) CreateChannelTransactor(ic fab.IdentityContext, cfg fab
 		Identity:  ic,
 	}
 
-	return channelImpl.NewTransactor(ctx, cfg)
+	selection, err := f.ordererSelection(cfg.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	return channelImpl.NewTransactor(ctx, cfg, channelImpl.WithOrdererSelection(selection))
+}
+
+// ordererSelection returns the orderer selection of the named channel
+func (f *FabricProvider) ordererSelection(channelID string) (*channelImpl.OrdererSelection, error) {
+	if v, ok := f.ordererSelections.Load(channelID); ok {
+		return v.(*channelImpl.OrdererSelection), nil
+	}
+
+	selection, err := channelImpl.NewOrdererSelection(f.providerContext.Config(), channelID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "creating orderer selection failed")
+	}
+	v, _ := f.ordererSelections.LoadOrStore(channelID, selection)
+	return v.(*channelImpl.OrdererSelection), nil
 }
 
 // CreatePeerFromConfig returns a new default implementation of Peer based configuration
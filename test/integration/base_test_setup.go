@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package integration
 
 import (
+	reqContext "context"
 	"os"
 	"path"
 	"testing"
@@ -233,7 +234,7 @@ func CreateAndSendTransactionProposal(transactor fab.ProposalSender, chainCodeID
 		return nil, nil, errors.WithMessage(err, "creating transaction proposal failed")
 	}
 
-	tpr, err := transactor.SendTransactionProposal(tp, targets)
+	tpr, err := transactor.SendTransactionProposal(reqContext.Background(), tp, targets)
 	return tpr, tp, err
 }
 
@@ -249,7 +250,7 @@ func CreateAndSendTransaction(transactor fab.Sender, proposal *fab.TransactionPr
 		return nil, errors.WithMessage(err, "CreateTransaction failed")
 	}
 
-	transactionResponse, err := transactor.SendTransaction(tx)
+	transactionResponse, err := transactor.SendTransaction(reqContext.Background(), tx)
 	if err != nil {
 		return nil, errors.WithMessage(err, "SendTransaction failed")
 